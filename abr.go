@@ -0,0 +1,94 @@
+package mpd
+
+import (
+	"time"
+)
+
+// ABRSegment is a single step of ABRTrack.
+type ABRSegment struct {
+	Duration time.Duration
+	// Bandwidth is in bits per second: calculated from the segment size if it's known, @bandwidth otherwise.
+	Bandwidth uint64
+	// Measured is true if Bandwidth is calculated from the segment size.
+	Measured bool
+}
+
+// ABRTrack is a time-ordered sequence of segments of a single Representation used by ABR simulators.
+type ABRTrack struct {
+	Period         string
+	AdaptationSet  int
+	Representation string
+	// Bandwidth is Representation@bandwidth.
+	Bandwidth uint64
+	Segments  []ABRSegment
+}
+
+// SegmentSizer returns size in bytes of media segment with given index of Representation in Period
+// (e.g. from parsed segment index), ok is false if size is unknown.
+type SegmentSizer func(period, representation string, index int) (size uint64, ok bool)
+
+// Subsegment is a single reference of the segment index ('sidx' box) of SegmentBase Representation.
+type Subsegment struct {
+	Duration time.Duration
+	Size     uint64
+}
+
+// SubsegmentIndexer returns subsegments of SegmentBase Representation in Period parsed from the segment index
+// at SegmentBase@indexRange, ok is false if index is unknown.
+type SubsegmentIndexer func(period, representation string) (subsegments []Subsegment, ok bool)
+
+// ABRTracks returns ABRTrack for every Representation. sizes can be nil, then @bandwidth is used for all segments.
+// SegmentBase Representation is a single segment covering the whole Period, sizes is called with index 0 for it;
+// use ABRTracksWithIndex to split it into subsegments.
+func (m *MPD) ABRTracks(sizes SegmentSizer) ([]ABRTrack, error) {
+	return m.ABRTracksWithIndex(sizes, nil)
+}
+
+// ABRTracksWithIndex is like ABRTracks, but SegmentBase Representations are split into subsegments returned by
+// index, which can be nil. Subsegment bandwidth is always calculated from its size.
+func (m *MPD) ABRTracksWithIndex(sizes SegmentSizer, index SubsegmentIndexer) ([]ABRTrack, error) {
+	timelines, err := m.Timelines()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]ABRTrack, 0, len(timelines))
+	for _, tl := range timelines {
+		track := ABRTrack{
+			Period:         tl.Period,
+			AdaptationSet:  tl.AdaptationSet,
+			Representation: tl.Representation,
+			Bandwidth:      tl.Bandwidth,
+			Segments:       make([]ABRSegment, 0, len(tl.Segments)),
+		}
+		if tl.segmentBase && index != nil {
+			if subsegments, ok := index(tl.Period, tl.Representation); ok {
+				for _, s := range subsegments {
+					segment := ABRSegment{Duration: s.Duration, Bandwidth: tl.Bandwidth}
+					if s.Duration > 0 {
+						segment.Bandwidth = uint64(float64(s.Size*8) / s.Duration.Seconds())
+						segment.Measured = true
+					}
+					track.Segments = append(track.Segments, segment)
+				}
+				res = append(res, track)
+				continue
+			}
+		}
+		for i, s := range tl.Segments {
+			segment := ABRSegment{
+				Duration:  time.Duration(s.Duration * float64(time.Second)),
+				Bandwidth: tl.Bandwidth,
+			}
+			if sizes != nil && s.Duration > 0 {
+				if size, ok := sizes(tl.Period, tl.Representation, i); ok {
+					segment.Bandwidth = uint64(float64(size*8) / s.Duration)
+					segment.Measured = true
+				}
+			}
+			track.Segments = append(track.Segments, segment)
+		}
+		res = append(res, track)
+	}
+	return res, nil
+}
@@ -0,0 +1,75 @@
+package mpd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestABRTracks(t *testing.T) {
+	m := decodeFixture(t, "fixture_elemental_delta_vod_multi_drm.mpd")
+	tracks, err := m.ABRTracks(nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, tracks)
+
+	track := tracks[0]
+	require.Equal(t, "1", track.Representation)
+	require.Equal(t, uint64(400000), track.Bandwidth)
+	require.Len(t, track.Segments, 18)
+	require.Equal(t, ABRSegment{Duration: 8 * time.Second, Bandwidth: 400000}, track.Segments[0])
+	require.Equal(t, ABRSegment{Duration: 680 * time.Millisecond, Bandwidth: 400000}, track.Segments[17])
+
+	sizes := func(period, representation string, index int) (uint64, bool) {
+		if representation == "1" && index == 1 {
+			return 500000, true
+		}
+		return 0, false
+	}
+	tracks, err = m.ABRTracks(sizes)
+	require.NoError(t, err)
+	require.Equal(t, ABRSegment{Duration: 8 * time.Second, Bandwidth: 400000}, tracks[0].Segments[0])
+	require.Equal(t, ABRSegment{Duration: 8 * time.Second, Bandwidth: 500000, Measured: true}, tracks[0].Segments[1])
+}
+
+func TestABRTracksSegmentListAndBase(t *testing.T) {
+	m := decodeFixture(t, "fixture_segment_list.mpd")
+	tracks, err := m.ABRTracks(nil)
+	require.NoError(t, err)
+	require.Len(t, tracks, 2)
+	require.Equal(t, "v1", tracks[0].Representation)
+	require.Len(t, tracks[0].Segments, 3)
+	require.Equal(t, ABRSegment{Duration: 4 * time.Second, Bandwidth: 800000}, tracks[0].Segments[0])
+	require.Equal(t, "a1", tracks[1].Representation)
+	require.Len(t, tracks[1].Segments, 3)
+
+	m = decodeFixture(t, "fixture_segment_base.mpd")
+	sizes := func(period, representation string, index int) (uint64, bool) {
+		if representation == "v2" && index == 0 {
+			return 632 * 300000, true
+		}
+		return 0, false
+	}
+	subsegments := func(period, representation string) ([]Subsegment, bool) {
+		if representation != "v1" {
+			return nil, false
+		}
+		return []Subsegment{
+			{Duration: 2 * time.Second, Size: 1000000},
+			{Duration: time.Second, Size: 750000},
+		}, true
+	}
+	tracks, err = m.ABRTracksWithIndex(sizes, subsegments)
+	require.NoError(t, err)
+	require.Len(t, tracks, 3)
+	require.Equal(t, []ABRSegment{
+		{Duration: 2 * time.Second, Bandwidth: 4000000, Measured: true},
+		{Duration: time.Second, Bandwidth: 6000000, Measured: true},
+	}, tracks[0].Segments)
+	require.Equal(t, []ABRSegment{{Duration: 632 * time.Second, Bandwidth: 2400000, Measured: true}}, tracks[1].Segments)
+	require.Len(t, tracks[2].Segments, 158)
+
+	tracks, err = m.ABRTracks(nil)
+	require.NoError(t, err)
+	require.Equal(t, []ABRSegment{{Duration: 632 * time.Second, Bandwidth: 4500000}}, tracks[0].Segments)
+}
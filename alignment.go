@@ -0,0 +1,84 @@
+package mpd
+
+import (
+	"fmt"
+	"time"
+)
+
+// segmentsRange returns presentation time range covered by SegmentTemplate segments, relative to the Period start.
+// periodDuration bounds @duration templates, see SegmentsWithin.
+func segmentsRange(st *SegmentTemplate, periodDuration time.Duration) (start, end time.Duration, ok bool) {
+	segments := st.SegmentsWithin(periodDuration)
+	if len(segments) == 0 {
+		return 0, 0, false
+	}
+	timescale := st.EffectiveTimescale()
+	pto := ticksToDuration(st.EffectivePresentationTimeOffset(), timescale)
+	first, last := segments[0], segments[len(segments)-1]
+	start = ticksToDuration(first.Time, timescale) - pto
+	end = ticksToDuration(last.Time+last.Duration, timescale) - pto
+	return start, end, true
+}
+
+// CheckPeriodAlignment checks that all Representations within each Period cover the same media time range:
+// first and last segment boundaries must be within tolerance from the first Representation ones.
+// The end of the last Period of dynamic MPD is not checked, since it's a live edge.
+func CheckPeriodAlignment(m *MPD, tolerance time.Duration) []Finding {
+	var res []Finding
+	for pi, p := range m.Period {
+		checkEnd := !m.IsDynamic() || pi < len(m.Period)-1
+		// unknown duration doesn't bound @duration templates
+		periodDuration, _, _ := m.PeriodDuration(pi)
+		var refPath string
+		var refStart, refEnd time.Duration
+		found := false
+
+		for ai, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			for ri := range as.Representations {
+				st := p.EffectiveSegmentTemplate(as, &as.Representations[ri])
+				if st == nil {
+					continue
+				}
+				start, end, ok := segmentsRange(st, periodDuration)
+				if !ok {
+					continue
+				}
+				path := representationPath(pi, ai, ri)
+				if !found {
+					refPath, refStart, refEnd, found = path, start, end, true
+					continue
+				}
+
+				if diff := absDuration(start - refStart); diff > tolerance {
+					res = append(res, Finding{
+						Severity: SeverityWarning,
+						Path:     path,
+						Message:  fmt.Sprintf("first segment starts at %s, %s differs by %s", start, refPath, diff),
+					})
+				}
+				if diff := absDuration(end - refEnd); checkEnd && diff > tolerance {
+					res = append(res, Finding{
+						Severity: SeverityWarning,
+						Path:     path,
+						Message:  fmt.Sprintf("last segment ends at %s, %s differs by %s", end, refPath, diff),
+					})
+				}
+			}
+		}
+	}
+	return res
+}
+
+func validatePeriodAlignment(m *MPD) []Finding {
+	return CheckPeriodAlignment(m, DefaultTolerance)
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,35 @@
+package mpd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckPeriodAlignment(t *testing.T) {
+	m := decodeFixture(t, "fixture_elemental_delta_vod_multi_drm.mpd")
+	require.Empty(t, CheckPeriodAlignment(m, DefaultTolerance))
+
+	as := m.Period[0].AdaptationSets[len(m.Period[0].AdaptationSets)-1]
+	st := as.Representations[0].SegmentTemplate
+	st.SegmentTimelineS = st.SegmentTimelineS[:len(st.SegmentTimelineS)-1]
+	last := &st.SegmentTimelineS[len(st.SegmentTimelineS)-1]
+	*last.R = *last.R - 1
+
+	findings := CheckPeriodAlignment(m, DefaultTolerance)
+	require.Len(t, findings, 1)
+	require.Contains(t, findings[0].Message, "last segment ends at")
+	require.Contains(t, findings[0].Message, "MPD/Period[0]/AdaptationSet[0]/Representation[0] differs by")
+	require.Equal(t, findings, CheckPeriodAlignment(m, time.Second))
+	require.Empty(t, CheckPeriodAlignment(m, time.Minute))
+
+	// live edge is not checked
+	m.Type = PresentationTypeOf(PresentationTypeDynamic)
+	require.Empty(t, CheckPeriodAlignment(m, DefaultTolerance))
+
+	*st.PresentationTimeOffset += 10 * st.EffectiveTimescale()
+	findings = CheckPeriodAlignment(m, DefaultTolerance)
+	require.Len(t, findings, 1)
+	require.Contains(t, findings[0].Message, "first segment starts at -")
+}
@@ -0,0 +1,221 @@
+package mpd
+
+import (
+	"math/rand"
+	"net/url"
+	"sort"
+)
+
+// BaseURLIterator iterates over BaseURL candidates of a Representation,
+// advancing to the next candidate after a failure.
+type BaseURLIterator struct {
+	candidates []*url.URL
+	i          int
+}
+
+// Current returns the current candidate, nil if all candidates failed.
+func (it *BaseURLIterator) Current() *url.URL {
+	if it.i >= len(it.candidates) {
+		return nil
+	}
+	return it.candidates[it.i]
+}
+
+// Fail marks the current candidate as failed and advances to the next one.
+// It returns false if there are no candidates left.
+func (it *BaseURLIterator) Fail() bool {
+	if it.i < len(it.candidates) {
+		it.i++
+	}
+	return it.i < len(it.candidates)
+}
+
+// Reset starts iteration from the first candidate again.
+func (it *BaseURLIterator) Reset() {
+	it.i = 0
+}
+
+// Len returns the number of candidates.
+func (it *BaseURLIterator) Len() int {
+	return len(it.candidates)
+}
+
+// representationParents returns Period and AdaptationSet containing r, nils if r doesn't belong to MPD.
+func (m *MPD) representationParents(r *Representation) (*Period, *AdaptationSet) {
+	for pi := range m.Period {
+		p := &m.Period[pi]
+		for _, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			for ri := range as.Representations {
+				if &as.Representations[ri] == r {
+					return p, as
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// baseURLLevels returns BaseURL values from MPD to Representation level, in document order of levels.
+// p and as can be nil. BaseURLs of every level are ordered by order, document order is kept if it's nil.
+// Levels without BaseURLs are skipped.
+func (m *MPD) baseURLLevels(p *Period, as *AdaptationSet, r *Representation, order func([]BaseURL) []BaseURL) [][]string {
+	levels := [][]BaseURL{m.BaseURL}
+	if p != nil {
+		levels = append(levels, p.BaseURL)
+	}
+	if as != nil {
+		levels = append(levels, as.BaseURL)
+	}
+	levels = append(levels, r.BaseURL)
+
+	var res [][]string
+	for _, level := range levels {
+		if order != nil {
+			level = order(level)
+		}
+		var refs []string
+		for _, b := range level {
+			if b.Value != "" {
+				refs = append(refs, b.Value)
+			}
+		}
+		if len(refs) > 0 {
+			res = append(res, refs)
+		}
+	}
+	return res
+}
+
+// orderBaseURLs returns BaseURLs ordered by @dvb:priority, lower value first. BaseURLs with the same priority
+// are ordered by weighted random selection according to @dvb:weight, as described in DVB-DASH 10.8.2.1.
+// Absent priority and weight are 1, BaseURLs with zero weight go last in document order.
+// rnd can be nil, then the default math/rand source is used.
+func orderBaseURLs(level []BaseURL, rnd *rand.Rand) []BaseURL {
+	if len(level) < 2 {
+		return level
+	}
+	priority := func(b BaseURL) uint64 {
+		if b.DVBPriority == nil {
+			return 1
+		}
+		return *b.DVBPriority
+	}
+	weight := func(b BaseURL) uint64 {
+		if b.DVBWeight == nil {
+			return 1
+		}
+		return *b.DVBWeight
+	}
+	random := rand.Int63n
+	if rnd != nil {
+		random = rnd.Int63n
+	}
+
+	sorted := make([]BaseURL, len(level))
+	copy(sorted, level)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return priority(sorted[i]) < priority(sorted[j])
+	})
+
+	res := make([]BaseURL, 0, len(sorted))
+	for start := 0; start < len(sorted); {
+		end := start + 1
+		for end < len(sorted) && priority(sorted[end]) == priority(sorted[start]) {
+			end++
+		}
+		group := sorted[start:end]
+		for len(group) > 0 {
+			var total uint64
+			for _, b := range group {
+				total += weight(b)
+			}
+			if total == 0 {
+				res = append(res, group...)
+				break
+			}
+			x := uint64(random(int64(total)))
+			i := 0
+			for ; x >= weight(group[i]); i++ {
+				x -= weight(group[i])
+			}
+			res = append(res, group[i])
+			group = append(group[:i:i], group[i+1:]...)
+		}
+		start = end
+	}
+	return res
+}
+
+// BaseURLCandidates returns all BaseURL candidates of Representation resolved against manifestURL (can be empty),
+// in document order. Every combination of BaseURLs from each hierarchy level is a candidate.
+// Period and AdaptationSet levels are taken into account only if r points into MPD.
+// Result is empty if there is nothing to resolve.
+func (m *MPD) BaseURLCandidates(manifestURL string, r *Representation) ([]*url.URL, error) {
+	p, as := m.representationParents(r)
+	return resolveBaseURLLevels(manifestURL, m.baseURLLevels(p, as, r, nil))
+}
+
+// resolveBaseURLLevels resolves every combination of references from each level against manifestURL.
+func resolveBaseURLLevels(manifestURL string, levels [][]string) ([]*url.URL, error) {
+	var bases []*url.URL
+	if manifestURL != "" {
+		u, err := url.Parse(manifestURL)
+		if err != nil {
+			return nil, err
+		}
+		bases = []*url.URL{u}
+	}
+
+	for _, level := range levels {
+		var next []*url.URL
+		if bases == nil {
+			bases = []*url.URL{nil}
+		}
+		for _, base := range bases {
+			for _, ref := range level {
+				u, err := resolveURL(base, ref)
+				if err != nil {
+					return nil, err
+				}
+				next = append(next, u)
+			}
+		}
+		bases = next
+	}
+	return bases, nil
+}
+
+// NewBaseURLIterator returns BaseURLIterator over BaseURLCandidates. Unlike BaseURLCandidates, BaseURLs of every
+// level are ordered by @dvb:priority and, within the same priority, picked randomly according to @dvb:weight.
+func (m *MPD) NewBaseURLIterator(manifestURL string, r *Representation) (*BaseURLIterator, error) {
+	return m.NewBaseURLIteratorWithRand(manifestURL, r, nil)
+}
+
+// NewBaseURLIteratorWithRand is like NewBaseURLIterator, but uses rnd for weighted selection.
+// rnd can be nil, then the default math/rand source is used.
+func (m *MPD) NewBaseURLIteratorWithRand(manifestURL string, r *Representation, rnd *rand.Rand) (*BaseURLIterator, error) {
+	p, as := m.representationParents(r)
+	order := func(level []BaseURL) []BaseURL { return orderBaseURLs(level, rnd) }
+	candidates, err := resolveBaseURLLevels(manifestURL, m.baseURLLevels(p, as, r, order))
+	if err != nil {
+		return nil, err
+	}
+	return &BaseURLIterator{candidates: candidates}, nil
+}
+
+// representationBaseURL returns the first BaseURL candidate in document order of Representation r of
+// AdaptationSet as within Period p, nil if there is nothing to resolve against.
+func (m *MPD) representationBaseURL(manifestURL string, p *Period, as *AdaptationSet, r *Representation) (*url.URL, error) {
+	levels := m.baseURLLevels(p, as, r, nil)
+	for i := range levels {
+		levels[i] = levels[i][:1]
+	}
+	candidates, err := resolveBaseURLLevels(manifestURL, levels)
+	if err != nil || len(candidates) == 0 {
+		return nil, err
+	}
+	return candidates[0], nil
+}
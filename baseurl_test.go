@@ -0,0 +1,109 @@
+package mpd
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseURLIterator(t *testing.T) {
+	m := decodeFixture(t, "fixture_vod_with_base_url.mpd")
+	r := &m.Period[0].AdaptationSets[0].Representations[0]
+	r.BaseURL = []BaseURL{{Value: "video/"}}
+
+	it, err := m.NewBaseURLIterator("http://origin/manifest.mpd", r)
+	require.NoError(t, err)
+	require.Equal(t, 1, it.Len())
+	require.Equal(t, "https://video-1-2/video/", it.Current().String())
+	require.False(t, it.Fail())
+	require.Nil(t, it.Current())
+	require.False(t, it.Fail())
+
+	it.Reset()
+	require.Equal(t, "https://video-1-2/video/", it.Current().String())
+
+	m.BaseURL = nil
+	it, err = m.NewBaseURLIterator("http://origin/live/manifest.mpd", r)
+	require.NoError(t, err)
+	require.Equal(t, "http://origin/live/video/", it.Current().String())
+
+	candidates, err := m.BaseURLCandidates("", &Representation{})
+	require.NoError(t, err)
+	require.Empty(t, candidates)
+
+	m = decodeFixture(t, "fixture_base_url_attributes.mpd")
+	r = &m.Period[0].AdaptationSets[0].Representations[0]
+	candidates, err = m.BaseURLCandidates("", r)
+	require.NoError(t, err)
+	var urls []string
+	for _, u := range candidates {
+		urls = append(urls, u.String())
+	}
+	require.Equal(t, []string{
+		"https://cdn1.example.com/live/video/hd/v1/",
+		"https://cdn2.example.com/live/video/hd/v1/",
+	}, urls)
+
+	// a copy of Representation doesn't point into MPD
+	copied := *r
+	candidates, err = m.BaseURLCandidates("", &copied)
+	require.NoError(t, err)
+	require.Len(t, candidates, 2)
+	require.Equal(t, "https://cdn1.example.com/live/v1/", candidates[0].String())
+}
+
+func TestBaseURLIteratorDVBPriority(t *testing.T) {
+	m := &MPD{BaseURL: []BaseURL{
+		{Value: "https://backup/", DVBPriority: uint64Ptr(2)},
+		{Value: "https://cdn1/", DVBPriority: uint64Ptr(1), DVBWeight: uint64Ptr(3)},
+		{Value: "https://disabled/", DVBPriority: uint64Ptr(1), DVBWeight: uint64Ptr(0)},
+		{Value: "https://cdn2/", DVBPriority: uint64Ptr(1), DVBWeight: uint64Ptr(1)},
+	}}
+	r := &Representation{BaseURL: []BaseURL{{Value: "v1/"}}}
+
+	rnd := rand.New(rand.NewSource(1))
+	first := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		it, err := m.NewBaseURLIteratorWithRand("", r, rnd)
+		require.NoError(t, err)
+		var candidates []string
+		for it.Current() != nil {
+			candidates = append(candidates, it.Current().String())
+			it.Fail()
+		}
+		require.Len(t, candidates, 4)
+		// cdn1 and cdn2 are picked by weight, zero weight and lower priority go last
+		require.ElementsMatch(t, []string{"https://cdn1/v1/", "https://cdn2/v1/"}, candidates[:2])
+		require.Equal(t, "https://disabled/v1/", candidates[2])
+		require.Equal(t, "https://backup/v1/", candidates[3])
+		first[candidates[0]]++
+	}
+	require.InDelta(t, 750, first["https://cdn1/v1/"], 50)
+	require.InDelta(t, 250, first["https://cdn2/v1/"], 50)
+
+	// candidates and segment URLs are resolved in document order
+	candidates, err := m.BaseURLCandidates("", r)
+	require.NoError(t, err)
+	require.Equal(t, "https://backup/v1/", candidates[0].String())
+	m.Period = []Period{{AdaptationSets: []*AdaptationSet{{Representations: []Representation{{
+		ID:              stringPtr("v1"),
+		BaseURL:         r.BaseURL,
+		SegmentTemplate: &SegmentTemplate{Initialization: stringPtr("init.mp4")},
+	}}}}}}
+	m.BaseURL[0].DVBPriority = uint64Ptr(1)
+	for i := 0; i < 10; i++ {
+		urls, err := m.SegmentURLs("")
+		require.NoError(t, err)
+		require.Equal(t, "https://backup/v1/init.mp4", urls[0].URL)
+	}
+
+	// fixture BaseURLs have different priorities, so the order is deterministic
+	m = decodeFixture(t, "fixture_base_url_attributes.mpd")
+	m.BaseURL[0].DVBPriority, m.BaseURL[1].DVBPriority = m.BaseURL[1].DVBPriority, m.BaseURL[0].DVBPriority
+	it, err := m.NewBaseURLIterator("", &m.Period[0].AdaptationSets[0].Representations[0])
+	require.NoError(t, err)
+	require.Equal(t, "https://cdn2.example.com/live/video/hd/v1/", it.Current().String())
+	require.True(t, it.Fail())
+	require.Equal(t, "https://cdn1.example.com/live/video/hd/v1/", it.Current().String())
+}
@@ -0,0 +1,81 @@
+package mpd
+
+import (
+	"encoding/xml"
+	"sort"
+	"strings"
+)
+
+// WithCanonical makes output byte-stable regardless of how MPD was populated: extension attributes
+// are written after namespace declarations sorted by name, and Representations of every AdaptationSet
+// are sorted by bandwidth and then by id. Modeled attributes and elements are always written in XSD order.
+// MPD itself is not modified.
+func WithCanonical() EncodeOption {
+	return func(o *encodeOptions) { o.canonical = true }
+}
+
+// canonicalize applies WithCanonical ordering to the marshal copy of MPD.
+func canonicalize(m *mpdMarshal) {
+	sortAttrs(m.ExtensionAttrs)
+	sortExtensionAttrs(m.Extensions)
+	for pi := range m.Period {
+		p := &m.Period[pi]
+		sortAttrs(p.ExtensionAttrs)
+		sortExtensionAttrs(p.Extensions)
+		for _, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			sortAttrs(as.ExtensionAttrs)
+			sortExtensionAttrs(as.Extensions)
+			sort.SliceStable(as.Representations, func(i, j int) bool {
+				return representationLess(&as.Representations[i], &as.Representations[j])
+			})
+			for ri := range as.Representations {
+				sortAttrs(as.Representations[ri].ExtensionAttrs)
+				sortExtensionAttrs(as.Representations[ri].Extensions)
+			}
+		}
+	}
+}
+
+// representationLess orders Representations by bandwidth and then by id, missing values go last.
+func representationLess(a, b *representationMarshal) bool {
+	switch {
+	case a.Bandwidth != nil && b.Bandwidth != nil && *a.Bandwidth != *b.Bandwidth:
+		return *a.Bandwidth < *b.Bandwidth
+	case (a.Bandwidth == nil) != (b.Bandwidth == nil):
+		return a.Bandwidth != nil
+	case a.ID != nil && b.ID != nil:
+		return *a.ID < *b.ID
+	default:
+		return a.ID != nil && b.ID == nil
+	}
+}
+
+// sortAttrs sorts attributes with prefixed names as produced by marshalAttrs: the default namespace
+// declaration first, then prefixes declarations and other attributes, each group sorted by name.
+func sortAttrs(attrs []xml.Attr) {
+	group := func(a xml.Attr) int {
+		switch {
+		case a.Name.Local == "xmlns":
+			return 0
+		case strings.HasPrefix(a.Name.Local, "xmlns:"):
+			return 1
+		default:
+			return 2
+		}
+	}
+	sort.SliceStable(attrs, func(i, j int) bool {
+		if gi, gj := group(attrs[i]), group(attrs[j]); gi != gj {
+			return gi < gj
+		}
+		return attrs[i].Name.Local < attrs[j].Name.Local
+	})
+}
+
+func sortExtensionAttrs(es []extensionMarshal) {
+	for i := range es {
+		sortAttrs(es[i].Attrs)
+	}
+}
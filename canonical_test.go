@@ -0,0 +1,62 @@
+package mpd
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeCanonical(t *testing.T) {
+	build := func(reversed bool) *MPD {
+		attrs := []xml.Attr{
+			{Name: xml.Name{Space: "urn:vendor", Local: "b"}, Value: "2"},
+			{Name: xml.Name{Space: "urn:vendor", Local: "a"}, Value: "1"},
+			{Name: xml.Name{Space: "xmlns", Local: "v"}, Value: "urn:vendor"},
+		}
+		reps := []Representation{
+			{ID: stringPtr("b"), Bandwidth: uint64Ptr(2000)},
+			{ID: stringPtr("none")},
+			{ID: stringPtr("c"), Bandwidth: uint64Ptr(1000)},
+			{ID: stringPtr("a"), Bandwidth: uint64Ptr(2000)},
+		}
+		if reversed {
+			for i, j := 0, len(attrs)-1; i < j; i, j = i+1, j-1 {
+				attrs[i], attrs[j] = attrs[j], attrs[i]
+			}
+			for i, j := 0, len(reps)-1; i < j; i, j = i+1, j-1 {
+				reps[i], reps[j] = reps[j], reps[i]
+			}
+		}
+		return &MPD{
+			XMLNS:          stringPtr(DASHNamespace),
+			Profiles:       ProfileISOFFLive,
+			ExtensionAttrs: attrs,
+			Period:         []Period{{AdaptationSets: []*AdaptationSet{{MimeType: "video/mp4", Representations: reps}}}},
+		}
+	}
+
+	a, err := build(false).Encode(WithCanonical())
+	require.NoError(t, err)
+	b, err := build(true).Encode(WithCanonical())
+	require.NoError(t, err)
+	require.Equal(t, string(a), string(b))
+	require.Equal(t, `<?xml version="1.0" encoding="utf-8"?>
+<MPD xmlns="urn:mpeg:dash:schema:mpd:2011" profiles="urn:mpeg:dash:profile:isoff-live:2011" xmlns:v="urn:vendor" v:a="1" v:b="2">
+  <Period>
+    <AdaptationSet mimeType="video/mp4">
+      <Representation id="c" bandwidth="1000"/>
+      <Representation id="a" bandwidth="2000"/>
+      <Representation id="b" bandwidth="2000"/>
+      <Representation id="none"/>
+    </AdaptationSet>
+  </Period>
+</MPD>
+`, string(a))
+
+	// MPD is not modified
+	m := build(false)
+	_, err = m.Encode(WithCanonical())
+	require.NoError(t, err)
+	require.Equal(t, build(false), m)
+}
@@ -0,0 +1,261 @@
+package mpd
+
+import (
+	"strconv"
+
+	"github.com/mc2soft/mpd/codecs"
+)
+
+// Video codec families understood by DecoderCapabilities.
+const (
+	CodecFamilyAVC  = "avc"
+	CodecFamilyHEVC = "hevc"
+	CodecFamilyAV1  = "av1"
+)
+
+// CodecCapability describes decoder support of a single codec family.
+type CodecCapability struct {
+	// Family is CodecFamilyAVC, CodecFamilyHEVC, CodecFamilyAV1 or sample entry code of other codecs (e.g. "mp4a").
+	Family string
+	// Profiles lists supported profiles: profile_idc for AVC, general_profile_idc for HEVC, seq_profile for AV1.
+	// Empty list means any profile.
+	Profiles []int
+	// MaxLevel is the maximal supported level: level_idc for AVC (e.g. 41), general_level_idc for HEVC
+	// (e.g. 123 for level 4.1), seq_level_idx for AV1. 0 means any level.
+	MaxLevel int
+}
+
+// DecoderCapabilities describes what a device can decode.
+type DecoderCapabilities struct {
+	// Codecs limits supported codecs of listed families, codecs of other families are supported
+	// unless OnlyListedCodecs is set. Empty list means any codecs.
+	Codecs []CodecCapability
+	// OnlyListedCodecs rejects codecs of families absent from Codecs.
+	OnlyListedCodecs bool
+	MaxWidth         uint64
+	MaxHeight        uint64
+	MaxFrameRate     float64
+}
+
+// codecInfo is codec family, profile and level parsed from a single codecs string entry.
+type codecInfo struct {
+	family  string
+	profile int
+	level   int
+}
+
+// parseCodecInfo parses RFC 6381 codecs entry, profile and level are taken for AVC, HEVC and AV1 only.
+func parseCodecInfo(codec string) (codecInfo, error) {
+	c, err := codecs.Parse(codec)
+	if err != nil {
+		return codecInfo{}, err
+	}
+	return newCodecInfo(c), nil
+}
+
+func newCodecInfo(c codecs.Codec) codecInfo {
+	switch c := c.(type) {
+	case codecs.AVC:
+		return codecInfo{family: CodecFamilyAVC, profile: int(c.Profile), level: int(c.Level)}
+	case codecs.HEVC:
+		return codecInfo{family: CodecFamilyHEVC, profile: int(c.Profile), level: int(c.Level)}
+	case codecs.AV1:
+		return codecInfo{family: CodecFamilyAV1, profile: int(c.Profile), level: int(c.Level)}
+	}
+	return codecInfo{family: c.SampleEntry()}
+}
+
+// supportsCodecs checks every entry of comma-separated codecs list.
+func (caps DecoderCapabilities) supportsCodecs(list string) bool {
+	if len(caps.Codecs) == 0 || list == "" {
+		return true
+	}
+	cs, err := codecs.ParseList(list)
+	if err != nil {
+		return false
+	}
+	for _, c := range cs {
+		if !caps.supportsCodec(newCodecInfo(c)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (caps DecoderCapabilities) supportsCodec(info codecInfo) bool {
+	listed := false
+	for _, c := range caps.Codecs {
+		if c.Family != info.family {
+			continue
+		}
+		listed = true
+		if c.MaxLevel > 0 && info.level > c.MaxLevel {
+			continue
+		}
+		if len(c.Profiles) == 0 {
+			return true
+		}
+		for _, p := range c.Profiles {
+			if p == info.profile {
+				return true
+			}
+		}
+	}
+	return !listed && !caps.OnlyListedCodecs
+}
+
+// Supports reports whether Representation of AdaptationSet can be decoded. AdaptationSet @maxWidth,
+// @maxHeight and @maxFrameRate are checked for Representations without @width, @height and @frameRate.
+func (caps DecoderCapabilities) Supports(as *AdaptationSet, r *Representation) bool {
+	codecs := stringValue(r.Codecs)
+	if codecs == "" {
+		codecs = stringValue(as.Codecs)
+	}
+	if !caps.supportsCodecs(codecs) {
+		return false
+	}
+	// AdaptationSet maximums are the best known limits of Representations which don't set their own values
+	width, height, frameRate := r.Width, r.Height, r.FrameRate
+	if width == nil {
+		width = as.MaxWidth
+	}
+	if height == nil {
+		height = as.MaxHeight
+	}
+	if frameRate == nil {
+		frameRate = as.MaxFrameRate
+	}
+	if caps.MaxWidth > 0 && width != nil && *width > caps.MaxWidth {
+		return false
+	}
+	if caps.MaxHeight > 0 && height != nil && *height > caps.MaxHeight {
+		return false
+	}
+	if caps.MaxFrameRate > 0 && frameRate != nil {
+		fr, err := frameRate.Float()
+		if err != nil || fr > caps.MaxFrameRate {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterRepresentations removes Representations which can't be decoded with given capabilities, including ones
+// with dependencyId referring to removed Representations. AdaptationSets emptied by filtering are removed too,
+// AdaptationSets which had no Representations (e.g. remote ones) are kept. Subset@contains and associationId
+// references to removed elements are dropped, as well as Preselections with removed components.
+// It returns the number of removed Representations.
+func (m *MPD) FilterRepresentations(caps DecoderCapabilities) int {
+	var removed int
+	for pi := range m.Period {
+		p := &m.Period[pi]
+		removedReps := map[string]bool{}
+		supported := func(as *AdaptationSet, r *Representation) bool {
+			for _, id := range r.DependencyID {
+				if removedReps[id] {
+					return false
+				}
+			}
+			return caps.Supports(as, r)
+		}
+
+		emptied := map[*AdaptationSet]bool{}
+		// dependent Representation may precede the removed one, so filtering is repeated until nothing is removed
+		for changed := true; changed; {
+			changed = false
+			for _, as := range p.AdaptationSets {
+				if as == nil || len(as.Representations) == 0 {
+					continue
+				}
+				reps := as.Representations[:0]
+				for ri := range as.Representations {
+					r := as.Representations[ri]
+					if supported(as, &r) {
+						reps = append(reps, r)
+						continue
+					}
+					if r.ID != nil {
+						removedReps[*r.ID] = true
+					}
+					removed++
+					changed = true
+				}
+				as.Representations = reps
+				if len(reps) == 0 {
+					emptied[as] = true
+				}
+			}
+		}
+		if len(removedReps) == 0 && len(emptied) == 0 {
+			continue
+		}
+
+		removedSets := map[string]bool{}
+		sets := p.AdaptationSets[:0]
+		for _, as := range p.AdaptationSets {
+			if emptied[as] {
+				if as.ID != nil {
+					removedSets[*as.ID] = true
+				}
+				continue
+			}
+			sets = append(sets, as)
+		}
+		p.AdaptationSets = sets
+		p.dropReferences(removedSets, removedReps)
+	}
+	return removed
+}
+
+// dropReferences removes references to AdaptationSets and Representations with given removed ids.
+func (p *Period) dropReferences(sets, reps map[string]bool) {
+	for _, as := range p.AdaptationSets {
+		if as == nil {
+			continue
+		}
+		for ri := range as.Representations {
+			r := &as.Representations[ri]
+			var association StringVector
+			for _, id := range r.AssociationID {
+				if !reps[id] {
+					association = append(association, id)
+				}
+			}
+			if len(association) < len(r.AssociationID) {
+				r.AssociationID = association
+			}
+		}
+	}
+
+	subsets := p.Subsets[:0]
+	for _, ss := range p.Subsets {
+		var contains UIntVector
+		for _, id := range ss.Contains {
+			if !sets[strconv.FormatUint(id, 10)] {
+				contains = append(contains, id)
+			}
+		}
+		if len(contains) == 0 {
+			continue
+		}
+		ss.Contains = contains
+		subsets = append(subsets, ss)
+	}
+	p.Subsets = subsets
+
+	// Preselection can't be played without any of its components
+	preselections := p.Preselections[:0]
+	for _, ps := range p.Preselections {
+		complete := true
+		for _, id := range ps.PreselectionComponents {
+			if sets[id] {
+				complete = false
+				break
+			}
+		}
+		if complete {
+			preselections = append(preselections, ps)
+		}
+	}
+	p.Preselections = preselections
+}
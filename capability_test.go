@@ -0,0 +1,134 @@
+package mpd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCodecInfo(t *testing.T) {
+	for codec, expected := range map[string]codecInfo{
+		"avc1.64001f":      {CodecFamilyAVC, 100, 31},
+		"avc3.4D401E":      {CodecFamilyAVC, 77, 30},
+		"hvc1.2.4.L153.B0": {CodecFamilyHEVC, 2, 153},
+		"hev1.A1.6.H120":   {CodecFamilyHEVC, 1, 120},
+		"av01.0.08M.10":    {CodecFamilyAV1, 0, 8},
+		"mp4a.40.2":        {"mp4a", 0, 0},
+		" ec-3":            {"ec-3", 0, 0},
+	} {
+		info, err := parseCodecInfo(codec)
+		require.NoError(t, err, codec)
+		require.Equal(t, expected, info, codec)
+	}
+
+	for _, codec := range []string{"avc1", "avc1.64001", "avc1.zz001f", "hvc1.1.6", "hvc1.1.6.X93", "av01.0.8M"} {
+		_, err := parseCodecInfo(codec)
+		require.Error(t, err, codec)
+	}
+}
+
+func TestFilterRepresentations(t *testing.T) {
+	m := decodeFixture(t, "fixture_flussonic_live.mpd")
+	caps := DecoderCapabilities{
+		Codecs: []CodecCapability{
+			{Family: CodecFamilyAVC, Profiles: []int{77}, MaxLevel: 30},
+			{Family: "mp4a"},
+		},
+		MaxWidth: 640,
+	}
+	require.Equal(t, 1, m.FilterRepresentations(caps))
+	var ids []string
+	for _, r := range m.Period[0].AdaptationSets[0].Representations {
+		ids = append(ids, *r.ID)
+	}
+	require.Equal(t, []string{"tracks-v1", "tracks-v2", "tracks-v3"}, ids)
+	require.Len(t, m.Period[0].AdaptationSets[1].Representations, 1)
+
+	caps.MaxFrameRate = 24
+	require.Equal(t, 3, m.FilterRepresentations(caps))
+	require.Len(t, m.Period[0].AdaptationSets, 1)
+	require.Equal(t, "audio/mp4", m.Period[0].AdaptationSets[0].MimeType)
+
+	// codecs of families absent from the list are supported by default
+	caps.Codecs = []CodecCapability{{Family: CodecFamilyHEVC}}
+	require.Equal(t, 0, m.FilterRepresentations(caps))
+	require.Len(t, m.Period[0].AdaptationSets, 1)
+
+	caps.OnlyListedCodecs = true
+	require.Equal(t, 1, m.FilterRepresentations(caps))
+	require.Empty(t, m.Period[0].AdaptationSets)
+}
+
+func TestFilterRepresentationsReferences(t *testing.T) {
+	video := func(id string, codecs string, deps ...string) Representation {
+		return Representation{ID: stringPtr(id), Codecs: stringPtr(codecs), DependencyID: deps}
+	}
+	m := &MPD{Period: []Period{{
+		AdaptationSets: []*AdaptationSet{
+			{ID: stringPtr("1"), MimeType: "video/mp4", Representations: []Representation{
+				// enhancement layer depends on the removed base layer
+				video("v-enh", "avc1.64001f", "v-hevc"),
+				video("v-hevc", "hvc1.1.6.L93.B0"),
+				video("v-avc", "avc1.64001f"),
+			}},
+			{ID: stringPtr("2"), MimeType: "video/mp4", Representations: []Representation{
+				video("v2", "hvc1.1.6.L93.B0"),
+			}},
+			{ID: stringPtr("3"), MimeType: "audio/mp4", Representations: []Representation{{
+				ID: stringPtr("a1"), Codecs: stringPtr("mp4a.40.2"), AssociationID: StringVector{"v2", "v-avc"},
+			}}},
+			// remote AdaptationSet has no Representations until it's resolved
+			{ID: stringPtr("4"), XLinkHref: stringPtr("https://example.com/as.xml")},
+		},
+		Subsets: []Subset{{Contains: UIntVector{1, 2}}, {Contains: UIntVector{2}}, {Contains: UIntVector{3, 4}}},
+		Preselections: []Preselection{
+			{ID: stringPtr("p1"), PreselectionComponents: StringVector{"3", "2"}},
+			{ID: stringPtr("p2"), PreselectionComponents: StringVector{"3", "4"}},
+		},
+	}}}
+	caps := DecoderCapabilities{
+		Codecs:           []CodecCapability{{Family: CodecFamilyAVC}, {Family: "mp4a"}},
+		OnlyListedCodecs: true,
+	}
+	require.Equal(t, 3, m.FilterRepresentations(caps))
+
+	p := m.Period[0]
+	var ids []string
+	for _, as := range p.AdaptationSets {
+		ids = append(ids, *as.ID)
+	}
+	require.Equal(t, []string{"1", "3", "4"}, ids)
+	require.Len(t, p.AdaptationSets[0].Representations, 1)
+	require.Equal(t, "v-avc", *p.AdaptationSets[0].Representations[0].ID)
+	require.Equal(t, StringVector{"v-avc"}, p.AdaptationSets[1].Representations[0].AssociationID)
+	require.Equal(t, []Subset{{Contains: UIntVector{1}}, {Contains: UIntVector{3, 4}}}, p.Subsets)
+	require.Len(t, p.Preselections, 1)
+	require.Equal(t, "p2", *p.Preselections[0].ID)
+
+	b, err := m.Encode()
+	require.NoError(t, err)
+	require.Contains(t, string(b), `xlink:href="https://example.com/as.xml"/>`)
+}
+
+func TestSupportsAdaptationSetLimits(t *testing.T) {
+	fr := FrameRate("60")
+	as := &AdaptationSet{MaxWidth: uint64Ptr(1920), MaxHeight: uint64Ptr(1080), MaxFrameRate: &fr}
+	r := &Representation{Codecs: stringPtr("avc1.64001f")}
+	caps := DecoderCapabilities{MaxWidth: 1280}
+	require.False(t, caps.Supports(as, r))
+
+	// Representation values take precedence
+	r.Width = uint64Ptr(1280)
+	require.True(t, caps.Supports(as, r))
+
+	caps = DecoderCapabilities{MaxHeight: 720}
+	require.False(t, caps.Supports(as, r))
+	r.Height = uint64Ptr(720)
+	require.True(t, caps.Supports(as, r))
+
+	caps = DecoderCapabilities{MaxFrameRate: 30}
+	require.False(t, caps.Supports(as, r))
+	rfr := FrameRate("30000/1001")
+	r.FrameRate = &rfr
+	require.True(t, caps.Supports(as, r))
+}
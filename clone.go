@@ -0,0 +1,660 @@
+package mpd
+
+import (
+	"encoding/xml"
+
+	copyobj "github.com/mc2soft/mpd/utils"
+)
+
+// Clone returns a deep copy of MPD: modifying the copy doesn't affect the original and vice versa.
+func (m *MPD) Clone() *MPD {
+	return cloneMPD(m)
+}
+
+// Clone returns a deep copy of Period.
+func (p *Period) Clone() *Period {
+	return clonePeriod(p)
+}
+
+// Clone returns a deep copy of AdaptationSet.
+func (as *AdaptationSet) Clone() *AdaptationSet {
+	return cloneAdaptationSet(as)
+}
+
+// Clone returns a deep copy of Representation.
+func (r *Representation) Clone() *Representation {
+	return cloneRepresentation(r)
+}
+
+func cloneMPD(m *MPD) *MPD {
+	if m == nil {
+		return nil
+	}
+	return &MPD{
+		XMLName:                    m.XMLName,
+		XMLNS:                      copyobj.String(m.XMLNS),
+		Type:                       copyPresentationType(m.Type),
+		MinimumUpdatePeriod:        copyobj.String(m.MinimumUpdatePeriod),
+		AvailabilityStartTime:      copyDateTime(m.AvailabilityStartTime),
+		AvailabilityEndTime:        copyDateTime(m.AvailabilityEndTime),
+		MediaPresentationDuration:  copyobj.String(m.MediaPresentationDuration),
+		MinBufferTime:              copyobj.String(m.MinBufferTime),
+		SuggestedPresentationDelay: copyobj.String(m.SuggestedPresentationDelay),
+		TimeShiftBufferDepth:       copyobj.String(m.TimeShiftBufferDepth),
+		PublishTime:                copyDateTime(m.PublishTime),
+		Profiles:                   m.Profiles,
+		XSI:                        copyobj.String(m.XSI),
+		SCTE35:                     copyobj.String(m.SCTE35),
+		DVB:                        copyobj.String(m.DVB),
+		XLink:                      copyobj.String(m.XLink),
+		XSISchemaLocation:          copyobj.String(m.XSISchemaLocation),
+		ID:                         copyobj.String(m.ID),
+		ProgramInformation:         copyProgramInformation(m.ProgramInformation),
+		BaseURL:                    cloneBaseURLs(m.BaseURL),
+		Location:                   copyobj.Strings(m.Location),
+		PatchLocation:              copyPatchLocation(m.PatchLocation),
+		ServiceDescription:         copyServiceDescriptions(m.ServiceDescription),
+		InitializationSet:          cloneInitializationSets(m.InitializationSet),
+		InitializationGroup:        copyUIntVWithIDs(m.InitializationGroup),
+		InitializationPresentation: copyUIntVWithIDs(m.InitializationPresentation),
+		ContentSteering:            copyContentSteering(m.ContentSteering),
+		Period:                     clonePeriods(m.Period),
+		Metrics:                    cloneMetrics(m.Metrics),
+		SupplementalProperty:       copyDescriptors(m.SupplementalProperty),
+		UTCTiming:                  copyDescriptors(m.UTCTiming),
+		ExtensionAttrs:             cloneAttrs(m.ExtensionAttrs),
+		Extensions:                 cloneExtensions(m.Extensions),
+	}
+}
+
+func clonePeriod(p *Period) *Period {
+	if p == nil {
+		return nil
+	}
+	return &Period{
+		Start:           copyobj.String(p.Start),
+		ID:              copyobj.String(p.ID),
+		Duration:        copyobj.String(p.Duration),
+		XLinkHref:       copyobj.String(p.XLinkHref),
+		XLinkActuate:    copyobj.String(p.XLinkActuate),
+		BaseURL:         cloneBaseURLs(p.BaseURL),
+		SegmentBase:     copySegmentBase(p.SegmentBase),
+		SegmentList:     cloneSegmentList(p.SegmentList),
+		SegmentTemplate: cloneSegmentTemplate(p.SegmentTemplate),
+		AssetIdentifier: copyDescriptor(p.AssetIdentifier),
+		EventStreams:    cloneEventStreams(p.EventStreams),
+		AdaptationSets:  cloneAdaptationSets(p.AdaptationSets),
+		Subsets:         copySubsets(p.Subsets),
+		Preselections:   copyPreselections(p.Preselections),
+		ExtensionAttrs:  cloneAttrs(p.ExtensionAttrs),
+		Extensions:      cloneExtensions(p.Extensions),
+	}
+}
+
+func cloneAdaptationSet(as *AdaptationSet) *AdaptationSet {
+	if as == nil {
+		return nil
+	}
+	return &AdaptationSet{
+		ID:                        copyobj.String(as.ID),
+		MimeType:                  as.MimeType,
+		SegmentAlignment:          copyConditionalUint(as.SegmentAlignment),
+		StartWithSAP:              copyobj.UInt64(as.StartWithSAP),
+		BitstreamSwitching:        copyobj.Bool(as.BitstreamSwitching),
+		SubsegmentAlignment:       copyConditionalUint(as.SubsegmentAlignment),
+		SubsegmentStartsWithSAP:   copyobj.UInt64(as.SubsegmentStartsWithSAP),
+		Lang:                      copyobj.String(as.Lang),
+		ContentType:               copyobj.String(as.ContentType),
+		Group:                     copyobj.UInt64(as.Group),
+		MinBandwidth:              copyobj.UInt64(as.MinBandwidth),
+		MaxBandwidth:              copyobj.UInt64(as.MaxBandwidth),
+		MinWidth:                  copyobj.UInt64(as.MinWidth),
+		MaxWidth:                  copyobj.UInt64(as.MaxWidth),
+		MinHeight:                 copyobj.UInt64(as.MinHeight),
+		MaxHeight:                 copyobj.UInt64(as.MaxHeight),
+		MinFrameRate:              copyFrameRate(as.MinFrameRate),
+		MaxFrameRate:              copyFrameRate(as.MaxFrameRate),
+		Profiles:                  copyobj.String(as.Profiles),
+		ScanType:                  copyobj.String(as.ScanType),
+		CodingDependency:          copyobj.Bool(as.CodingDependency),
+		MaximumSAPPeriod:          copyobj.Float64(as.MaximumSAPPeriod),
+		MaxPlayoutRate:            copyobj.Float64(as.MaxPlayoutRate),
+		SelectionPriority:         copyobj.UInt64(as.SelectionPriority),
+		Tag:                       copyobj.String(as.Tag),
+		SegmentProfiles:           StringVector(copyobj.Strings(as.SegmentProfiles)),
+		InitializationSetRef:      UIntVector(copyobj.UInt64s(as.InitializationSetRef)),
+		XLinkHref:                 copyobj.String(as.XLinkHref),
+		XLinkActuate:              copyobj.String(as.XLinkActuate),
+		FramePacking:              copyDescriptors(as.FramePacking),
+		AudioChannelConfiguration: copyDescriptors(as.AudioChannelConfiguration),
+		ContentProtections:        cloneContentProtections(as.ContentProtections),
+		EssentialProperty:         copyDescriptors(as.EssentialProperty),
+		SupplementalProperty:      copyDescriptors(as.SupplementalProperty),
+		InbandEventStreams:        copyDescriptors(as.InbandEventStreams),
+		Switching:                 copySwitchings(as.Switching),
+		RandomAccess:              copyRandomAccesses(as.RandomAccess),
+		Resync:                    copyResyncs(as.Resync),
+		Accessibility:             copyDescriptors(as.Accessibility),
+		Role:                      copyDescriptors(as.Role),
+		Rating:                    copyDescriptors(as.Rating),
+		ContentComponents:         copyContentComponents(as.ContentComponents),
+		BaseURL:                   cloneBaseURLs(as.BaseURL),
+		SegmentBase:               copySegmentBase(as.SegmentBase),
+		SegmentList:               cloneSegmentList(as.SegmentList),
+		SegmentTemplate:           cloneSegmentTemplate(as.SegmentTemplate),
+		Representations:           cloneRepresentations(as.Representations),
+		ExtensionAttrs:            cloneAttrs(as.ExtensionAttrs),
+		Extensions:                cloneExtensions(as.Extensions),
+		Codecs:                    copyobj.String(as.Codecs),
+	}
+}
+
+func cloneRepresentation(r *Representation) *Representation {
+	if r == nil {
+		return nil
+	}
+	return &Representation{
+		ID:                        copyobj.String(r.ID),
+		Width:                     copyobj.UInt64(r.Width),
+		Height:                    copyobj.UInt64(r.Height),
+		SAR:                       copyRatio(r.SAR),
+		FrameRate:                 copyFrameRate(r.FrameRate),
+		Bandwidth:                 copyobj.UInt64(r.Bandwidth),
+		AudioSamplingRate:         copyAudioSamplingRate(r.AudioSamplingRate),
+		Codecs:                    copyobj.String(r.Codecs),
+		QualityRanking:            copyobj.UInt64(r.QualityRanking),
+		DependencyID:              StringVector(copyobj.Strings(r.DependencyID)),
+		AssociationID:             StringVector(copyobj.Strings(r.AssociationID)),
+		AssociationType:           StringVector(copyobj.Strings(r.AssociationType)),
+		MediaStreamStructureID:    StringVector(copyobj.Strings(r.MediaStreamStructureID)),
+		Profiles:                  copyobj.String(r.Profiles),
+		ScanType:                  copyobj.String(r.ScanType),
+		CodingDependency:          copyobj.Bool(r.CodingDependency),
+		MaximumSAPPeriod:          copyobj.Float64(r.MaximumSAPPeriod),
+		MaxPlayoutRate:            copyobj.Float64(r.MaxPlayoutRate),
+		SelectionPriority:         copyobj.UInt64(r.SelectionPriority),
+		Tag:                       copyobj.String(r.Tag),
+		SegmentProfiles:           StringVector(copyobj.Strings(r.SegmentProfiles)),
+		FramePacking:              copyDescriptors(r.FramePacking),
+		AudioChannelConfiguration: copyDescriptors(r.AudioChannelConfiguration),
+		ContentProtections:        cloneContentProtections(r.ContentProtections),
+		EssentialProperty:         copyDescriptors(r.EssentialProperty),
+		SupplementalProperty:      copyDescriptors(r.SupplementalProperty),
+		InbandEventStreams:        copyDescriptors(r.InbandEventStreams),
+		Switching:                 copySwitchings(r.Switching),
+		RandomAccess:              copyRandomAccesses(r.RandomAccess),
+		Resync:                    copyResyncs(r.Resync),
+		BaseURL:                   cloneBaseURLs(r.BaseURL),
+		SegmentBase:               copySegmentBase(r.SegmentBase),
+		SegmentList:               cloneSegmentList(r.SegmentList),
+		SegmentTemplate:           cloneSegmentTemplate(r.SegmentTemplate),
+		ExtensionAttrs:            cloneAttrs(r.ExtensionAttrs),
+		Extensions:                cloneExtensions(r.Extensions),
+	}
+}
+
+func cloneSegmentTemplate(st *SegmentTemplate) *SegmentTemplate {
+	if st == nil {
+		return nil
+	}
+	return &SegmentTemplate{
+		Timescale:                copyobj.UInt64(st.Timescale),
+		Duration:                 copyobj.UInt64(st.Duration),
+		Media:                    copyobj.String(st.Media),
+		Index:                    copyobj.String(st.Index),
+		Initialization:           copyobj.String(st.Initialization),
+		BitstreamSwitching:       copyobj.String(st.BitstreamSwitching),
+		StartNumber:              copyobj.UInt64(st.StartNumber),
+		EndNumber:                copyobj.UInt64(st.EndNumber),
+		PresentationTimeOffset:   copyobj.UInt64(st.PresentationTimeOffset),
+		AvailabilityTimeOffset:   copyAvailabilityTimeOffset(st.AvailabilityTimeOffset),
+		AvailabilityTimeComplete: copyobj.Bool(st.AvailabilityTimeComplete),
+		InitializationURL:        copyURLType(st.InitializationURL),
+		RepresentationIndex:      copyURLType(st.RepresentationIndex),
+		SegmentTimelineS:         copySegmentTimelineS(st.SegmentTimelineS),
+	}
+}
+
+func cloneSegmentList(sl *SegmentList) *SegmentList {
+	if sl == nil {
+		return nil
+	}
+	return &SegmentList{
+		Timescale:                copyobj.UInt64(sl.Timescale),
+		Duration:                 copyobj.UInt64(sl.Duration),
+		StartNumber:              copyobj.UInt64(sl.StartNumber),
+		PresentationTimeOffset:   copyobj.UInt64(sl.PresentationTimeOffset),
+		AvailabilityTimeOffset:   copyAvailabilityTimeOffset(sl.AvailabilityTimeOffset),
+		AvailabilityTimeComplete: copyobj.Bool(sl.AvailabilityTimeComplete),
+		Initialization:           copyURLType(sl.Initialization),
+		RepresentationIndex:      copyURLType(sl.RepresentationIndex),
+		SegmentTimelineS:         copySegmentTimelineS(sl.SegmentTimelineS),
+		SegmentURLs:              cloneSegmentListURLs(sl.SegmentURLs),
+	}
+}
+
+func clonePssh(p *Pssh) *Pssh {
+	if p == nil {
+		return nil
+	}
+	return &Pssh{
+		Cenc:  copyobj.String(p.Cenc),
+		Value: copyobj.String(p.Value),
+	}
+}
+
+func clonePro(p *Pro) *Pro {
+	if p == nil {
+		return nil
+	}
+	return &Pro{
+		MSPR:  copyobj.String(p.MSPR),
+		Value: copyobj.String(p.Value),
+	}
+}
+
+func cloneLaurl(l *Laurl) *Laurl {
+	if l == nil {
+		return nil
+	}
+	return &Laurl{
+		DashIF:      copyobj.String(l.DashIF),
+		LicenseType: copyobj.String(l.LicenseType),
+		Value:       copyobj.String(l.Value),
+	}
+}
+
+func cloneLegacyLaurl(l *LegacyLaurl) *LegacyLaurl {
+	if l == nil {
+		return nil
+	}
+	return &LegacyLaurl{
+		ClearKey: copyobj.String(l.ClearKey),
+		LicType:  copyobj.String(l.LicType),
+		Value:    copyobj.String(l.Value),
+	}
+}
+
+func cloneDashIFValue(v *DashIFValue) *DashIFValue {
+	if v == nil {
+		return nil
+	}
+	return &DashIFValue{
+		DashIF: copyobj.String(v.DashIF),
+		Value:  copyobj.String(v.Value),
+	}
+}
+
+func cloneMarlinContentIDs(ids *MarlinContentIDs) *MarlinContentIDs {
+	if ids == nil {
+		return nil
+	}
+	return &MarlinContentIDs{
+		ContentIDs: copyobj.Strings(ids.ContentIDs),
+	}
+}
+
+func cloneSCTE35Signal(s *SCTE35Signal) *SCTE35Signal {
+	if s == nil {
+		return nil
+	}
+	return &SCTE35Signal{
+		SpliceInfoSection: cloneSCTE35SpliceInfoSection(s.SpliceInfoSection),
+		Binary:            cloneSCTE35Binary(s.Binary),
+	}
+}
+
+func cloneSCTE35Binary(b *SCTE35Binary) *SCTE35Binary {
+	if b == nil {
+		return nil
+	}
+	return &SCTE35Binary{
+		SignalType: copyobj.String(b.SignalType),
+		Value:      b.Value,
+	}
+}
+
+func cloneSCTE35SpliceInfoSection(s *SCTE35SpliceInfoSection) *SCTE35SpliceInfoSection {
+	if s == nil {
+		return nil
+	}
+	return &SCTE35SpliceInfoSection{
+		SAPType:                 copyobj.UInt64(s.SAPType),
+		PTSAdjustment:           copyobj.UInt64(s.PTSAdjustment),
+		ProtocolVersion:         copyobj.UInt64(s.ProtocolVersion),
+		Tier:                    copyobj.UInt64(s.Tier),
+		SpliceInsert:            cloneSCTE35SpliceInsert(s.SpliceInsert),
+		TimeSignal:              cloneSCTE35TimeSignal(s.TimeSignal),
+		SegmentationDescriptors: cloneSCTE35SegmentationDescriptors(s.SegmentationDescriptors),
+		Extensions:              cloneExtensions(s.Extensions),
+	}
+}
+
+func cloneSCTE35TimeSignal(ts *SCTE35TimeSignal) *SCTE35TimeSignal {
+	if ts == nil {
+		return nil
+	}
+	return &SCTE35TimeSignal{
+		SpliceTime: copySCTE35SpliceTime(ts.SpliceTime),
+	}
+}
+
+func cloneSCTE35SpliceInsert(si *SCTE35SpliceInsert) *SCTE35SpliceInsert {
+	if si == nil {
+		return nil
+	}
+	return &SCTE35SpliceInsert{
+		SpliceEventID:              copyobj.UInt64(si.SpliceEventID),
+		SpliceEventCancelIndicator: copyobj.Bool(si.SpliceEventCancelIndicator),
+		OutOfNetworkIndicator:      copyobj.Bool(si.OutOfNetworkIndicator),
+		SpliceImmediateFlag:        copyobj.Bool(si.SpliceImmediateFlag),
+		UniqueProgramID:            copyobj.UInt64(si.UniqueProgramID),
+		AvailNum:                   copyobj.UInt64(si.AvailNum),
+		AvailsExpected:             copyobj.UInt64(si.AvailsExpected),
+		Program:                    cloneSCTE35Program(si.Program),
+		BreakDuration:              cloneSCTE35BreakDuration(si.BreakDuration),
+	}
+}
+
+func cloneSCTE35Program(p *SCTE35Program) *SCTE35Program {
+	if p == nil {
+		return nil
+	}
+	return &SCTE35Program{
+		SpliceTime: copySCTE35SpliceTime(p.SpliceTime),
+	}
+}
+
+func cloneSCTE35BreakDuration(bd *SCTE35BreakDuration) *SCTE35BreakDuration {
+	if bd == nil {
+		return nil
+	}
+	return &SCTE35BreakDuration{
+		AutoReturn: copyobj.Bool(bd.AutoReturn),
+		Duration:   copyobj.UInt64(bd.Duration),
+	}
+}
+
+func cloneSCTE35DeliveryRestrictions(dr *SCTE35DeliveryRestrictions) *SCTE35DeliveryRestrictions {
+	if dr == nil {
+		return nil
+	}
+	return &SCTE35DeliveryRestrictions{
+		WebDeliveryAllowedFlag: copyobj.Bool(dr.WebDeliveryAllowedFlag),
+		NoRegionalBlackoutFlag: copyobj.Bool(dr.NoRegionalBlackoutFlag),
+		ArchiveAllowedFlag:     copyobj.Bool(dr.ArchiveAllowedFlag),
+		DeviceRestrictions:     copyobj.UInt64(dr.DeviceRestrictions),
+	}
+}
+
+func cloneMetrics(ms []Metrics) []Metrics {
+	if ms == nil {
+		return nil
+	}
+	res := make([]Metrics, 0, len(ms))
+	for _, m := range ms {
+		res = append(res, Metrics{
+			Metrics:   m.Metrics,
+			Reporting: cloneReportings(m.Reporting),
+			Range:     cloneMetricsRanges(m.Range),
+		})
+	}
+	return res
+}
+
+func cloneReportings(rs []Reporting) []Reporting {
+	if rs == nil {
+		return nil
+	}
+	res := make([]Reporting, 0, len(rs))
+	for _, r := range rs {
+		res = append(res, Reporting{
+			SchemeIDURI:     copyobj.String(r.SchemeIDURI),
+			Value:           copyobj.String(r.Value),
+			ID:              copyobj.String(r.ID),
+			DVBReportingURL: copyobj.String(r.DVBReportingURL),
+			DVBProbability:  copyobj.UInt64(r.DVBProbability),
+		})
+	}
+	return res
+}
+
+func cloneMetricsRanges(rs []MetricsRange) []MetricsRange {
+	if rs == nil {
+		return nil
+	}
+	res := make([]MetricsRange, 0, len(rs))
+	for _, r := range rs {
+		res = append(res, MetricsRange{
+			Starttime: copyobj.String(r.Starttime),
+			Duration:  copyobj.String(r.Duration),
+		})
+	}
+	return res
+}
+
+func cloneInitializationSets(iss []InitializationSet) []InitializationSet {
+	if iss == nil {
+		return nil
+	}
+	res := make([]InitializationSet, 0, len(iss))
+	for _, is := range iss {
+		res = append(res, InitializationSet{
+			XLinkHref:            copyobj.String(is.XLinkHref),
+			XLinkActuate:         copyobj.String(is.XLinkActuate),
+			ID:                   copyobj.UInt64(is.ID),
+			InAllPeriods:         copyobj.Bool(is.InAllPeriods),
+			ContentType:          copyobj.String(is.ContentType),
+			Par:                  copyRatio(is.Par),
+			MaxWidth:             copyobj.UInt64(is.MaxWidth),
+			MaxHeight:            copyobj.UInt64(is.MaxHeight),
+			MaxFrameRate:         copyFrameRate(is.MaxFrameRate),
+			Initialization:       copyobj.String(is.Initialization),
+			Profiles:             copyobj.String(is.Profiles),
+			MimeType:             copyobj.String(is.MimeType),
+			Codecs:               copyobj.String(is.Codecs),
+			EssentialProperty:    copyDescriptors(is.EssentialProperty),
+			SupplementalProperty: copyDescriptors(is.SupplementalProperty),
+			Accessibility:        copyDescriptors(is.Accessibility),
+			Role:                 copyDescriptors(is.Role),
+			Rating:               copyDescriptors(is.Rating),
+			Viewpoint:            copyDescriptors(is.Viewpoint),
+		})
+	}
+	return res
+}
+
+func cloneBaseURLs(bs []BaseURL) []BaseURL {
+	if bs == nil {
+		return nil
+	}
+	res := make([]BaseURL, 0, len(bs))
+	for _, b := range bs {
+		res = append(res, BaseURL{
+			Value:                    b.Value,
+			ServiceLocation:          copyobj.String(b.ServiceLocation),
+			ByteRange:                copyobj.String(b.ByteRange),
+			AvailabilityTimeOffset:   copyAvailabilityTimeOffset(b.AvailabilityTimeOffset),
+			AvailabilityTimeComplete: copyobj.Bool(b.AvailabilityTimeComplete),
+			DVBPriority:              copyobj.UInt64(b.DVBPriority),
+			DVBWeight:                copyobj.UInt64(b.DVBWeight),
+		})
+	}
+	return res
+}
+
+func clonePeriods(ps []Period) []Period {
+	if ps == nil {
+		return nil
+	}
+	res := make([]Period, 0, len(ps))
+	for i := range ps {
+		res = append(res, *clonePeriod(&ps[i]))
+	}
+	return res
+}
+
+func cloneEventStreams(ess []EventStream) []EventStream {
+	if ess == nil {
+		return nil
+	}
+	res := make([]EventStream, 0, len(ess))
+	for _, es := range ess {
+		res = append(res, EventStream{
+			SchemeIDURI:            copyobj.String(es.SchemeIDURI),
+			Value:                  copyobj.String(es.Value),
+			Timescale:              copyobj.UInt64(es.Timescale),
+			PresentationTimeOffset: copyobj.UInt64(es.PresentationTimeOffset),
+			XLinkHref:              copyobj.String(es.XLinkHref),
+			XLinkActuate:           copyobj.String(es.XLinkActuate),
+			Events:                 cloneEvents(es.Events),
+		})
+	}
+	return res
+}
+
+func cloneEvents(es []Event) []Event {
+	if es == nil {
+		return nil
+	}
+	res := make([]Event, 0, len(es))
+	for _, e := range es {
+		res = append(res, Event{
+			PresentationTime:  copyobj.UInt64(e.PresentationTime),
+			Duration:          copyobj.UInt64(e.Duration),
+			ID:                copyobj.UInt64(e.ID),
+			ContentEncoding:   copyobj.String(e.ContentEncoding),
+			MessageData:       copyobj.String(e.MessageData),
+			Signal:            cloneSCTE35Signal(e.Signal),
+			SpliceInfoSection: cloneSCTE35SpliceInfoSection(e.SpliceInfoSection),
+			Payload:           e.Payload,
+		})
+	}
+	return res
+}
+
+func cloneAdaptationSets(ass []*AdaptationSet) []*AdaptationSet {
+	if ass == nil {
+		return nil
+	}
+	res := make([]*AdaptationSet, 0, len(ass))
+	for _, as := range ass {
+		res = append(res, cloneAdaptationSet(as))
+	}
+	return res
+}
+
+func cloneRepresentations(rs []Representation) []Representation {
+	if rs == nil {
+		return nil
+	}
+	res := make([]Representation, 0, len(rs))
+	for i := range rs {
+		res = append(res, *cloneRepresentation(&rs[i]))
+	}
+	return res
+}
+
+func cloneContentProtections(ds []DRMDescriptor) []DRMDescriptor {
+	if ds == nil {
+		return nil
+	}
+	res := make([]DRMDescriptor, 0, len(ds))
+	for _, d := range ds {
+		res = append(res, DRMDescriptor{
+			SchemeIDURI:      copyobj.String(d.SchemeIDURI),
+			Value:            copyobj.String(d.Value),
+			Robustness:       copyobj.String(d.Robustness),
+			RefID:            copyobj.String(d.RefID),
+			Ref:              copyobj.String(d.Ref),
+			CencDefaultKID:   copyobj.String(d.CencDefaultKID),
+			Cenc:             copyobj.String(d.Cenc),
+			MSPR:             copyobj.String(d.MSPR),
+			MAS:              copyobj.String(d.MAS),
+			Pssh:             clonePssh(d.Pssh),
+			Pro:              clonePro(d.Pro),
+			Laurl:            cloneLaurl(d.Laurl),
+			LegacyLaurl:      cloneLegacyLaurl(d.LegacyLaurl),
+			AuthzURL:         cloneDashIFValue(d.AuthzURL),
+			AuthzScheme:      cloneDashIFValue(d.AuthzScheme),
+			MarlinContentIDs: cloneMarlinContentIDs(d.MarlinContentIDs),
+		})
+	}
+	return res
+}
+
+func cloneSegmentListURLs(us []SegmentListURL) []SegmentListURL {
+	if us == nil {
+		return nil
+	}
+	res := make([]SegmentListURL, 0, len(us))
+	for _, u := range us {
+		res = append(res, SegmentListURL{
+			Media:      copyobj.String(u.Media),
+			MediaRange: copyobj.String(u.MediaRange),
+			Index:      copyobj.String(u.Index),
+			IndexRange: copyobj.String(u.IndexRange),
+		})
+	}
+	return res
+}
+
+func cloneSCTE35SegmentationDescriptors(sds []SCTE35SegmentationDescriptor) []SCTE35SegmentationDescriptor {
+	if sds == nil {
+		return nil
+	}
+	res := make([]SCTE35SegmentationDescriptor, 0, len(sds))
+	for _, sd := range sds {
+		res = append(res, SCTE35SegmentationDescriptor{
+			SegmentationEventID:              copyobj.UInt64(sd.SegmentationEventID),
+			SegmentationEventCancelIndicator: copyobj.Bool(sd.SegmentationEventCancelIndicator),
+			SegmentationDuration:             copyobj.UInt64(sd.SegmentationDuration),
+			SegmentationTypeID:               copyobj.UInt64(sd.SegmentationTypeID),
+			SegmentNum:                       copyobj.UInt64(sd.SegmentNum),
+			SegmentsExpected:                 copyobj.UInt64(sd.SegmentsExpected),
+			SubSegmentNum:                    copyobj.UInt64(sd.SubSegmentNum),
+			SubSegmentsExpected:              copyobj.UInt64(sd.SubSegmentsExpected),
+			DeliveryRestrictions:             cloneSCTE35DeliveryRestrictions(sd.DeliveryRestrictions),
+			SegmentationUpids:                cloneSCTE35SegmentationUpids(sd.SegmentationUpids),
+		})
+	}
+	return res
+}
+
+func cloneSCTE35SegmentationUpids(us []SCTE35SegmentationUpid) []SCTE35SegmentationUpid {
+	if us == nil {
+		return nil
+	}
+	res := make([]SCTE35SegmentationUpid, 0, len(us))
+	for _, u := range us {
+		res = append(res, SCTE35SegmentationUpid{
+			SegmentationUpidType:   copyobj.UInt64(u.SegmentationUpidType),
+			FormatIdentifier:       copyobj.UInt64(u.FormatIdentifier),
+			SegmentationUpidFormat: copyobj.String(u.SegmentationUpidFormat),
+			Value:                  u.Value,
+		})
+	}
+	return res
+}
+
+func cloneExtensions(es []Extension) []Extension {
+	if es == nil {
+		return nil
+	}
+	res := make([]Extension, 0, len(es))
+	for _, e := range es {
+		res = append(res, Extension{
+			XMLName: e.XMLName,
+			Attrs:   cloneAttrs(e.Attrs),
+			Content: e.Content,
+		})
+	}
+	return res
+}
+
+func cloneAttrs(attrs []xml.Attr) []xml.Attr {
+	if attrs == nil {
+		return nil
+	}
+	return append(make([]xml.Attr, 0, len(attrs)), attrs...)
+}
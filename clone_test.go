@@ -0,0 +1,94 @@
+package mpd
+
+import (
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// sharedMemory returns path of the first pointer or slice shared by a and b, empty if there is none.
+// Locations of time.Time values are immutable and may be shared.
+func sharedMemory(a, b reflect.Value, path string) string {
+	if a.Type() == reflect.TypeOf((*time.Location)(nil)) {
+		return ""
+	}
+	switch a.Kind() {
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			return ""
+		}
+		if a.Pointer() == b.Pointer() {
+			return path
+		}
+		return sharedMemory(a.Elem(), b.Elem(), path)
+	case reflect.Slice:
+		if a.Len() == 0 || b.Len() == 0 {
+			return ""
+		}
+		if a.Pointer() == b.Pointer() {
+			return path
+		}
+		for i := 0; i < a.Len() && i < b.Len(); i++ {
+			if res := sharedMemory(a.Index(i), b.Index(i), path+"["+strconv.Itoa(i)+"]"); res != "" {
+				return res
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if res := sharedMemory(a.Field(i), b.Field(i), path+"."+a.Type().Field(i).Name); res != "" {
+				return res
+			}
+		}
+	}
+	return ""
+}
+
+func TestClone(t *testing.T) {
+	fixtures, err := filepath.Glob("fixture_*.mpd")
+	require.NoError(t, err)
+	for _, name := range fixtures {
+		m := decodeFixture(t, name)
+		c := m.Clone()
+		require.Equal(t, m, c, name)
+		require.Empty(t, sharedMemory(reflect.ValueOf(m), reflect.ValueOf(c), "MPD"), name)
+	}
+
+	m := decodeFixture(t, "fixture_elemental_delta_vod_multi_drm.mpd")
+	c := m.Clone()
+	r := &c.Period[0].AdaptationSets[0].Representations[0]
+	*r.ID = "changed"
+	r.SegmentTemplate.SegmentTimelineS[0].D = 1
+	*r.ContentProtections[0].SchemeIDURI = "changed"
+	require.Equal(t, decodeFixture(t, "fixture_elemental_delta_vod_multi_drm.mpd"), m)
+
+	p := m.Period[0].Clone()
+	require.Equal(t, m.Period[0], *p)
+	as := m.Period[0].AdaptationSets[0].Clone()
+	require.Equal(t, m.Period[0].AdaptationSets[0], as)
+	r = m.Period[0].AdaptationSets[0].Representations[0].Clone()
+	require.Equal(t, m.Period[0].AdaptationSets[0].Representations[0], *r)
+	require.Empty(t, sharedMemory(reflect.ValueOf(&m.Period[0].AdaptationSets[0].Representations[0]), reflect.ValueOf(r), "Representation"))
+
+	require.Nil(t, (*MPD)(nil).Clone())
+	require.Nil(t, (*Representation)(nil).Clone())
+}
+
+func TestCloneGenerated(t *testing.T) {
+	f := func(m *MPD) bool {
+		c := m.Clone()
+		if !reflect.DeepEqual(m, c) {
+			return false
+		}
+		if path := sharedMemory(reflect.ValueOf(m), reflect.ValueOf(c), "MPD"); path != "" {
+			t.Log(path)
+			return false
+		}
+		return true
+	}
+	require.NoError(t, quick.Check(f, &quick.Config{MaxCount: 300}))
+}
@@ -0,0 +1,365 @@
+// Package codecs parses and formats RFC 6381 codecs strings used by codecs attribute of MPD,
+// e.g. "avc1.64001f" or "mp4a.40.2".
+package codecs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Codec is a single parsed entry of codecs attribute.
+type Codec interface {
+	// SampleEntry returns ISO BMFF sample entry code, e.g. "avc1" or "mp4a".
+	SampleEntry() string
+	// String formats codec back to RFC 6381 string.
+	String() string
+}
+
+// AVC is H.264/AVC codec: avc1, avc2, avc3 or avc4 followed by profile_idc, constraint flags and level_idc
+// in hex, e.g. "avc1.64001f".
+type AVC struct {
+	Entry       string
+	Profile     uint8
+	Constraints uint8
+	Level       uint8
+}
+
+// HEVC is H.265/HEVC codec: hvc1 or hev1, e.g. "hvc1.2.4.L153.B0".
+type HEVC struct {
+	Entry string
+	// ProfileSpace is general_profile_space: 0 or 1-3 for "A"-"C" profile prefix.
+	ProfileSpace uint8
+	Profile      uint8
+	// CompatibilityFlags is general_profile_compatibility_flags in reverse bit order, as written in the string.
+	CompatibilityFlags uint32
+	// HighTier is true for "H" tier, false for "L" (main) one.
+	HighTier bool
+	Level    uint8
+	// Constraints are constraint indicator flags bytes, trailing zero bytes may be omitted.
+	Constraints []uint8
+}
+
+// AV1 is AV1 codec, e.g. "av01.0.08M.10".
+type AV1 struct {
+	Profile uint8
+	Level   uint8
+	// HighTier is true for "H" tier, false for "M" (main) one.
+	HighTier bool
+	// BitDepth is 0 if it's not signaled.
+	BitDepth uint8
+	// Extra are optional fields following bit depth (monochrome, chroma subsampling, color info, range) as written.
+	Extra []string
+}
+
+// VP9 is VP9 codec, e.g. "vp09.00.10.08".
+type VP9 struct {
+	Profile  uint8
+	Level    uint8
+	BitDepth uint8
+	// Extra are optional fields following bit depth (chroma subsampling, color info, range) as written.
+	Extra []string
+}
+
+// MP4A is MPEG-4 audio codec: object type indication in hex optionally followed by audio object type,
+// e.g. "mp4a.40.2" for AAC-LC.
+type MP4A struct {
+	ObjectTypeIndication uint8
+	// AudioObjectType is 0 if it's not signaled.
+	AudioObjectType uint8
+}
+
+// AC4 is Dolby AC-4 codec, e.g. "ac-4.02.01.03".
+type AC4 struct {
+	BitstreamVersion    uint8
+	PresentationVersion uint8
+	MDCompat            uint8
+}
+
+// Generic is a codec without specific parameters parsing, e.g. "ec-3" or "stpp.ttml.im1t".
+type Generic struct {
+	Entry  string
+	Params []string
+}
+
+// Parse parses single codecs entry, surrounding whitespace is ignored.
+func Parse(s string) (Codec, error) {
+	parts := strings.Split(strings.TrimSpace(s), ".")
+	invalid := fmt.Errorf("invalid codecs %q", s)
+	if parts[0] == "" {
+		return nil, invalid
+	}
+
+	var c Codec
+	var ok bool
+	switch parts[0] {
+	case "avc1", "avc2", "avc3", "avc4":
+		c, ok = parseAVC(parts)
+	case "hvc1", "hev1":
+		c, ok = parseHEVC(parts)
+	case "av01":
+		c, ok = parseAV1(parts)
+	case "vp09":
+		c, ok = parseVP9(parts)
+	case "mp4a":
+		c, ok = parseMP4A(parts)
+	case "ac-4":
+		c, ok = parseAC4(parts)
+	default:
+		g := Generic{Entry: parts[0]}
+		if len(parts) > 1 {
+			g.Params = parts[1:]
+		}
+		c, ok = g, true
+	}
+	if !ok {
+		return nil, invalid
+	}
+	return c, nil
+}
+
+// ParseList parses comma-separated codecs attribute value.
+func ParseList(s string) ([]Codec, error) {
+	var res []Codec
+	for _, entry := range strings.Split(s, ",") {
+		c, err := Parse(entry)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, c)
+	}
+	return res, nil
+}
+
+// FormatList formats codecs as comma-separated codecs attribute value.
+func FormatList(cs []Codec) string {
+	entries := make([]string, 0, len(cs))
+	for _, c := range cs {
+		entries = append(entries, c.String())
+	}
+	return strings.Join(entries, ",")
+}
+
+func parseUint8(s string, base int) (uint8, bool) {
+	v, err := strconv.ParseUint(s, base, 8)
+	return uint8(v), err == nil
+}
+
+func parseAVC(parts []string) (Codec, bool) {
+	if len(parts) != 2 || len(parts[1]) != 6 {
+		return nil, false
+	}
+	profile, ok1 := parseUint8(parts[1][0:2], 16)
+	constraints, ok2 := parseUint8(parts[1][2:4], 16)
+	level, ok3 := parseUint8(parts[1][4:6], 16)
+	return AVC{Entry: parts[0], Profile: profile, Constraints: constraints, Level: level}, ok1 && ok2 && ok3
+}
+
+func parseHEVC(parts []string) (Codec, bool) {
+	if len(parts) < 4 || len(parts) > 10 || parts[1] == "" || len(parts[3]) < 2 {
+		return nil, false
+	}
+	c := HEVC{Entry: parts[0]}
+	profile := parts[1]
+	if p := profile[0]; p >= 'A' && p <= 'C' {
+		c.ProfileSpace = p - 'A' + 1
+		profile = profile[1:]
+	}
+	var ok bool
+	if c.Profile, ok = parseUint8(profile, 10); !ok {
+		return nil, false
+	}
+	flags, err := strconv.ParseUint(parts[2], 16, 32)
+	if err != nil {
+		return nil, false
+	}
+	c.CompatibilityFlags = uint32(flags)
+	switch parts[3][0] {
+	case 'L':
+	case 'H':
+		c.HighTier = true
+	default:
+		return nil, false
+	}
+	if c.Level, ok = parseUint8(parts[3][1:], 10); !ok {
+		return nil, false
+	}
+	for _, p := range parts[4:] {
+		b, ok := parseUint8(p, 16)
+		if !ok {
+			return nil, false
+		}
+		c.Constraints = append(c.Constraints, b)
+	}
+	return c, true
+}
+
+func parseAV1(parts []string) (Codec, bool) {
+	if len(parts) < 3 || len(parts[2]) != 3 {
+		return nil, false
+	}
+	var c AV1
+	var ok bool
+	if c.Profile, ok = parseUint8(parts[1], 10); !ok {
+		return nil, false
+	}
+	if c.Level, ok = parseUint8(parts[2][:2], 10); !ok {
+		return nil, false
+	}
+	switch parts[2][2] {
+	case 'M':
+	case 'H':
+		c.HighTier = true
+	default:
+		return nil, false
+	}
+	if len(parts) > 3 {
+		if c.BitDepth, ok = parseUint8(parts[3], 10); !ok {
+			return nil, false
+		}
+		if len(parts) > 4 {
+			c.Extra = parts[4:]
+		}
+	}
+	return c, true
+}
+
+func parseVP9(parts []string) (Codec, bool) {
+	if len(parts) < 4 {
+		return nil, false
+	}
+	profile, ok1 := parseUint8(parts[1], 10)
+	level, ok2 := parseUint8(parts[2], 10)
+	bitDepth, ok3 := parseUint8(parts[3], 10)
+	c := VP9{Profile: profile, Level: level, BitDepth: bitDepth}
+	if len(parts) > 4 {
+		c.Extra = parts[4:]
+	}
+	return c, ok1 && ok2 && ok3
+}
+
+func parseMP4A(parts []string) (Codec, bool) {
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, false
+	}
+	var c MP4A
+	var ok bool
+	if c.ObjectTypeIndication, ok = parseUint8(parts[1], 16); !ok {
+		return nil, false
+	}
+	if len(parts) == 3 {
+		if c.AudioObjectType, ok = parseUint8(parts[2], 10); !ok || c.AudioObjectType == 0 {
+			return nil, false
+		}
+	}
+	return c, true
+}
+
+func parseAC4(parts []string) (Codec, bool) {
+	if len(parts) != 4 {
+		return nil, false
+	}
+	bitstream, ok1 := parseUint8(parts[1], 16)
+	presentation, ok2 := parseUint8(parts[2], 16)
+	mdcompat, ok3 := parseUint8(parts[3], 16)
+	return AC4{BitstreamVersion: bitstream, PresentationVersion: presentation, MDCompat: mdcompat}, ok1 && ok2 && ok3
+}
+
+// SampleEntry implements Codec.
+func (c AVC) SampleEntry() string { return c.Entry }
+
+// String implements Codec.
+func (c AVC) String() string {
+	return fmt.Sprintf("%s.%02x%02x%02x", c.Entry, c.Profile, c.Constraints, c.Level)
+}
+
+// SampleEntry implements Codec.
+func (c HEVC) SampleEntry() string { return c.Entry }
+
+// String implements Codec.
+func (c HEVC) String() string {
+	var b strings.Builder
+	b.WriteString(c.Entry + ".")
+	if c.ProfileSpace > 0 {
+		b.WriteByte('A' + c.ProfileSpace - 1)
+	}
+	tier := 'L'
+	if c.HighTier {
+		tier = 'H'
+	}
+	fmt.Fprintf(&b, "%d.%X.%c%d", c.Profile, c.CompatibilityFlags, tier, c.Level)
+	for _, constraint := range c.Constraints {
+		fmt.Fprintf(&b, ".%X", constraint)
+	}
+	return b.String()
+}
+
+// SampleEntry implements Codec.
+func (c AV1) SampleEntry() string { return "av01" }
+
+// String implements Codec.
+func (c AV1) String() string {
+	tier := 'M'
+	if c.HighTier {
+		tier = 'H'
+	}
+	s := fmt.Sprintf("av01.%d.%02d%c", c.Profile, c.Level, tier)
+	if c.BitDepth > 0 {
+		s += fmt.Sprintf(".%02d", c.BitDepth)
+	}
+	for _, e := range c.Extra {
+		s += "." + e
+	}
+	return s
+}
+
+// SampleEntry implements Codec.
+func (c VP9) SampleEntry() string { return "vp09" }
+
+// String implements Codec.
+func (c VP9) String() string {
+	s := fmt.Sprintf("vp09.%02d.%02d.%02d", c.Profile, c.Level, c.BitDepth)
+	for _, e := range c.Extra {
+		s += "." + e
+	}
+	return s
+}
+
+// SampleEntry implements Codec.
+func (c MP4A) SampleEntry() string { return "mp4a" }
+
+// String implements Codec.
+func (c MP4A) String() string {
+	s := fmt.Sprintf("mp4a.%02x", c.ObjectTypeIndication)
+	if c.AudioObjectType > 0 {
+		s += "." + strconv.Itoa(int(c.AudioObjectType))
+	}
+	return s
+}
+
+// SampleEntry implements Codec.
+func (c AC4) SampleEntry() string { return "ac-4" }
+
+// String implements Codec.
+func (c AC4) String() string {
+	return fmt.Sprintf("ac-4.%02x.%02x.%02x", c.BitstreamVersion, c.PresentationVersion, c.MDCompat)
+}
+
+// SampleEntry implements Codec.
+func (c Generic) SampleEntry() string { return c.Entry }
+
+// String implements Codec.
+func (c Generic) String() string {
+	return strings.Join(append([]string{c.Entry}, c.Params...), ".")
+}
+
+// check interfaces
+var (
+	_ Codec = AVC{}
+	_ Codec = HEVC{}
+	_ Codec = AV1{}
+	_ Codec = VP9{}
+	_ Codec = MP4A{}
+	_ Codec = AC4{}
+	_ Codec = Generic{}
+)
@@ -0,0 +1,45 @@
+package codecs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	for s, expected := range map[string]Codec{
+		"avc1.64001f":                    AVC{Entry: "avc1", Profile: 100, Level: 31},
+		"avc3.4d401e":                    AVC{Entry: "avc3", Profile: 77, Constraints: 0x40, Level: 30},
+		"hvc1.2.4.L153.B0":               HEVC{Entry: "hvc1", Profile: 2, CompatibilityFlags: 4, Level: 153, Constraints: []uint8{0xb0}},
+		"hev1.A1.6.H120":                 HEVC{Entry: "hev1", ProfileSpace: 1, Profile: 1, CompatibilityFlags: 6, HighTier: true, Level: 120},
+		"av01.0.08M.10":                  AV1{Level: 8, BitDepth: 10},
+		"av01.0.04M.10.0.112.09.16.09.0": AV1{Level: 4, BitDepth: 10, Extra: []string{"0", "112", "09", "16", "09", "0"}},
+		"vp09.00.10.08":                  VP9{Level: 10, BitDepth: 8},
+		"mp4a.40.2":                      MP4A{ObjectTypeIndication: 0x40, AudioObjectType: 2},
+		"mp4a.a5":                        MP4A{ObjectTypeIndication: 0xa5},
+		"ac-4.02.01.03":                  AC4{BitstreamVersion: 2, PresentationVersion: 1, MDCompat: 3},
+		"ec-3":                           Generic{Entry: "ec-3"},
+		"stpp.ttml.im1t":                 Generic{Entry: "stpp", Params: []string{"ttml", "im1t"}},
+	} {
+		c, err := Parse(s)
+		require.NoError(t, err, s)
+		require.Equal(t, expected, c, s)
+		require.Equal(t, s, c.String(), s)
+	}
+
+	for _, s := range []string{"", "avc1", "avc1.64001", "avc1.zz001f", "hvc1.1.6", "hvc1.1.6.X93", "av01.0.8M",
+		"av01.0.08X", "vp09.00.10", "mp4a", "mp4a.40.0", "mp4a.40.x", "ac-4.02.01"} {
+		_, err := Parse(s)
+		require.EqualError(t, err, "invalid codecs \""+s+"\"", s)
+	}
+}
+
+func TestParseList(t *testing.T) {
+	cs, err := ParseList("avc1.4D401E, mp4a.40.2")
+	require.NoError(t, err)
+	require.Equal(t, []Codec{AVC{Entry: "avc1", Profile: 77, Constraints: 0x40, Level: 30}, MP4A{ObjectTypeIndication: 0x40, AudioObjectType: 2}}, cs)
+	require.Equal(t, "avc1.4d401e,mp4a.40.2", FormatList(cs))
+
+	_, err = ParseList("avc1.64001f,")
+	require.Error(t, err)
+}
@@ -0,0 +1,80 @@
+package mpd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"reflect"
+	"sort"
+)
+
+// CompareOptions configures Equal.
+type CompareOptions struct {
+	// IgnoreOrder treats the order of AdaptationSet, Representation and ContentProtection elements
+	// as insignificant.
+	IgnoreOrder bool
+}
+
+// Equal reports whether a and b describe the same MPD.
+func Equal(a, b *MPD, opts CompareOptions) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	// modifyMPD makes a deep copy, so it's safe to reorder it
+	am, bm := modifyMPD(a), modifyMPD(b)
+	normalizeDateTimes(am)
+	normalizeDateTimes(bm)
+	if opts.IgnoreOrder {
+		sortMPDMarshal(am)
+		sortMPDMarshal(bm)
+	}
+	return reflect.DeepEqual(am, bm)
+}
+
+// normalizeDateTimes replaces xsd:dateTime attributes with their encoded values,
+// so decoded and constructed times are compared the way they are written.
+func normalizeDateTimes(m *mpdMarshal) {
+	for _, t := range []**DateTime{&m.AvailabilityStartTime, &m.AvailabilityEndTime, &m.PublishTime} {
+		if *t != nil {
+			*t = &DateTime{raw: (*t).value()}
+		}
+	}
+}
+
+func sortMPDMarshal(m *mpdMarshal) {
+	for _, p := range m.Period {
+		for _, as := range p.AdaptationSets {
+			sortByXML(as.ContentProtections)
+			for _, r := range as.Representations {
+				sortByXML(r.ContentProtections)
+			}
+			sortByXML(as.Representations)
+		}
+		sortByXML(p.AdaptationSets)
+	}
+}
+
+// sortByXML sorts slice by encoded XML of its elements.
+func sortByXML(slice interface{}) {
+	v := reflect.ValueOf(slice)
+	keys := make([][]byte, v.Len())
+	for i := range keys {
+		// all elements are plain structs, encoding can't fail
+		keys[i], _ = xml.Marshal(v.Index(i).Interface())
+	}
+
+	swap := reflect.Swapper(slice)
+	sort.Sort(&xmlSorter{keys: keys, swap: swap})
+}
+
+type xmlSorter struct {
+	keys [][]byte
+	swap func(i, j int)
+}
+
+func (s *xmlSorter) Len() int           { return len(s.keys) }
+func (s *xmlSorter) Less(i, j int) bool { return bytes.Compare(s.keys[i], s.keys[j]) < 0 }
+func (s *xmlSorter) Swap(i, j int) {
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+	s.swap(i, j)
+}
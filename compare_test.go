@@ -0,0 +1,31 @@
+package mpd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEqual(t *testing.T) {
+	a := decodeFixture(t, "fixture_flussonic_live.mpd")
+	b := decodeFixture(t, "fixture_flussonic_live.mpd")
+	require.True(t, Equal(a, b, CompareOptions{}))
+	require.True(t, Equal(nil, nil, CompareOptions{}))
+	require.False(t, Equal(a, nil, CompareOptions{}))
+
+	as := b.Period[0].AdaptationSets
+	as[0], as[1] = as[1], as[0]
+	rs := as[1].Representations
+	rs[0], rs[3] = rs[3], rs[0]
+	cp := as[0].ContentProtections
+	cp[0], cp[1] = cp[1], cp[0]
+	require.False(t, Equal(a, b, CompareOptions{}))
+	require.True(t, Equal(a, b, CompareOptions{IgnoreOrder: true}))
+
+	// comparison must not reorder the arguments
+	require.Equal(t, "audio/mp4", b.Period[0].AdaptationSets[0].MimeType)
+	require.Equal(t, "tracks-v4", *b.Period[0].AdaptationSets[1].Representations[0].ID)
+
+	*rs[1].Bandwidth++
+	require.False(t, Equal(a, b, CompareOptions{IgnoreOrder: true}))
+}
@@ -0,0 +1,114 @@
+package mpd
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DOT renders MPD structure (periods, adaptation sets and representations with their key attributes)
+// as Graphviz DOT graph.
+func (m *MPD) DOT() []byte {
+	b := new(bytes.Buffer)
+	b.WriteString("digraph MPD {\n")
+	b.WriteString("  node [shape=box, fontname=\"Helvetica\"];\n")
+
+	writeDOTNode(b, "mpd", "MPD",
+		dotAttr("id", m.ID),
+		dotAttr("type", (*string)(m.Type)),
+		dotAttr("profiles", (*string)(&m.Profiles)),
+		dotAttr("duration", m.MediaPresentationDuration),
+		dotAttr("minBufferTime", m.MinBufferTime),
+	)
+
+	for pi, p := range m.Period {
+		pID := fmt.Sprintf("p%d", pi)
+		writeDOTNode(b, pID, "Period",
+			dotAttr("id", p.ID),
+			dotAttr("start", p.Start),
+			dotAttr("duration", p.Duration),
+		)
+		writeDOTEdge(b, "mpd", pID)
+
+		for ai, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			asID := fmt.Sprintf("%s_as%d", pID, ai)
+			writeDOTNode(b, asID, "AdaptationSet",
+				dotAttr("mimeType", &as.MimeType),
+				dotAttr("lang", as.Lang),
+				dotAttr("codecs", as.Codecs),
+				dotDRM(as.ContentProtections),
+			)
+			writeDOTEdge(b, pID, asID)
+
+			for ri, r := range as.Representations {
+				rID := fmt.Sprintf("%s_r%d", asID, ri)
+				writeDOTNode(b, rID, "Representation",
+					dotAttr("id", r.ID),
+					dotUint("bandwidth", r.Bandwidth),
+					dotResolution(r.Width, r.Height),
+					dotAttr("frameRate", (*string)(r.FrameRate)),
+					dotAttr("codecs", r.Codecs),
+					dotDRM(r.ContentProtections),
+				)
+				writeDOTEdge(b, asID, rID)
+			}
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+func writeDOTNode(b *bytes.Buffer, id, title string, lines ...string) {
+	label := []string{title}
+	for _, l := range lines {
+		if l != "" {
+			label = append(label, l)
+		}
+	}
+	for i := range label {
+		label[i] = dotEscape(label[i])
+	}
+	fmt.Fprintf(b, "  %s [label=\"%s\"];\n", id, strings.Join(label, `\n`))
+}
+
+func writeDOTEdge(b *bytes.Buffer, from, to string) {
+	fmt.Fprintf(b, "  %s -> %s;\n", from, to)
+}
+
+func dotAttr(name string, value *string) string {
+	if value == nil || *value == "" {
+		return ""
+	}
+	return name + "=" + *value
+}
+
+func dotUint(name string, value *uint64) string {
+	if value == nil {
+		return ""
+	}
+	return name + "=" + strconv.FormatUint(*value, 10)
+}
+
+func dotResolution(width, height *uint64) string {
+	if width == nil || height == nil {
+		return ""
+	}
+	return fmt.Sprintf("%dx%d", *width, *height)
+}
+
+func dotDRM(ds []DRMDescriptor) string {
+	if len(ds) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("ContentProtection=%d", len(ds))
+}
+
+func dotEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, `"`, `\"`)
+}
@@ -0,0 +1,26 @@
+package mpd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDOT(t *testing.T) {
+	m := decodeFixture(t, "fixture_flussonic_live.mpd")
+	dot := string(m.DOT())
+
+	require.True(t, strings.HasPrefix(dot, "digraph MPD {\n"))
+	require.True(t, strings.HasSuffix(dot, "}\n"))
+	require.Contains(t, dot, `mpd [label="MPD\nid=dash\ntype=dynamic\nprofiles=urn:mpeg:dash:profile:isoff-live:2011\nminBufferTime=PT17S"];`)
+	require.Contains(t, dot, `p0 [label="Period\nid=1631853774\nstart=PT0S"];`)
+	require.Contains(t, dot, `p0_as1 [label="AdaptationSet\nmimeType=audio/mp4\nlang=rus\nContentProtection=2"];`)
+	require.Contains(t, dot, `p0_as0_r3 [label="Representation\nid=tracks-v4\nbandwidth=2332000\n768x432\nframeRate=25\ncodecs=avc1.64001e"];`)
+	require.Contains(t, dot, "  p0 -> p0_as0;\n")
+	require.Contains(t, dot, "  p0_as0 -> p0_as0_r3;\n")
+}
+
+func TestDOTEscape(t *testing.T) {
+	require.Equal(t, `a\"b\\c`, dotEscape(`a"b\c`))
+}
@@ -0,0 +1,411 @@
+package mpd
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// CENCNamespace is a namespace of cenc:default_KID attribute and cenc:pssh element.
+const CENCNamespace = "urn:mpeg:cenc:2013"
+
+// MP4ProtectionScheme is schemeIdUri of ContentProtection signaling common encryption (ISO/IEC 23001-7).
+const MP4ProtectionScheme = "urn:mpeg:dash:mp4protection:2011"
+
+// DRM system IDs.
+const (
+	WidevineSystemID  = "edef8ba9-79d6-4ace-a3c8-27dcd51d21ed"
+	PlayReadySystemID = "9a04f079-9840-4286-ab92-e65be0885f95"
+	FairPlaySystemID  = "94ce86fb-07ff-4f43-adb8-93d2fa968ca2"
+	ClearKeySystemID  = "e2719d58-a985-b3c9-781a-b030af78d30e"
+	MarlinSystemID    = "5e629af5-38da-4063-8977-97ffbd9902d4"
+)
+
+// SystemIDScheme returns schemeIdUri of ContentProtection of DRM system with systemID.
+func SystemIDScheme(systemID string) string {
+	return "urn:uuid:" + systemID
+}
+
+// NewCENCProtection returns ContentProtection signaling common encryption with "cenc" scheme and default KID.
+func NewCENCProtection(kid string) DRMDescriptor {
+	return DRMDescriptor{
+		SchemeIDURI:    stringPtrOf(MP4ProtectionScheme),
+		Value:          stringPtrOf("cenc"),
+		CencDefaultKID: stringPtrOf(kid),
+		Cenc:           stringPtrOf(CENCNamespace),
+	}
+}
+
+// NewWidevineProtection returns Widevine ContentProtection, pssh is a PSSH box and can be empty.
+func NewWidevineProtection(pssh []byte) DRMDescriptor {
+	return DRMDescriptor{
+		SchemeIDURI: stringPtrOf(SystemIDScheme(WidevineSystemID)),
+		Pssh:        newPssh(pssh),
+	}
+}
+
+// NewPlayReadyProtection returns PlayReady ContentProtection, pssh is a PSSH box and pro is a PlayReady Object,
+// both can be empty.
+func NewPlayReadyProtection(pssh, pro []byte) DRMDescriptor {
+	d := DRMDescriptor{
+		SchemeIDURI: stringPtrOf(SystemIDScheme(PlayReadySystemID)),
+		Value:       stringPtrOf("MSPR 2.0"),
+		Pssh:        newPssh(pssh),
+	}
+	if len(pro) > 0 {
+		d.Pro = &Pro{
+			MSPR:  stringPtrOf(MSPRNamespace),
+			Value: stringPtrOf(base64.StdEncoding.EncodeToString(pro)),
+		}
+	}
+	return d
+}
+
+// NewClearKeyProtection returns ClearKey ContentProtection with license acquisition URL laurl, which can be empty.
+func NewClearKeyProtection(laurl string) DRMDescriptor {
+	d := DRMDescriptor{
+		SchemeIDURI: stringPtrOf(SystemIDScheme(ClearKeySystemID)),
+		Value:       stringPtrOf("ClearKey1.0"),
+	}
+	if laurl != "" {
+		d.Laurl = &Laurl{
+			DashIF: stringPtrOf(DashIFCPSNamespace),
+			Value:  stringPtrOf(laurl),
+		}
+	}
+	return d
+}
+
+func newPssh(b []byte) *Pssh {
+	if len(b) == 0 {
+		return nil
+	}
+	return &Pssh{
+		Cenc:  stringPtrOf(CENCNamespace),
+		Value: stringPtrOf(base64.StdEncoding.EncodeToString(b)),
+	}
+}
+
+// SetMultiDRM replaces ContentProtection elements of AdaptationSet with common encryption one carrying
+// default kid, followed by Widevine, PlayReady and ClearKey ones. pssh maps DRM system IDs to PSSH boxes
+// put to the corresponding ContentProtection, missing boxes are omitted.
+func (as *AdaptationSet) SetMultiDRM(kid string, pssh map[string][]byte) {
+	as.ContentProtections = []DRMDescriptor{
+		NewCENCProtection(NormalizeKID(kid)),
+		NewWidevineProtection(pssh[WidevineSystemID]),
+		NewPlayReadyProtection(pssh[PlayReadySystemID], nil),
+		NewClearKeyProtection(""),
+	}
+	as.ContentProtections[3].Pssh = newPssh(pssh[ClearKeySystemID])
+}
+
+// KIDs returns cenc:default_KID values of all ContentProtections of MPD in document order, normalized
+// with NormalizeKID and deduplicated.
+func (m *MPD) KIDs() []string {
+	var res []string
+	seen := map[string]bool{}
+	add := func(kids []string) {
+		for _, kid := range kids {
+			if !seen[kid] {
+				seen[kid] = true
+				res = append(res, kid)
+			}
+		}
+	}
+	for _, p := range m.Period {
+		for _, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			add(descriptorKIDs(as.ContentProtections))
+			for _, r := range as.Representations {
+				add(descriptorKIDs(r.ContentProtections))
+			}
+		}
+	}
+	return res
+}
+
+// RepresentationKIDs returns normalized cenc:default_KID values applying to Representation r of AdaptationSet:
+// ones of r itself and ones inherited from AdaptationSet.
+func (as *AdaptationSet) RepresentationKIDs(r *Representation) []string {
+	var res []string
+	seen := map[string]bool{}
+	for _, kid := range append(descriptorKIDs(as.ContentProtections), descriptorKIDs(r.ContentProtections)...) {
+		if !seen[kid] {
+			seen[kid] = true
+			res = append(res, kid)
+		}
+	}
+	return res
+}
+
+// NormalizeKID formats KID as lowercase UUID with dashes, e.g. "3BC6927192DE4ADC8110F4A4BBDD759C" becomes
+// "3bc69271-92de-4adc-8110-f4a4bbdd759c". Values which aren't UUIDs are returned unchanged.
+func NormalizeKID(kid string) string {
+	b, err := hex.DecodeString(strings.NewReplacer("-", "", "{", "", "}", "").Replace(strings.TrimSpace(kid)))
+	if err != nil || len(b) != 16 {
+		return kid
+	}
+	return formatUUID(b)
+}
+
+// ParseKID parses KID in UUID form with dashes (e.g. "3BC69271-92DE-4ADC-8110-F4A4BBDD759C"),
+// as required by cenc:default_KID, into its 16 bytes.
+func ParseKID(kid string) ([16]byte, error) {
+	var res [16]byte
+	invalid := fmt.Errorf("invalid KID %q", kid)
+	if len(kid) != 36 {
+		return res, invalid
+	}
+	for _, i := range []int{8, 13, 18, 23} {
+		if kid[i] != '-' {
+			return res, invalid
+		}
+	}
+	b, err := hex.DecodeString(strings.ReplaceAll(kid, "-", ""))
+	if err != nil || len(b) != 16 {
+		return res, invalid
+	}
+	copy(res[:], b)
+	return res, nil
+}
+
+// FormatKID formats 16 bytes KID as lowercase UUID with dashes.
+func FormatKID(kid [16]byte) string {
+	return formatUUID(kid[:])
+}
+
+// validateKIDs checks that cenc:default_KID values are well-formed.
+func validateKIDs(m *MPD) []Finding {
+	var res []Finding
+	check := func(path string, ds []DRMDescriptor) {
+		for i, d := range ds {
+			if d.CencDefaultKID == nil {
+				continue
+			}
+			if _, err := ParseKID(*d.CencDefaultKID); err != nil {
+				res = append(res, Finding{
+					Severity: SeverityError,
+					Path:     fmt.Sprintf("%s/ContentProtection[%d]@cenc:default_KID", path, i),
+					Message:  err.Error(),
+				})
+			}
+		}
+	}
+	for pi, p := range m.Period {
+		for ai, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			check(adaptationSetPath(pi, ai), as.ContentProtections)
+			for ri, r := range as.Representations {
+				check(representationPath(pi, ai, ri), r.ContentProtections)
+			}
+		}
+	}
+	return res
+}
+
+func descriptorKIDs(ds []DRMDescriptor) []string {
+	var res []string
+	for _, d := range ds {
+		if d.CencDefaultKID != nil && *d.CencDefaultKID != "" {
+			res = append(res, NormalizeKID(*d.CencDefaultKID))
+		}
+	}
+	return res
+}
+
+// SystemID returns normalized DRM system ID of ContentProtection with "urn:uuid:" schemeIdUri, or an empty string.
+func (d *DRMDescriptor) SystemID() string {
+	if d.SchemeIDURI == nil || len(*d.SchemeIDURI) <= len("urn:uuid:") || !strings.EqualFold((*d.SchemeIDURI)[:len("urn:uuid:")], "urn:uuid:") {
+		return ""
+	}
+	return NormalizeKID((*d.SchemeIDURI)[len("urn:uuid:"):])
+}
+
+// FilterDRM keeps only ContentProtection elements of DRM systems with systemIDs in AdaptationSets and Representations,
+// e.g. m.FilterDRM(WidevineSystemID). ContentProtections which don't identify DRM system (like the mp4protection one
+// carrying default KID) are kept.
+func (m *MPD) FilterDRM(systemIDs ...string) {
+	keep := map[string]bool{}
+	for _, id := range systemIDs {
+		keep[NormalizeKID(id)] = true
+	}
+	filter := func(ds []DRMDescriptor) []DRMDescriptor {
+		if ds == nil {
+			return nil
+		}
+		res := ds[:0]
+		for _, d := range ds {
+			if id := d.SystemID(); id == "" || keep[id] {
+				res = append(res, d)
+			}
+		}
+		if len(res) == 0 {
+			return nil
+		}
+		return res
+	}
+	for _, p := range m.Period {
+		for _, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			as.ContentProtections = filter(as.ContentProtections)
+			for ri := range as.Representations {
+				as.Representations[ri].ContentProtections = filter(as.Representations[ri].ContentProtections)
+			}
+		}
+	}
+}
+
+// ContentProtectionByRefID returns ContentProtection of AdaptationSets or Representations with refId id, or nil.
+func (m *MPD) ContentProtectionByRefID(id string) *DRMDescriptor {
+	find := func(ds []DRMDescriptor) *DRMDescriptor {
+		for i := range ds {
+			if ds[i].RefID != nil && *ds[i].RefID == id {
+				return &ds[i]
+			}
+		}
+		return nil
+	}
+	for _, p := range m.Period {
+		for _, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			if d := find(as.ContentProtections); d != nil {
+				return d
+			}
+			for _, r := range as.Representations {
+				if d := find(r.ContentProtections); d != nil {
+					return d
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ResolveContentProtection returns ContentProtection referenced by d@ref, or d itself if it has no ref.
+// It returns nil if the referenced ContentProtection doesn't exist.
+func (m *MPD) ResolveContentProtection(d *DRMDescriptor) *DRMDescriptor {
+	if d.Ref == nil {
+		return d
+	}
+	return m.ContentProtectionByRefID(*d.Ref)
+}
+
+// validateContentProtectionRefs checks that ContentProtection refId values are unique and ref values refer to them.
+func validateContentProtectionRefs(m *MPD) []Finding {
+	var res []Finding
+	ids := map[string]bool{}
+	type ref struct {
+		path, id string
+	}
+	var refs []ref
+	check := func(path string, ds []DRMDescriptor) {
+		for i, d := range ds {
+			path := fmt.Sprintf("%s/ContentProtection[%d]", path, i)
+			if d.RefID != nil {
+				if ids[*d.RefID] {
+					res = append(res, Finding{Severity: SeverityError, Path: path, Message: fmt.Sprintf("duplicate refId %q", *d.RefID)})
+				}
+				ids[*d.RefID] = true
+			}
+			if d.Ref != nil {
+				refs = append(refs, ref{path, *d.Ref})
+			}
+		}
+	}
+	for pi, p := range m.Period {
+		for ai, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			check(adaptationSetPath(pi, ai), as.ContentProtections)
+			for ri, r := range as.Representations {
+				check(representationPath(pi, ai, ri), r.ContentProtections)
+			}
+		}
+	}
+	// ContentProtection may refer to one defined later
+	for _, r := range refs {
+		if !ids[r.id] {
+			res = append(res, Finding{Severity: SeverityError, Path: r.path, Message: fmt.Sprintf("ref %q refers to unknown ContentProtection", r.id)})
+		}
+	}
+	return res
+}
+
+// drmNamespaces are namespaces used by ContentProtection only.
+var drmNamespaces = map[string]bool{
+	CENCNamespace:      true,
+	MSPRNamespace:      true,
+	MASNamespace:       true,
+	DashIFCPSNamespace: true,
+	ClearKeyNamespace:  true,
+}
+
+// StripDRM removes all ContentProtection elements, producing a clear MPD. Attributes and extension elements
+// of DRM namespaces (cenc, mspr, mas, dashif, clearkey) and declarations of these namespaces are removed too.
+func (m *MPD) StripDRM() {
+	m.ExtensionAttrs = stripDRMAttrs(m.ExtensionAttrs)
+	m.Extensions = stripDRMExtensions(m.Extensions)
+	for pi := range m.Period {
+		p := &m.Period[pi]
+		p.ExtensionAttrs = stripDRMAttrs(p.ExtensionAttrs)
+		p.Extensions = stripDRMExtensions(p.Extensions)
+		for _, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			as.ContentProtections = nil
+			as.ExtensionAttrs = stripDRMAttrs(as.ExtensionAttrs)
+			as.Extensions = stripDRMExtensions(as.Extensions)
+			for ri := range as.Representations {
+				r := &as.Representations[ri]
+				r.ContentProtections = nil
+				r.ExtensionAttrs = stripDRMAttrs(r.ExtensionAttrs)
+				r.Extensions = stripDRMExtensions(r.Extensions)
+			}
+		}
+	}
+}
+
+func stripDRMAttrs(attrs []xml.Attr) []xml.Attr {
+	if attrs == nil {
+		return nil
+	}
+	res := attrs[:0]
+	for _, a := range attrs {
+		if drmNamespaces[a.Name.Space] || (a.Name.Space == "xmlns" && drmNamespaces[a.Value]) {
+			continue
+		}
+		res = append(res, a)
+	}
+	if len(res) == 0 {
+		return nil
+	}
+	return res
+}
+
+func stripDRMExtensions(es []Extension) []Extension {
+	if es == nil {
+		return nil
+	}
+	res := es[:0]
+	for _, e := range es {
+		if !drmNamespaces[e.XMLName.Space] {
+			res = append(res, e)
+		}
+	}
+	if len(res) == 0 {
+		return nil
+	}
+	return res
+}
@@ -0,0 +1,165 @@
+package mpd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProtections(t *testing.T) {
+	as := &AdaptationSet{ContentProtections: []DRMDescriptor{
+		NewCENCProtection("10000000-1000-1000-1000-100000000001"),
+		NewWidevineProtection([]byte("pssh")),
+		NewPlayReadyProtection(nil, []byte("pro")),
+		NewClearKeyProtection("https://example.com/license"),
+	}}
+	b, err := (&MPD{Period: []Period{{AdaptationSets: []*AdaptationSet{as}}}}).Encode()
+	require.NoError(t, err)
+	for _, s := range []string{
+		`<ContentProtection schemeIdUri="urn:mpeg:dash:mp4protection:2011" value="cenc" cenc:default_KID="10000000-1000-1000-1000-100000000001" xmlns:cenc="urn:mpeg:cenc:2013"/>`,
+		`<ContentProtection schemeIdUri="urn:uuid:edef8ba9-79d6-4ace-a3c8-27dcd51d21ed">`,
+		`<cenc:pssh xmlns:cenc="urn:mpeg:cenc:2013">cHNzaA==</cenc:pssh>`,
+		`<ContentProtection schemeIdUri="urn:uuid:9a04f079-9840-4286-ab92-e65be0885f95" value="MSPR 2.0">`,
+		`<mspr:pro xmlns:mspr="urn:microsoft:playready">cHJv</mspr:pro>`,
+		`<ContentProtection schemeIdUri="urn:uuid:e2719d58-a985-b3c9-781a-b030af78d30e" value="ClearKey1.0">`,
+		`<dashif:laurl xmlns:dashif="https://dashif.org/CPS">https://example.com/license</dashif:laurl>`,
+	} {
+		require.Contains(t, string(b), s)
+	}
+
+	require.Nil(t, NewWidevineProtection(nil).Pssh)
+	require.Nil(t, NewClearKeyProtection("").Laurl)
+}
+
+func TestKIDs(t *testing.T) {
+	m := decodeFixture(t, "fixture_elemental_delta_live.mpd")
+	require.Equal(t, []string{
+		"6b909cbd-977e-5ec2-b234-1bbc87e7405e",
+		"cdec59ed-5552-5f2c-a219-be26188c0e14",
+		"11309fff-f99f-59e1-b440-24b780ec91d0",
+	}, m.KIDs())
+
+	as := m.Period[0].AdaptationSets[0]
+	require.Equal(t, []string{"6b909cbd-977e-5ec2-b234-1bbc87e7405e"}, as.RepresentationKIDs(&as.Representations[0]))
+
+	as = &AdaptationSet{ContentProtections: []DRMDescriptor{NewCENCProtection("{10000000100010001000100000000001}")}}
+	r := &Representation{ContentProtections: []DRMDescriptor{
+		NewCENCProtection("10000000-1000-1000-1000-100000000001"),
+		NewCENCProtection("20000000-2000-2000-2000-200000000002"),
+	}}
+	require.Equal(t, []string{"10000000-1000-1000-1000-100000000001", "20000000-2000-2000-2000-200000000002"}, as.RepresentationKIDs(r))
+	require.Empty(t, (&MPD{}).KIDs())
+}
+
+func TestNormalizeKID(t *testing.T) {
+	require.Equal(t, "3bc69271-92de-4adc-8110-f4a4bbdd759c", NormalizeKID("3BC69271-92DE-4ADC-8110-F4A4BBDD759C"))
+	require.Equal(t, "3bc69271-92de-4adc-8110-f4a4bbdd759c", NormalizeKID(" 3bc6927192de4adc8110f4a4bbdd759c"))
+	require.Equal(t, "not-a-kid", NormalizeKID("not-a-kid"))
+}
+
+func TestStripDRM(t *testing.T) {
+	m := decodeFixture(t, "fixture_elemental_delta_vod_multi_drm.mpd")
+	m.StripDRM()
+	b, err := m.Encode()
+	require.NoError(t, err)
+	require.NotContains(t, string(b), "ContentProtection")
+	require.NotContains(t, string(b), CENCNamespace)
+	require.Len(t, m.Period[0].AdaptationSets[0].Representations, 5)
+
+	m = &MPD{
+		ExtensionAttrs: []xml.Attr{
+			{Name: xml.Name{Space: "xmlns", Local: "cenc"}, Value: CENCNamespace},
+			{Name: xml.Name{Space: "xmlns", Local: "ext"}, Value: "urn:example"},
+		},
+		Period: []Period{{AdaptationSets: []*AdaptationSet{{
+			ContentProtections: []DRMDescriptor{NewCENCProtection("10000000-1000-1000-1000-100000000001")},
+			Representations: []Representation{{
+				ExtensionAttrs: []xml.Attr{{Name: xml.Name{Space: CENCNamespace, Local: "default_KID"}, Value: "x"}},
+				Extensions: []Extension{
+					{XMLName: xml.Name{Space: CENCNamespace, Local: "pssh"}},
+					{XMLName: xml.Name{Space: "urn:example", Local: "Info"}},
+				},
+			}},
+		}}}},
+	}
+	m.StripDRM()
+	require.Equal(t, []xml.Attr{{Name: xml.Name{Space: "xmlns", Local: "ext"}, Value: "urn:example"}}, m.ExtensionAttrs)
+	as := m.Period[0].AdaptationSets[0]
+	require.Nil(t, as.ContentProtections)
+	require.Nil(t, as.Representations[0].ExtensionAttrs)
+	require.Equal(t, []Extension{{XMLName: xml.Name{Space: "urn:example", Local: "Info"}}}, as.Representations[0].Extensions)
+}
+
+func TestFilterDRM(t *testing.T) {
+	m := decodeFixture(t, "fixture_elemental_delta_vod_multi_drm.mpd")
+	m.FilterDRM(strings.ToUpper(WidevineSystemID))
+	for _, as := range m.Period[0].AdaptationSets {
+		for _, r := range as.Representations {
+			require.Len(t, r.ContentProtections, 2)
+			require.Equal(t, "", r.ContentProtections[0].SystemID())
+			require.Equal(t, WidevineSystemID, r.ContentProtections[1].SystemID())
+		}
+	}
+
+	m.FilterDRM()
+	for _, as := range m.Period[0].AdaptationSets {
+		for _, r := range as.Representations {
+			require.Len(t, r.ContentProtections, 1)
+		}
+	}
+}
+
+func TestSetMultiDRM(t *testing.T) {
+	as := &AdaptationSet{ContentProtections: []DRMDescriptor{NewCENCProtection("old")}}
+	as.SetMultiDRM("3BC6927192DE4ADC8110F4A4BBDD759C", map[string][]byte{
+		WidevineSystemID:  []byte("widevine"),
+		PlayReadySystemID: []byte("playready"),
+	})
+	require.Len(t, as.ContentProtections, 4)
+	require.Equal(t, []string{"3bc69271-92de-4adc-8110-f4a4bbdd759c"}, as.RepresentationKIDs(&Representation{}))
+	require.Equal(t, "d2lkZXZpbmU=", *as.ContentProtections[1].Pssh.Value)
+	require.Equal(t, "cGxheXJlYWR5", *as.ContentProtections[2].Pssh.Value)
+	require.Nil(t, as.ContentProtections[3].Pssh)
+
+	b, err := (&MPD{Period: []Period{{AdaptationSets: []*AdaptationSet{as}}}}).Encode()
+	require.NoError(t, err)
+	m := new(MPD)
+	require.NoError(t, m.Decode(b))
+	var systems []string
+	for _, d := range m.Period[0].AdaptationSets[0].ContentProtections {
+		systems = append(systems, d.SystemID())
+	}
+	require.Equal(t, []string{"", WidevineSystemID, PlayReadySystemID, ClearKeySystemID}, systems)
+}
+
+func TestParseKID(t *testing.T) {
+	kid, err := ParseKID("3BC69271-92DE-4ADC-8110-F4A4BBDD759C")
+	require.NoError(t, err)
+	require.Equal(t, [16]byte{0x3b, 0xc6, 0x92, 0x71, 0x92, 0xde, 0x4a, 0xdc, 0x81, 0x10, 0xf4, 0xa4, 0xbb, 0xdd, 0x75, 0x9c}, kid)
+	require.Equal(t, "3bc69271-92de-4adc-8110-f4a4bbdd759c", FormatKID(kid))
+
+	for _, s := range []string{"", "3bc6927192de4adc8110f4a4bbdd759c", "3bc69271-92de-4adc-8110-f4a4bbdd759", "3bc69271-92de-4adc-8110-f4a4bbdd759x", "3bc69271492de-4adc-8110-f4a4bbdd759c"} {
+		_, err := ParseKID(s)
+		require.EqualError(t, err, fmt.Sprintf("invalid KID %q", s))
+	}
+}
+
+func TestResolveContentProtection(t *testing.T) {
+	m := decodeFixture(t, "fixture_content_protection_ref.mpd")
+	video, audio := m.Period[0].AdaptationSets[0], m.Period[0].AdaptationSets[1]
+	d := m.ResolveContentProtection(&audio.ContentProtections[1])
+	require.Equal(t, &video.ContentProtections[1], d)
+	require.NotNil(t, d.Pssh)
+	require.Equal(t, &video.ContentProtections[0], m.ResolveContentProtection(&video.ContentProtections[0]))
+
+	audio.ContentProtections[1].Ref = stringPtr("playready")
+	require.Nil(t, m.ResolveContentProtection(&audio.ContentProtections[1]))
+	audio.ContentProtections[0].RefID = stringPtr("widevine")
+	require.Equal(t, []Finding{
+		{SeverityError, "MPD/Period[0]/AdaptationSet[1]/ContentProtection[0]", `duplicate refId "widevine"`},
+		{SeverityError, "MPD/Period[0]/AdaptationSet[1]/ContentProtection[1]", `ref "playready" refers to unknown ContentProtection`},
+	}, m.Validate())
+}
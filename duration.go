@@ -0,0 +1,290 @@
+package mpd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is a value of xsd:duration attribute, like MPD@minBufferTime or Period@duration.
+// Model keeps such attributes as strings to encode them exactly as decoded, ParseDurationAttr and Attr
+// convert them to and from Duration.
+type Duration time.Duration
+
+// ParseDuration parses xsd:duration (ISO 8601 duration, e.g. "PT1M30.5S").
+// Years and months are not fixed length, they are approximated as 365 and 30 days.
+func ParseDuration(s string) (Duration, error) {
+	d, err := parseDuration(s)
+	return Duration(d), err
+}
+
+// ParseDurationAttr parses optional xsd:duration attribute value, nil value is returned as nil Duration.
+func ParseDurationAttr(s *string) (*Duration, error) {
+	if s == nil {
+		return nil, nil
+	}
+	d, err := ParseDuration(*s)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Attr returns d formatted as attribute value, e.g. m.MinBufferTime = Duration(2 * time.Second).Attr().
+func (d Duration) Attr() *string {
+	s := d.String()
+	return &s
+}
+
+// String formats d as xsd:duration with hours, minutes and seconds, e.g. "PT1M30.5S".
+func (d Duration) String() string {
+	if d == 0 {
+		return "PT0S"
+	}
+	var sb strings.Builder
+	v := time.Duration(d)
+	if v < 0 {
+		sb.WriteByte('-')
+		v = -v
+	}
+	sb.WriteString("PT")
+	if h := v / time.Hour; h > 0 {
+		sb.WriteString(strconv.FormatInt(int64(h), 10) + "H")
+		v -= h * time.Hour
+	}
+	if m := v / time.Minute; m > 0 {
+		sb.WriteString(strconv.FormatInt(int64(m), 10) + "M")
+		v -= m * time.Minute
+	}
+	if v > 0 {
+		sb.WriteString(strconv.FormatFloat(v.Seconds(), 'f', -1, 64) + "S")
+	}
+	return sb.String()
+}
+
+// MarshalXMLAttr encodes Duration.
+func (d Duration) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: d.String()}, nil
+}
+
+// UnmarshalXMLAttr decodes Duration.
+func (d *Duration) UnmarshalXMLAttr(attr xml.Attr) error {
+	v, err := parseDuration(attr.Value)
+	if err != nil {
+		return fmt.Errorf("Duration: can't UnmarshalXMLAttr %#v", attr)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON encodes Duration as xsd:duration string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes Duration from xsd:duration string.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("Duration: can't UnmarshalJSON %s", b)
+	}
+	v, err := parseDuration(s)
+	if err != nil {
+		return fmt.Errorf("Duration: can't UnmarshalJSON %s", b)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// check interfaces
+var (
+	_ xml.MarshalerAttr   = Duration(0)
+	_ xml.UnmarshalerAttr = new(Duration)
+	_ json.Marshaler      = Duration(0)
+	_ json.Unmarshaler    = new(Duration)
+)
+
+// parseDuration parses xsd:duration (ISO 8601 duration, e.g. "PT1M30.5S").
+// Years and months are not fixed length, they are approximated as 365 and 30 days.
+func parseDuration(s string) (time.Duration, error) {
+	orig := s
+	var neg bool
+	if strings.HasPrefix(s, "-") {
+		neg = true
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") || len(s) < 2 {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+	s = s[1:]
+
+	const day = 24 * time.Hour
+	var res float64
+	var timePart, any bool
+	for s != "" {
+		if s[0] == 'T' {
+			if timePart || len(s) == 1 {
+				return 0, fmt.Errorf("invalid duration %q", orig)
+			}
+			timePart = true
+			s = s[1:]
+			continue
+		}
+
+		i := strings.IndexAny(s, "YMWDHS")
+		if i <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+		v, err := strconv.ParseFloat(s[:i], 64)
+		if err != nil || v < 0 || strings.ContainsAny(s[:i], "+-eE") {
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+
+		var unit time.Duration
+		switch {
+		case !timePart && s[i] == 'Y':
+			unit = 365 * day
+		case !timePart && s[i] == 'M':
+			unit = 30 * day
+		case !timePart && s[i] == 'W':
+			unit = 7 * day
+		case !timePart && s[i] == 'D':
+			unit = day
+		case timePart && s[i] == 'H':
+			unit = time.Hour
+		case timePart && s[i] == 'M':
+			unit = time.Minute
+		case timePart && s[i] == 'S':
+			unit = time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+		res += v * float64(unit)
+		any = true
+		s = s[i+1:]
+	}
+	if !any || res > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+
+	d := time.Duration(math.Round(res))
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+// parseOptionalDuration parses xsd:duration attribute value, nil value is a zero duration.
+func parseOptionalDuration(s *string) (time.Duration, error) {
+	if s == nil {
+		return 0, nil
+	}
+	return parseDuration(*s)
+}
+
+// DateTime is a value of xsd:dateTime attribute, like MPD@availabilityStartTime or MPD@publishTime.
+// Decoded value keeps its original lexical form, which is written back while the time is unchanged,
+// so Decode/Encode round trip is byte-stable.
+type DateTime struct {
+	time.Time
+	raw string
+}
+
+// NewDateTime returns DateTime attribute value for t.
+func NewDateTime(t time.Time) *DateTime {
+	return &DateTime{Time: t}
+}
+
+// ParseDateTime parses xsd:dateTime, values without time zone are treated as UTC.
+func ParseDateTime(s string) (DateTime, error) {
+	t, err := parseDateTime(s)
+	if err != nil {
+		return DateTime{}, err
+	}
+	return DateTime{Time: t, raw: s}, nil
+}
+
+// String formats t as xsd:dateTime in UTC, e.g. "2021-09-17T04:42:54.5Z".
+func (t DateTime) String() string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// value returns attribute value: the original one if t was decoded and not changed since, String() otherwise.
+func (t DateTime) value() string {
+	if t.raw != "" {
+		if orig, err := parseDateTime(t.raw); err == nil && orig.Equal(t.Time) {
+			return t.raw
+		}
+	}
+	return t.String()
+}
+
+// dateTimeValue returns attribute value of t, empty string for nil.
+func dateTimeValue(t *DateTime) string {
+	if t == nil {
+		return ""
+	}
+	return t.value()
+}
+
+// MarshalXMLAttr encodes DateTime.
+func (t *DateTime) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	// encoding/xml calls it for nil pointers too
+	if t == nil {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: t.value()}, nil
+}
+
+// UnmarshalXMLAttr decodes DateTime.
+func (t *DateTime) UnmarshalXMLAttr(attr xml.Attr) error {
+	v, err := ParseDateTime(attr.Value)
+	if err != nil {
+		return fmt.Errorf("DateTime: can't UnmarshalXMLAttr %#v", attr)
+	}
+	*t = v
+	return nil
+}
+
+// MarshalJSON encodes DateTime as xsd:dateTime string.
+func (t DateTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.value())
+}
+
+// UnmarshalJSON decodes DateTime from xsd:dateTime string.
+func (t *DateTime) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("DateTime: can't UnmarshalJSON %s", b)
+	}
+	v, err := ParseDateTime(s)
+	if err != nil {
+		return fmt.Errorf("DateTime: can't UnmarshalJSON %s", b)
+	}
+	*t = v
+	return nil
+}
+
+// check interfaces
+var (
+	_ xml.MarshalerAttr   = new(DateTime)
+	_ xml.UnmarshalerAttr = new(DateTime)
+	_ json.Marshaler      = DateTime{}
+	_ json.Unmarshaler    = new(DateTime)
+)
+
+// parseDateTime parses xsd:dateTime attribute value.
+// Values without time zone are treated as UTC.
+func parseDateTime(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid dateTime %q", s)
+}
@@ -0,0 +1,126 @@
+package mpd
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDuration(t *testing.T) {
+	for s, expected := range map[string]time.Duration{
+		"PT0S":         0,
+		"PT17S":        17 * time.Second,
+		"PT5.6S":       5600 * time.Millisecond,
+		"PT136.680S":   136680 * time.Millisecond,
+		"PT1M30.5S":    90500 * time.Millisecond,
+		"PT2H":         2 * time.Hour,
+		"P1DT1H":       25 * time.Hour,
+		"P1W":          7 * 24 * time.Hour,
+		"-PT1S":        -time.Second,
+		"PT0H0M10.04S": 10040 * time.Millisecond,
+	} {
+		d, err := parseDuration(s)
+		require.NoError(t, err, s)
+		require.Equal(t, expected, d, s)
+	}
+
+	for _, s := range []string{"", "P", "PT", "T1S", "PT1", "P1H", "PT1D", "PT-1S", "PTS", "P1DT"} {
+		_, err := parseDuration(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestParseDateTime(t *testing.T) {
+	d, err := parseDateTime("2021-09-17T04:42:54Z")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2021, 9, 17, 4, 42, 54, 0, time.UTC), d)
+
+	d, err = parseDateTime("2021-09-17T04:42:54.5")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2021, 9, 17, 4, 42, 54, 500000000, time.UTC), d)
+
+	_, err = parseDateTime("yesterday")
+	require.Error(t, err)
+}
+
+func TestDuration(t *testing.T) {
+	for d, expected := range map[time.Duration]string{
+		0:                               "PT0S",
+		90500 * time.Millisecond:        "PT1M30.5S",
+		2 * time.Hour:                   "PT2H",
+		25*time.Hour + time.Millisecond: "PT25H0.001S",
+		-time.Second:                    "-PT1S",
+		time.Hour + 5*time.Minute:       "PT1H5M",
+		136680 * time.Millisecond:       "PT2M16.68S",
+	} {
+		require.Equal(t, expected, Duration(d).String())
+		parsed, err := ParseDuration(expected)
+		require.NoError(t, err)
+		require.Equal(t, Duration(d), parsed)
+	}
+
+	m := decodeFixture(t, "fixture_elemental_delta_vod.mpd")
+	d, err := ParseDurationAttr(m.MediaPresentationDuration)
+	require.NoError(t, err)
+	require.Equal(t, Duration(136680*time.Millisecond), *d)
+	d, err = ParseDurationAttr(m.MinimumUpdatePeriod)
+	require.NoError(t, err)
+	require.Nil(t, d)
+	_, err = ParseDurationAttr(stringPtr("1s"))
+	require.EqualError(t, err, `invalid duration "1s"`)
+	m.MinBufferTime = Duration(2 * time.Second).Attr()
+	require.Equal(t, "PT2S", *m.MinBufferTime)
+
+	type element struct {
+		XMLName xml.Name `xml:"v"`
+		D       Duration `xml:"d,attr"`
+	}
+	var v element
+	require.NoError(t, xml.Unmarshal([]byte(`<v d="PT1M30.5S"/>`), &v))
+	require.Equal(t, Duration(90500*time.Millisecond), v.D)
+	b, err := xml.Marshal(v)
+	require.NoError(t, err)
+	require.Equal(t, `<v d="PT1M30.5S"></v>`, string(b))
+	require.Error(t, xml.Unmarshal([]byte(`<v d="PT"/>`), &v))
+}
+
+func dateTimePtr(s string) *DateTime {
+	t, err := ParseDateTime(s)
+	if err != nil {
+		panic(err)
+	}
+	return &t
+}
+
+func TestDateTime(t *testing.T) {
+	m := decodeFixture(t, "fixture_inband_event_stream.mpd")
+	require.Equal(t, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), m.AvailabilityStartTime.Time)
+	require.Nil(t, m.PublishTime)
+	_, err := ParseDateTime("yesterday")
+	require.EqualError(t, err, `invalid dateTime "yesterday"`)
+
+	m.PublishTime = NewDateTime(m.AvailabilityStartTime.Add(90500 * time.Millisecond).In(time.FixedZone("", 3600)))
+	require.Equal(t, "2021-01-01T00:01:30.5Z", m.PublishTime.String())
+
+	type element struct {
+		XMLName xml.Name `xml:"v"`
+		T       DateTime `xml:"t,attr"`
+	}
+	var v element
+	require.NoError(t, xml.Unmarshal([]byte(`<v t="2021-09-17T04:42:54.500+03:00"/>`), &v))
+	require.True(t, time.Date(2021, 9, 17, 1, 42, 54, 5e8, time.UTC).Equal(v.T.Time))
+	// original lexical form is kept while the time is unchanged
+	b, err := xml.Marshal(&v)
+	require.NoError(t, err)
+	require.Equal(t, `<v t="2021-09-17T04:42:54.500+03:00"></v>`, string(b))
+	v.T.Time = v.T.Add(time.Second)
+	b, err = xml.Marshal(&v)
+	require.NoError(t, err)
+	require.Equal(t, `<v t="2021-09-17T01:42:55.5Z"></v>`, string(b))
+
+	require.NoError(t, xml.Unmarshal([]byte(`<v t="2021-09-17T04:42:54"/>`), &v))
+	require.Equal(t, time.Date(2021, 9, 17, 4, 42, 54, 0, time.UTC), v.T.Time)
+	require.Error(t, xml.Unmarshal([]byte(`<v t="now"/>`), &v))
+}
@@ -0,0 +1,114 @@
+package mpd
+
+import (
+	"fmt"
+	"time"
+)
+
+// PeriodDuration returns duration of Period with index i: Period@duration, the distance to the next Period start,
+// or the rest of mediaPresentationDuration for the last Period. ok is false if duration is unknown.
+func (m *MPD) PeriodDuration(i int) (d time.Duration, ok bool, err error) {
+	if i < 0 || i >= len(m.Period) {
+		return 0, false, fmt.Errorf("no Period with index %d", i)
+	}
+	if m.Period[i].Duration != nil {
+		d, err = parseDuration(*m.Period[i].Duration)
+		return d, err == nil, err
+	}
+
+	start, err := m.PeriodStart(i)
+	if err != nil {
+		return 0, false, err
+	}
+	var end time.Duration
+	switch {
+	case i+1 < len(m.Period) && m.Period[i+1].Start != nil:
+		if end, err = m.PeriodStart(i + 1); err != nil {
+			return 0, false, err
+		}
+	case i+1 == len(m.Period) && m.MediaPresentationDuration != nil:
+		if end, err = parseDuration(*m.MediaPresentationDuration); err != nil {
+			return 0, false, err
+		}
+	default:
+		return 0, false, nil
+	}
+	return end - start, true, nil
+}
+
+// CheckDurations checks that the sum of segment durations of every Representation of static MPD matches
+// its Period duration, and that the end of the last Period matches mediaPresentationDuration, within tolerance.
+func CheckDurations(m *MPD, tolerance time.Duration) []Finding {
+	if m.IsDynamic() {
+		return nil
+	}
+
+	var res []Finding
+	addError := func(path string, err error) {
+		res = append(res, Finding{Severity: SeverityError, Path: path, Message: err.Error()})
+	}
+
+	for pi, p := range m.Period {
+		expected, ok, err := m.PeriodDuration(pi)
+		if err != nil {
+			addError(periodPath(pi), err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		for ai, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			for ri := range as.Representations {
+				st := p.EffectiveSegmentTemplate(as, &as.Representations[ri])
+				if st == nil || len(st.SegmentTimelineS) == 0 {
+					continue
+				}
+				var ticks uint64
+				for _, s := range st.Segments() {
+					ticks += s.Duration
+				}
+				actual := ticksToDuration(ticks, st.EffectiveTimescale())
+				if diff := absDuration(actual - expected); diff > tolerance {
+					res = append(res, Finding{
+						Severity: SeverityError,
+						Path:     representationPath(pi, ai, ri),
+						Message:  fmt.Sprintf("segments duration %s doesn't match Period duration %s", actual, expected),
+					})
+				}
+			}
+		}
+	}
+
+	if m.MediaPresentationDuration != nil && len(m.Period) > 0 {
+		last := len(m.Period) - 1
+		mpd, err := parseDuration(*m.MediaPresentationDuration)
+		if err != nil {
+			addError("MPD", err)
+			return res
+		}
+		start, err := m.PeriodStart(last)
+		if err != nil {
+			return res
+		}
+		d, ok, err := m.PeriodDuration(last)
+		if err != nil || !ok {
+			return res
+		}
+		if diff := absDuration(start + d - mpd); diff > tolerance {
+			res = append(res, Finding{
+				Severity: SeverityError,
+				Path:     "MPD",
+				Message:  fmt.Sprintf("Periods end at %s, mediaPresentationDuration is %s", start+d, mpd),
+			})
+		}
+	}
+	return res
+}
+
+func validateDurations(m *MPD) []Finding {
+	return CheckDurations(m, DefaultTolerance)
+}
@@ -0,0 +1,49 @@
+package mpd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriodDuration(t *testing.T) {
+	m := &MPD{
+		MediaPresentationDuration: stringPtr("PT60S"),
+		Period: []Period{
+			{Duration: stringPtr("PT10S")},
+			{},
+			{Start: stringPtr("PT30S")},
+		},
+	}
+	for i, expected := range []time.Duration{10 * time.Second, 20 * time.Second, 30 * time.Second} {
+		d, ok, err := m.PeriodDuration(i)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, expected, d)
+	}
+
+	m.MediaPresentationDuration = nil
+	_, ok, err := m.PeriodDuration(2)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestCheckDurations(t *testing.T) {
+	m := decodeFixture(t, "fixture_elemental_delta_vod_multi_drm.mpd")
+	require.Empty(t, CheckDurations(m, DefaultTolerance))
+
+	// truncated encode
+	st := m.Period[0].AdaptationSets[0].Representations[1].SegmentTemplate
+	st.SegmentTimelineS = st.SegmentTimelineS[:1]
+	m.MediaPresentationDuration = stringPtr("PT140S")
+	require.Equal(t, []Finding{
+		{SeverityError, "MPD/Period[0]/AdaptationSet[0]/Representation[1]", "segments duration 2m16s doesn't match Period duration 2m16.68s"},
+		{SeverityError, "MPD", "Periods end at 2m16.68s, mediaPresentationDuration is 2m20s"},
+	}, CheckDurations(m, DefaultTolerance))
+	require.Len(t, CheckDurations(m, time.Second), 1)
+	require.Empty(t, CheckDurations(m, 5*time.Second))
+
+	m.Type = PresentationTypeOf(PresentationTypeDynamic)
+	require.Empty(t, CheckDurations(m, DefaultTolerance))
+}
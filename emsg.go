@@ -0,0 +1,149 @@
+package mpd
+
+import (
+	"encoding/base64"
+	"time"
+
+	copyobj "github.com/mc2soft/mpd/utils"
+)
+
+// Emsg holds fields of a parsed ISO BMFF Event Message Box (emsg) used to correlate in-band events
+// with MPD event streams.
+type Emsg struct {
+	SchemeIDURI string
+	Value       string
+	Timescale   uint32
+	// PresentationTime is an absolute presentation time (version 1 box) in Timescale units.
+	// For version 0 boxes it should be calculated as segment earliest presentation time + presentation_time_delta.
+	PresentationTime uint64
+	// EventDuration in Timescale units, 0xFFFFFFFF means unknown duration.
+	EventDuration uint32
+	ID            uint32
+	MessageData   []byte
+}
+
+// UnknownEmsgDuration is EventDuration value of events with unknown duration.
+const UnknownEmsgDuration = 0xFFFFFFFF
+
+// Matches reports whether emsg belongs to event stream declared with schemeIdUri and optional value:
+// empty value matches any emsg value.
+func (e *Emsg) Matches(schemeIDURI, value string) bool {
+	return e.SchemeIDURI == schemeIDURI && (value == "" || e.Value == value)
+}
+
+// MatchesInbandEventStream reports whether emsg belongs to InbandEventStream d.
+func (e *Emsg) MatchesInbandEventStream(d Descriptor) bool {
+	return e.Matches(stringValue(d.SchemeIDURI), stringValue(d.Value))
+}
+
+// Time returns presentation time of emsg as time.Duration.
+func (e *Emsg) Time() time.Duration {
+	return ticksToDuration(e.PresentationTime, uint64(e.Timescale))
+}
+
+// Duration returns event duration, ok is false if it's unknown.
+func (e *Emsg) Duration() (d time.Duration, ok bool) {
+	if e.EventDuration == UnknownEmsgDuration {
+		return 0, false
+	}
+	return ticksToDuration(uint64(e.EventDuration), uint64(e.Timescale)), true
+}
+
+// Event converts emsg to MPD Event of EventStream with the same schemeIdUri, value and timescale,
+// message data is base64 encoded. PresentationTime is copied as is, use Period.EventForEmsg to place
+// the event on the timeline of a particular EventStream.
+func (e *Emsg) Event() Event {
+	pt := e.PresentationTime
+	id := uint64(e.ID)
+	res := Event{
+		PresentationTime: &pt,
+		ID:               &id,
+	}
+	if e.EventDuration != UnknownEmsgDuration {
+		d := uint64(e.EventDuration)
+		res.Duration = &d
+	}
+	if len(e.MessageData) > 0 {
+		encoding := "base64"
+		data := base64.StdEncoding.EncodeToString(e.MessageData)
+		res.ContentEncoding = &encoding
+		res.MessageData = &data
+	}
+	return res
+}
+
+// EventForEmsg converts emsg found in media segments of Representation r of AdaptationSet as to Event of
+// the matching stream of Period p: EventStream of p or InbandEventStream of as or r with the same schemeIdUri
+// and value. stream points into p.EventStreams, for InbandEventStream it's a new EventStream with its
+// schemeIdUri and value and emsg timescale.
+// emsg PresentationTime is on the Representation media timeline: it is moved to the Period timeline using
+// Representation presentationTimeOffset, then rescaled to the stream timescale and shifted by the stream
+// presentationTimeOffset. ok is false if there is no matching stream or the event starts before the Period.
+func (p *Period) EventForEmsg(as *AdaptationSet, r *Representation, e *Emsg) (stream *EventStream, ev Event, ok bool) {
+	for i := range p.EventStreams {
+		if es := &p.EventStreams[i]; e.Matches(stringValue(es.SchemeIDURI), stringValue(es.Value)) {
+			stream = es
+			break
+		}
+	}
+	if stream == nil {
+		var inband []Descriptor
+		if as != nil {
+			inband = append(inband, as.InbandEventStreams...)
+		}
+		if r != nil {
+			inband = append(inband, r.InbandEventStreams...)
+		}
+		for _, d := range inband {
+			if e.MatchesInbandEventStream(d) {
+				timescale := uint64(e.Timescale)
+				stream = &EventStream{
+					SchemeIDURI: copyobj.String(d.SchemeIDURI),
+					Value:       copyobj.String(d.Value),
+					Timescale:   &timescale,
+				}
+				break
+			}
+		}
+	}
+	if stream == nil {
+		return nil, Event{}, false
+	}
+
+	pto, ptoTimescale := p.representationPresentationTimeOffset(as, r)
+	start := e.Time() - ticksToDuration(pto, ptoTimescale)
+	if start < 0 {
+		return nil, Event{}, false
+	}
+
+	timescale := uint64(1)
+	if stream.Timescale != nil {
+		timescale = *stream.Timescale
+	}
+	ev = e.Event()
+	pt := durationToTicks(start, timescale)
+	if stream.PresentationTimeOffset != nil {
+		pt += *stream.PresentationTimeOffset
+	}
+	ev.PresentationTime = &pt
+	if d, known := e.Duration(); known {
+		duration := durationToTicks(d, timescale)
+		ev.Duration = &duration
+	}
+	return stream, ev, true
+}
+
+// representationPresentationTimeOffset returns effective presentationTimeOffset of Representation r and its
+// timescale from SegmentTemplate, SegmentList or SegmentBase, zero if there are none.
+func (p *Period) representationPresentationTimeOffset(as *AdaptationSet, r *Representation) (pto, timescale uint64) {
+	if st := p.EffectiveSegmentTemplate(as, r); st != nil {
+		return st.EffectivePresentationTimeOffset(), st.EffectiveTimescale()
+	}
+	if sl := p.EffectiveSegmentList(as, r); sl != nil {
+		return sl.EffectivePresentationTimeOffset(), sl.EffectiveTimescale()
+	}
+	if sb := p.EffectiveSegmentBase(as, r); sb != nil {
+		return sb.EffectivePresentationTimeOffset(), sb.EffectiveTimescale()
+	}
+	return 0, 1
+}
@@ -0,0 +1,102 @@
+package mpd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmsg(t *testing.T) {
+	e := &Emsg{
+		SchemeIDURI:      "urn:scte:scte35:2013:bin",
+		Value:            "1",
+		Timescale:        90000,
+		PresentationTime: 900000,
+		EventDuration:    2700000,
+	}
+	require.True(t, e.Matches("urn:scte:scte35:2013:bin", ""))
+	require.True(t, e.Matches("urn:scte:scte35:2013:bin", "1"))
+	require.False(t, e.Matches("urn:scte:scte35:2013:bin", "2"))
+	require.False(t, e.Matches("urn:mpeg:dash:event:2012", ""))
+
+	m := decodeFixture(t, "fixture_inband_event_stream.mpd")
+	streams := m.Period[0].AdaptationSets[0].InbandEventStreams
+	require.True(t, e.MatchesInbandEventStream(streams[0]))
+	require.False(t, e.MatchesInbandEventStream(streams[1]))
+
+	require.Equal(t, 10*time.Second, e.Time())
+	d, ok := e.Duration()
+	require.True(t, ok)
+	require.Equal(t, 30*time.Second, d)
+
+	e.EventDuration = UnknownEmsgDuration
+	_, ok = e.Duration()
+	require.False(t, ok)
+}
+
+func TestEmsgEvent(t *testing.T) {
+	e := &Emsg{Timescale: 1000, PresentationTime: 5100, EventDuration: 1000, ID: 3, MessageData: []byte("hello")}
+	m := decodeFixture(t, "fixture_event_stream.mpd")
+	require.Equal(t, m.Period[0].EventStreams[1].Events[1], e.Event())
+
+	e.EventDuration = UnknownEmsgDuration
+	e.MessageData = nil
+	require.Equal(t, Event{PresentationTime: uint64Ptr(5100), ID: uint64Ptr(3)}, e.Event())
+}
+
+func TestPeriodEventForEmsg(t *testing.T) {
+	m := decodeFixture(t, "fixture_event_stream.mpd")
+	p := &m.Period[0]
+	as := p.AdaptationSets[0]
+	r := &as.Representations[0]
+	// Representation media timeline starts at 10s
+	r.SegmentTemplate.PresentationTimeOffset = uint64Ptr(10000)
+
+	e := &Emsg{
+		SchemeIDURI:      "urn:example:callback",
+		Value:            "1",
+		Timescale:        90000,
+		PresentationTime: 90000 * 15,
+		EventDuration:    90000,
+		ID:               3,
+		MessageData:      []byte("hello"),
+	}
+	stream, ev, ok := p.EventForEmsg(as, r, e)
+	require.True(t, ok)
+	require.Same(t, &p.EventStreams[1], stream)
+	// 5s from the Period start in timescale 1000 and presentationTimeOffset 100
+	require.Equal(t, p.EventStreams[1].Events[1], ev)
+
+	e.PresentationTime = 90000 * 5
+	_, _, ok = p.EventForEmsg(as, r, e)
+	require.False(t, ok)
+
+	e.SchemeIDURI = "urn:example:unknown"
+	_, _, ok = p.EventForEmsg(as, r, e)
+	require.False(t, ok)
+
+	m = decodeFixture(t, "fixture_inband_event_stream.mpd")
+	p = &m.Period[0]
+	as = p.AdaptationSets[0]
+	e = &Emsg{
+		SchemeIDURI:      "urn:example:callback",
+		Value:            "beacon",
+		Timescale:        1000,
+		PresentationTime: 4000,
+		EventDuration:    UnknownEmsgDuration,
+		ID:               7,
+	}
+	stream, ev, ok = p.EventForEmsg(as, &as.Representations[0], e)
+	require.True(t, ok)
+	require.Equal(t, &EventStream{
+		SchemeIDURI: stringPtr("urn:example:callback"),
+		Value:       stringPtr("beacon"),
+		Timescale:   uint64Ptr(1000),
+	}, stream)
+	require.Equal(t, Event{PresentationTime: uint64Ptr(4000), ID: uint64Ptr(7)}, ev)
+
+	// InbandEventStream of another Representation doesn't match
+	_, _, ok = p.EventForEmsg(as, nil, e)
+	require.False(t, ok)
+}
@@ -0,0 +1,189 @@
+package mpd
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+// DASHNamespace is the namespace of MPD elements.
+const DASHNamespace = "urn:mpeg:dash:schema:mpd:2011"
+
+// xmlNamespace is bound to xml prefix by definition, it's never declared.
+const xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+
+// wellKnownPrefixes maps commonly used namespaces to prefixes used when Encode has to declare them.
+var wellKnownPrefixes = map[string]string{
+	"http://www.w3.org/2001/XMLSchema-instance": "xsi",
+	"http://www.scte.org/schemas/35/2016":       "scte35",
+	"urn:dvb:dash:dash-extensions:2014-1":       "dvb",
+	CENCNamespace:                               "cenc",
+	"urn:microsoft:playready":                   "mspr",
+	"urn:dashif:org:cpix":                       "cpix",
+	XLinkNamespace:                              "xlink",
+}
+
+// Namespaces returns namespace bindings declared on MPD element: prefix to namespace URI.
+func (m *MPD) Namespaces() map[string]string {
+	res := map[string]string{}
+	for uri, prefix := range m.declaredNamespaces() {
+		res[prefix] = uri
+	}
+	return res
+}
+
+// DeclareNamespace binds prefix to namespace uri on MPD element, replacing previous binding of prefix.
+// Encode writes elements and attributes of uri namespace kept in Extensions and ExtensionAttrs with the prefix.
+func (m *MPD) DeclareNamespace(prefix, uri string) {
+	switch prefix {
+	case "xsi":
+		m.XSI = &uri
+		return
+	case "scte35":
+		m.SCTE35 = &uri
+		return
+	case "dvb":
+		m.DVB = &uri
+		return
+	case "xlink":
+		m.XLink = &uri
+		return
+	}
+	for i, a := range m.ExtensionAttrs {
+		if a.Name.Space == "xmlns" && a.Name.Local == prefix {
+			m.ExtensionAttrs[i].Value = uri
+			return
+		}
+	}
+	m.ExtensionAttrs = append(m.ExtensionAttrs, xml.Attr{Name: xml.Name{Space: "xmlns", Local: prefix}, Value: uri})
+}
+
+// declaredNamespaces returns namespaces declared on MPD element, the first binding of URI wins.
+func (m *MPD) declaredNamespaces() namespaces {
+	res := namespaces{}
+	for _, d := range []struct {
+		prefix string
+		uri    *string
+	}{{"xsi", m.XSI}, {"scte35", m.SCTE35}, {"dvb", m.DVB}, {"xlink", m.XLink}} {
+		if d.uri != nil {
+			res.add(*d.uri, d.prefix)
+		}
+	}
+	for _, a := range m.ExtensionAttrs {
+		if a.Name.Space == "xmlns" {
+			res.add(a.Value, a.Name.Local)
+		}
+	}
+	return res
+}
+
+// namespaces maps namespace URIs to prefixes declared for them.
+type namespaces map[string]string
+
+func (ns namespaces) add(uri, prefix string) {
+	if _, ok := ns[uri]; !ok {
+		ns[uri] = prefix
+	}
+}
+
+// with returns copy of ns extended with namespaces declared by attrs, which take precedence.
+func (ns namespaces) with(attrs []xml.Attr) namespaces {
+	res := make(namespaces, len(ns))
+	for uri, prefix := range ns {
+		res[uri] = prefix
+	}
+	for _, a := range attrs {
+		if a.Name.Space == "xmlns" {
+			res[a.Value] = a.Name.Local
+		}
+	}
+	return res
+}
+
+// Extension is a child element not described by the model (e.g. vendor-specific one).
+// It's kept as is, so Decode/Encode round trip doesn't lose it.
+type Extension struct {
+	XMLName xml.Name   `json:"name,omitempty"`
+	Attrs   []xml.Attr `xml:",any,attr" json:"attrs,omitempty"`
+	// Content is the raw content of the element.
+	Content string `xml:",innerxml" json:"content,omitempty"`
+}
+
+type extensionMarshal struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Content string     `xml:",innerxml"`
+}
+
+// modifyAttrs restores prefixed names of attributes: Decode resolves prefixes to namespaces,
+// while Marshal would declare a generated prefix for every attribute. ns are namespaces declared by
+// ancestors of the element.
+func modifyAttrs(attrs []xml.Attr, ns namespaces) []xml.Attr {
+	return marshalAttrs(attrs, ns.with(attrs))
+}
+
+// marshalAttrs converts attributes names to prefixed ones using declared namespaces, undeclared ones
+// are declared on the element and added to declared.
+func marshalAttrs(attrs []xml.Attr, declared namespaces) []xml.Attr {
+	if len(attrs) == 0 {
+		return nil
+	}
+	res := make([]xml.Attr, 0, len(attrs))
+	var generated int
+	for _, a := range attrs {
+		name := a.Name.Local
+		switch space := a.Name.Space; space {
+		case "":
+		case "xmlns":
+			name = "xmlns:" + name
+		case xmlNamespace:
+			name = "xml:" + name
+		default:
+			p, ok := declared[space]
+			if !ok {
+				if p, ok = wellKnownPrefixes[space]; !ok {
+					generated++
+					p = "ns" + strconv.Itoa(generated)
+				}
+				declared[space] = p
+				res = append(res, xml.Attr{Name: xml.Name{Local: "xmlns:" + p}, Value: space})
+			}
+			name = p + ":" + name
+		}
+		res = append(res, xml.Attr{Name: xml.Name{Local: name}, Value: a.Value})
+	}
+	return res
+}
+
+// modifyExtensions restores prefixed names of extensions like modifyAttrs does,
+// element namespace which has no prefix is declared as the default one on the element.
+func modifyExtensions(es []Extension, ns namespaces) []extensionMarshal {
+	if es == nil {
+		return nil
+	}
+	res := make([]extensionMarshal, 0, len(es))
+	for _, e := range es {
+		declared := ns.with(e.Attrs)
+		var defaultNS string
+		hasDefault := false
+		for _, a := range e.Attrs {
+			if a.Name.Space == "" && a.Name.Local == "xmlns" {
+				defaultNS, hasDefault = a.Value, true
+			}
+		}
+
+		em := extensionMarshal{XMLName: xml.Name{Local: e.XMLName.Local}, Content: e.Content}
+		var attrs []xml.Attr
+		switch space := e.XMLName.Space; {
+		case hasDefault && space == defaultNS, !hasDefault && (space == "" || space == DASHNamespace):
+		default:
+			if p, ok := declared[space]; ok {
+				em.XMLName.Local = p + ":" + e.XMLName.Local
+			} else if !hasDefault {
+				attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: space})
+			}
+		}
+		em.Attrs = append(attrs, marshalAttrs(e.Attrs, declared)...)
+		res = append(res, em)
+	}
+	return res
+}
@@ -0,0 +1,76 @@
+package mpd
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeExtensionNamespaces(t *testing.T) {
+	m := new(MPD)
+	require.NoError(t, m.Decode([]byte(`<MPD xmlns="urn:mpeg:dash:schema:mpd:2011" xmlns:foo="urn:foo">`+
+		`<foo:Bar foo:a="1" b="2"><foo:Baz/></foo:Bar></MPD>`)))
+	require.Equal(t, []Extension{{
+		XMLName: xml.Name{Space: "urn:foo", Local: "Bar"},
+		Attrs: []xml.Attr{
+			{Name: xml.Name{Space: "urn:foo", Local: "a"}, Value: "1"},
+			{Name: xml.Name{Local: "b"}, Value: "2"},
+		},
+		Content: "<foo:Baz/>",
+	}}, m.Extensions)
+
+	b, err := m.Encode()
+	require.NoError(t, err)
+	require.Contains(t, string(b), `<foo:Bar foo:a="1" b="2"><foo:Baz/></foo:Bar>`)
+
+	// undeclared namespaces are declared on the element
+	m.Extensions = append(m.Extensions, Extension{
+		XMLName: xml.Name{Space: "urn:bar", Local: "Bar"},
+		Attrs: []xml.Attr{
+			{Name: xml.Name{Space: "urn:mpeg:cenc:2013", Local: "a"}, Value: "1"},
+			{Name: xml.Name{Space: "urn:baz", Local: "b"}, Value: "2"},
+		},
+	})
+	b, err = m.Encode()
+	require.NoError(t, err)
+	require.Contains(t, string(b), `<Bar xmlns="urn:bar" xmlns:cenc="urn:mpeg:cenc:2013" cenc:a="1" xmlns:ns1="urn:baz" ns1:b="2"/>`)
+
+	decoded := new(MPD)
+	require.NoError(t, decoded.Decode(b))
+	require.Equal(t, m.Extensions[0], decoded.Extensions[0])
+	require.Equal(t, xml.Name{Space: "urn:bar", Local: "Bar"}, decoded.Extensions[1].XMLName)
+}
+
+func TestEncodeExtensionAttrs(t *testing.T) {
+	m := new(MPD)
+	require.NoError(t, m.Decode([]byte(`<MPD xmlns="urn:mpeg:dash:schema:mpd:2011" xmlns:foo="urn:foo" foo:a="1">`+
+		`<Period id="0" foo:b="2" c="3"/></MPD>`)))
+	require.Equal(t, []xml.Attr{
+		{Name: xml.Name{Space: "xmlns", Local: "foo"}, Value: "urn:foo"},
+		{Name: xml.Name{Space: "urn:foo", Local: "a"}, Value: "1"},
+	}, m.ExtensionAttrs)
+
+	b, err := m.Encode()
+	require.NoError(t, err)
+	require.Contains(t, string(b), `xmlns:foo="urn:foo" foo:a="1">`)
+	require.Contains(t, string(b), `<Period id="0" foo:b="2" c="3"/>`)
+}
+
+func TestDeclareNamespace(t *testing.T) {
+	m := &MPD{Period: []Period{{
+		Extensions: []Extension{{XMLName: xml.Name{Space: "https://dashif.org/", Local: "Thumbnail"}}},
+	}}}
+	m.DeclareNamespace("dashif", "urn:dashif")
+	m.DeclareNamespace("dashif", "https://dashif.org/")
+	m.DeclareNamespace("scte35", "http://www.scte.org/schemas/35/2016")
+	require.Equal(t, map[string]string{
+		"dashif": "https://dashif.org/",
+		"scte35": "http://www.scte.org/schemas/35/2016",
+	}, m.Namespaces())
+
+	b, err := m.Encode()
+	require.NoError(t, err)
+	require.Contains(t, string(b), `xmlns:scte35="http://www.scte.org/schemas/35/2016" xmlns:dashif="https://dashif.org/">`)
+	require.Contains(t, string(b), `<dashif:Thumbnail/>`)
+}
@@ -0,0 +1,39 @@
+package mpd
+
+import (
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratedRoundTrip(t *testing.T) {
+	f := func(m *MPD) bool {
+		b, err := m.Encode()
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+		decoded := new(MPD)
+		if err = decoded.Decode(b); err != nil {
+			t.Log(err)
+			return false
+		}
+		// compare models directly to catch fields lost by modifyMPD
+		decoded.XMLName = m.XMLName
+		if !reflect.DeepEqual(m, decoded) {
+			t.Logf("%s", b)
+			return false
+		}
+		return true
+	}
+	require.NoError(t, quick.Check(f, &quick.Config{MaxCount: 300}))
+}
+
+func TestGenerators(t *testing.T) {
+	f := func(p *Period, as *AdaptationSet, r *Representation, c *ConditionalUint) bool {
+		return p != nil && as != nil && r != nil && c != nil
+	}
+	require.NoError(t, quick.Check(f, nil))
+}
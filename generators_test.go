@@ -0,0 +1,320 @@
+package mpd
+
+import (
+	"encoding/xml"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing/quick"
+	"time"
+)
+
+// Generators of random model values for property-based tests (testing/quick), defined in a test file
+// so that importers of the package don't link testing.
+// Generated values are not semantically valid manifests, but they survive Encode/Decode round trip.
+
+// maxGeneratedSliceLen limits length of generated slices, so nested slices don't explode.
+const maxGeneratedSliceLen = 3
+
+const generatedStringChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:"
+
+var (
+	xmlNameType         = reflect.TypeOf(xml.Name{})
+	quickGeneratorType  = reflect.TypeOf((*quick.Generator)(nil)).Elem()
+	generatedStructType = map[reflect.Type]bool{
+		reflect.TypeOf(MPD{}):            true,
+		reflect.TypeOf(Period{}):         true,
+		reflect.TypeOf(AdaptationSet{}):  true,
+		reflect.TypeOf(Representation{}): true,
+	}
+)
+
+// randomize fills v (must be settable) with random data.
+func randomize(v reflect.Value, r *rand.Rand, size int) {
+	t := v.Type()
+	// types with own generators, except ones which use randomize themselves
+	if !generatedStructType[t] && t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(quickGeneratorType) {
+		g := reflect.New(t).Interface().(quick.Generator)
+		v.Set(g.Generate(r, size).Elem())
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" || f.Type == xmlNameType {
+				continue
+			}
+			randomize(v.Field(i), r, size)
+		}
+	case reflect.Ptr:
+		if r.Intn(2) == 0 {
+			v.Set(reflect.Zero(t))
+			return
+		}
+		p := reflect.New(t.Elem())
+		randomize(p.Elem(), r, size)
+		v.Set(p)
+	case reflect.Slice:
+		// empty slices are decoded as nil
+		if size == 0 || r.Intn(2) == 0 {
+			v.Set(reflect.Zero(t))
+			return
+		}
+		n := 1 + r.Intn(maxGeneratedSliceLen)
+		s := reflect.MakeSlice(t, n, n)
+		for i := 0; i < n; i++ {
+			// nil elements are never decoded
+			if t.Elem().Kind() == reflect.Ptr {
+				s.Index(i).Set(reflect.New(t.Elem().Elem()))
+				randomize(s.Index(i).Elem(), r, size)
+				continue
+			}
+			randomize(s.Index(i), r, size)
+		}
+		v.Set(s)
+	case reflect.String:
+		b := make([]byte, r.Intn(9))
+		for i := range b {
+			b[i] = generatedStringChars[r.Intn(len(generatedStringChars))]
+		}
+		v.SetString(string(b))
+	case reflect.Bool:
+		v.SetBool(r.Intn(2) == 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(r.Int63() - r.Int63())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(r.Uint64())
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(r.NormFloat64())
+	default:
+		panic("randomize: unsupported type " + t.String())
+	}
+}
+
+// fixGenerated adjusts random MPD for known Decode features.
+func fixGenerated(m *MPD) {
+	empty := ""
+	fixDRM := func(ds []DRMDescriptor) {
+		for i := range ds {
+			d := &ds[i]
+			// chardata of present element is always decoded as a non-nil value
+			if d.Pssh != nil && d.Pssh.Value == nil {
+				d.Pssh.Value = &empty
+			}
+			if d.Pro != nil && d.Pro.Value == nil {
+				d.Pro.Value = &empty
+			}
+			if d.Laurl != nil && d.Laurl.Value == nil {
+				d.Laurl.Value = &empty
+			}
+			if d.LegacyLaurl != nil && d.LegacyLaurl.Value == nil {
+				d.LegacyLaurl.Value = &empty
+			}
+			for _, v := range []*DashIFValue{d.AuthzURL, d.AuthzScheme} {
+				if v != nil && v.Value == nil {
+					v.Value = &empty
+				}
+			}
+			// Encode declares namespaces of prefixed elements
+			if d.Pro != nil && d.MSPR == nil && d.Pro.MSPR == nil {
+				ns := MSPRNamespace
+				d.Pro.MSPR = &ns
+			}
+			if d.Laurl != nil && d.Laurl.DashIF == nil {
+				ns := DashIFCPSNamespace
+				d.Laurl.DashIF = &ns
+			}
+			if d.LegacyLaurl != nil && d.LegacyLaurl.ClearKey == nil {
+				ns := ClearKeyNamespace
+				d.LegacyLaurl.ClearKey = &ns
+			}
+			for _, v := range []*DashIFValue{d.AuthzURL, d.AuthzScheme} {
+				if v != nil && v.DashIF == nil {
+					ns := DashIFCPSNamespace
+					v.DashIF = &ns
+				}
+			}
+			if d.MarlinContentIDs != nil && d.MAS == nil {
+				ns := MASNamespace
+				d.MAS = &ns
+			}
+		}
+	}
+	for _, p := range m.Period {
+		for _, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			fixDRM(as.ContentProtections)
+			for _, r := range as.Representations {
+				fixDRM(r.ContentProtections)
+			}
+		}
+	}
+	// extensions are decoded in the default namespace, Encode declares it on the element if it's not DASH one
+	fixExtensions := func(es []Extension) {
+		for i := range es {
+			e := &es[i]
+			e.XMLName.Space = stringValue(m.XMLNS)
+			if e.XMLName.Space != "" && e.XMLName.Space != DASHNamespace {
+				e.Attrs = append([]xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: e.XMLName.Space}}, e.Attrs...)
+			}
+		}
+	}
+	// attributes without name are not encoded
+	fixAttrs := func(attrs []xml.Attr) {
+		for i := range attrs {
+			attrs[i].Name = xml.Name{Local: "x" + strconv.Itoa(i)}
+		}
+	}
+	fixExtensions(m.Extensions)
+	fixAttrs(m.ExtensionAttrs)
+	for _, p := range m.Period {
+		fixExtensions(p.Extensions)
+		fixAttrs(p.ExtensionAttrs)
+		for _, es := range p.EventStreams {
+			for i := range es.Events {
+				e := &es.Events[i]
+				// Payload is not decoded along with SCTE-35 elements
+				if e.Signal != nil || e.SpliceInfoSection != nil {
+					e.Payload = ""
+				}
+				if e.Signal != nil && e.Signal.SpliceInfoSection != nil {
+					fixExtensions(e.Signal.SpliceInfoSection.Extensions)
+				}
+				if e.SpliceInfoSection != nil {
+					fixExtensions(e.SpliceInfoSection.Extensions)
+				}
+			}
+		}
+		for _, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			fixExtensions(as.Extensions)
+			fixAttrs(as.ExtensionAttrs)
+			for _, r := range as.Representations {
+				fixExtensions(r.Extensions)
+				fixAttrs(r.ExtensionAttrs)
+			}
+		}
+	}
+	// Encode declares xlink and scte35 namespaces if they are used
+	if m.XLink == nil && m.usesXLink() {
+		ns := XLinkNamespace
+		m.XLink = &ns
+	}
+	if m.SCTE35 == nil && m.usesSCTE35() {
+		ns := SCTE35Namespace
+		m.SCTE35 = &ns
+	}
+}
+
+// Generate implements quick.Generator.
+func (*MPD) Generate(r *rand.Rand, size int) reflect.Value {
+	m := new(MPD)
+	randomize(reflect.ValueOf(m).Elem(), r, size)
+	fixGenerated(m)
+	return reflect.ValueOf(m)
+}
+
+// Generate implements quick.Generator.
+func (*Period) Generate(r *rand.Rand, size int) reflect.Value {
+	m := &MPD{Period: make([]Period, 1)}
+	randomize(reflect.ValueOf(&m.Period[0]).Elem(), r, size)
+	fixGenerated(m)
+	return reflect.ValueOf(&m.Period[0])
+}
+
+// Generate implements quick.Generator.
+func (*AdaptationSet) Generate(r *rand.Rand, size int) reflect.Value {
+	as := new(AdaptationSet)
+	randomize(reflect.ValueOf(as).Elem(), r, size)
+	fixGenerated(&MPD{Period: []Period{{AdaptationSets: []*AdaptationSet{as}}}})
+	return reflect.ValueOf(as)
+}
+
+// Generate implements quick.Generator.
+func (*Representation) Generate(r *rand.Rand, size int) reflect.Value {
+	as := &AdaptationSet{Representations: make([]Representation, 1)}
+	randomize(reflect.ValueOf(&as.Representations[0]).Elem(), r, size)
+	fixGenerated(&MPD{Period: []Period{{AdaptationSets: []*AdaptationSet{as}}}})
+	return reflect.ValueOf(&as.Representations[0])
+}
+
+// Generate implements quick.Generator.
+func (*ConditionalUint) Generate(r *rand.Rand, size int) reflect.Value {
+	c := new(ConditionalUint)
+	switch r.Intn(3) {
+	case 0:
+		u := r.Uint64()
+		c.u = &u
+	case 1:
+		b := r.Intn(2) == 0
+		c.b = &b
+	}
+	return reflect.ValueOf(c)
+}
+
+// Generate implements quick.Generator.
+func (*AvailabilityTimeOffset) Generate(r *rand.Rand, size int) reflect.Value {
+	a := AvailabilityTimeOffset(float64(r.Intn(100000)) / 1000)
+	if r.Intn(4) == 0 {
+		a = InfiniteAvailabilityTimeOffset
+	}
+	return reflect.ValueOf(&a)
+}
+
+// Generate implements quick.Generator.
+func (*DateTime) Generate(r *rand.Rand, size int) reflect.Value {
+	// decoded value keeps its lexical form
+	t := time.Unix(r.Int63n(1<<32), r.Int63n(1000)*int64(time.Millisecond)).UTC()
+	return reflect.ValueOf(&DateTime{Time: t, raw: t.Format(time.RFC3339Nano)})
+}
+
+// Generate implements quick.Generator.
+func (*PresentationType) Generate(r *rand.Rand, size int) reflect.Value {
+	t := PresentationTypeStatic
+	if r.Intn(2) == 0 {
+		t = PresentationTypeDynamic
+	}
+	return reflect.ValueOf(&t)
+}
+
+// Generate implements quick.Generator.
+func (*StringVector) Generate(r *rand.Rand, size int) reflect.Value {
+	// empty vectors and empty strings are lost on Encode
+	var v StringVector
+	for i := r.Intn(maxGeneratedSliceLen + 1); i > 0; i-- {
+		v = append(v, "s"+strconv.Itoa(r.Intn(1000)))
+	}
+	return reflect.ValueOf(&v)
+}
+
+// Generate implements quick.Generator.
+func (*Extension) Generate(r *rand.Rand, size int) reflect.Value {
+	// namespace is set by fixGenerated
+	e := &Extension{XMLName: xml.Name{Local: "x" + strconv.Itoa(r.Intn(1000))}}
+	if r.Intn(2) == 0 {
+		e.Attrs = []xml.Attr{{Name: xml.Name{Local: "a"}, Value: strconv.Itoa(r.Intn(1000))}}
+	}
+	if r.Intn(2) == 0 {
+		e.Content = "c" + strconv.Itoa(r.Intn(1000))
+	}
+	return reflect.ValueOf(e)
+}
+
+// check interfaces
+var (
+	_ quick.Generator = new(MPD)
+	_ quick.Generator = new(Period)
+	_ quick.Generator = new(AdaptationSet)
+	_ quick.Generator = new(Representation)
+	_ quick.Generator = new(ConditionalUint)
+	_ quick.Generator = new(AvailabilityTimeOffset)
+	_ quick.Generator = new(PresentationType)
+	_ quick.Generator = new(StringVector)
+	_ quick.Generator = new(Extension)
+)
@@ -0,0 +1,118 @@
+package mpd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContentType is MIME type of MPD.
+const ContentType = "application/dash+xml"
+
+// ResponseOptions configures WriteResponse.
+type ResponseOptions struct {
+	// Gzip compresses response if client accepts gzip encoding.
+	Gzip bool
+	// StaticMaxAge is Cache-Control max-age for static MPD, no Cache-Control header is sent if it's zero.
+	StaticMaxAge time.Duration
+	// EncodeOptions configure MPD XML output.
+	EncodeOptions []EncodeOption
+}
+
+// cacheControl returns Cache-Control header value, empty if header should not be sent.
+// Dynamic MPD may be cached until the next update.
+func (m *MPD) cacheControl(opts ResponseOptions) (string, error) {
+	if !m.IsDynamic() {
+		if opts.StaticMaxAge <= 0 {
+			return "", nil
+		}
+		return "max-age=" + strconv.FormatInt(int64(opts.StaticMaxAge/time.Second), 10), nil
+	}
+
+	if m.MinimumUpdatePeriod == nil {
+		return "no-cache", nil
+	}
+	mup, err := parseDuration(*m.MinimumUpdatePeriod)
+	if err != nil {
+		return "", err
+	}
+	if mup < time.Second {
+		return "no-cache", nil
+	}
+	return "max-age=" + strconv.FormatInt(int64(mup/time.Second), 10), nil
+}
+
+// WriteResponse encodes MPD and writes it to w with Content-Type, Content-Length and Cache-Control headers.
+// r is used to check Accept-Encoding and method (body is not sent for HEAD requests), it can be nil.
+// Nothing is written if error is returned.
+func (m *MPD) WriteResponse(w http.ResponseWriter, r *http.Request, opts ResponseOptions) error {
+	b, err := m.Encode(opts.EncodeOptions...)
+	if err != nil {
+		return err
+	}
+	cc, err := m.cacheControl(opts)
+	if err != nil {
+		return err
+	}
+
+	h := w.Header()
+	if opts.Gzip {
+		h.Add("Vary", "Accept-Encoding")
+		if r != nil && acceptsGzip(r) {
+			buf := new(bytes.Buffer)
+			gz := gzip.NewWriter(buf)
+			if _, err = gz.Write(b); err != nil {
+				return err
+			}
+			if err = gz.Close(); err != nil {
+				return err
+			}
+			b = buf.Bytes()
+			h.Set("Content-Encoding", "gzip")
+		}
+	}
+	h.Set("Content-Type", ContentType)
+	h.Set("Content-Length", strconv.Itoa(len(b)))
+	if cc != "" {
+		h.Set("Cache-Control", cc)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if r != nil && r.Method == http.MethodHead {
+		return nil
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// ServeHTTP implements http.Handler, it serves MPD with gzip compression enabled.
+func (m *MPD) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := m.WriteResponse(w, r, ResponseOptions{Gzip: true}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// acceptsGzip checks whether Accept-Encoding request header allows gzip.
+func acceptsGzip(r *http.Request) bool {
+	for _, header := range r.Header.Values("Accept-Encoding") {
+		for _, enc := range strings.Split(header, ",") {
+			params := strings.Split(enc, ";")
+			if strings.TrimSpace(params[0]) != "gzip" {
+				continue
+			}
+			if len(params) > 1 && strings.ReplaceAll(params[1], " ", "") == "q=0" {
+				return false
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// check interfaces
+var (
+	_ http.Handler = new(MPD)
+)
@@ -0,0 +1,73 @@
+package mpd
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteResponse(t *testing.T) {
+	m := decodeFixture(t, "fixture_flussonic_live.mpd")
+	expected, err := m.Encode()
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	require.NoError(t, m.WriteResponse(w, nil, ResponseOptions{}))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, ContentType, w.Header().Get("Content-Type"))
+	require.Equal(t, strconv.Itoa(len(expected)), w.Header().Get("Content-Length"))
+	require.Equal(t, "max-age=5", w.Header().Get("Cache-Control"))
+	require.Equal(t, expected, w.Body.Bytes())
+
+	m.Type = PresentationTypeOf(PresentationTypeStatic)
+	w = httptest.NewRecorder()
+	require.NoError(t, m.WriteResponse(w, nil, ResponseOptions{}))
+	require.Empty(t, w.Header().Get("Cache-Control"))
+
+	w = httptest.NewRecorder()
+	require.NoError(t, m.WriteResponse(w, nil, ResponseOptions{StaticMaxAge: time.Hour}))
+	require.Equal(t, "max-age=3600", w.Header().Get("Cache-Control"))
+
+	expected, err = m.Encode(WithCompact())
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	require.NoError(t, m.WriteResponse(w, nil, ResponseOptions{EncodeOptions: []EncodeOption{WithCompact()}}))
+	require.Equal(t, expected, w.Body.Bytes())
+}
+
+func TestServeHTTP(t *testing.T) {
+	m := decodeFixture(t, "fixture_flussonic_live.mpd")
+	expected, err := m.Encode()
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodGet, "/manifest.mpd", nil)
+	r.Header.Set("Accept-Encoding", "deflate, gzip;q=1.0")
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, r)
+	require.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	require.Equal(t, strconv.Itoa(w.Body.Len()), w.Header().Get("Content-Length"))
+	gz, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	b, err := ioutil.ReadAll(gz)
+	require.NoError(t, err)
+	require.Equal(t, expected, b)
+
+	r = httptest.NewRequest(http.MethodHead, "/manifest.mpd", nil)
+	r.Header.Set("Accept-Encoding", "gzip;q=0")
+	w = httptest.NewRecorder()
+	m.ServeHTTP(w, r)
+	require.Empty(t, w.Header().Get("Content-Encoding"))
+	require.Equal(t, strconv.Itoa(len(expected)), w.Header().Get("Content-Length"))
+	require.Zero(t, w.Body.Len())
+
+	m.MinimumUpdatePeriod = stringPtr("bad")
+	w = httptest.NewRecorder()
+	m.ServeHTTP(w, r)
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,78 @@
+package mpd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	fixtures, err := filepath.Glob("fixture_*.mpd")
+	require.NoError(t, err)
+	require.NotEmpty(t, fixtures)
+	for _, name := range fixtures {
+		m := decodeFixture(t, name)
+		b, err := json.Marshal(m)
+		require.NoError(t, err, name)
+		var top map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(b, &top), name)
+		require.NotContains(t, top, "name", name)
+
+		res := new(MPD)
+		require.NoError(t, json.Unmarshal(b, res), name)
+		expected, err := m.Encode()
+		require.NoError(t, err)
+		obtained, err := res.Encode()
+		require.NoError(t, err)
+		require.Equal(t, string(expected), string(obtained), name)
+	}
+}
+
+func TestJSONSpecialTypes(t *testing.T) {
+	ato := InfiniteAvailabilityTimeOffset
+	st := &SegmentTemplate{Timescale: uint64Ptr(90000), AvailabilityTimeOffset: &ato}
+	b, err := json.Marshal(st)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"timescale": 90000, "availabilityTimeOffset": "INF"}`, string(b))
+	res := new(SegmentTemplate)
+	require.NoError(t, json.Unmarshal(b, res))
+	require.True(t, res.AvailabilityTimeOffset.IsInf())
+	require.NoError(t, json.Unmarshal([]byte(`{"availabilityTimeOffset": 1.5}`), res))
+	require.Equal(t, AvailabilityTimeOffset(1.5), *res.AvailabilityTimeOffset)
+	require.Error(t, json.Unmarshal([]byte(`{"availabilityTimeOffset": "1.5"}`), res))
+
+	as := &AdaptationSet{SegmentAlignment: CondBool(true), SubsegmentAlignment: CondUint(2)}
+	b, err = json.Marshal(as)
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"segmentAlignment":true,"subsegmentAlignment":2`)
+	resAS := new(AdaptationSet)
+	require.NoError(t, json.Unmarshal(b, resAS))
+	require.Equal(t, as.SegmentAlignment, resAS.SegmentAlignment)
+	require.Equal(t, as.SubsegmentAlignment, resAS.SubsegmentAlignment)
+	require.NoError(t, json.Unmarshal([]byte(`{"segmentAlignment": null}`), resAS))
+	require.Error(t, json.Unmarshal([]byte(`{"segmentAlignment": "yes"}`), resAS))
+
+	b, err = json.Marshal(Duration(90*time.Second + 500*time.Millisecond))
+	require.NoError(t, err)
+	require.Equal(t, `"PT1M30.5S"`, string(b))
+	var d Duration
+	require.NoError(t, json.Unmarshal(b, &d))
+	require.Equal(t, Duration(90*time.Second+500*time.Millisecond), d)
+	require.Error(t, json.Unmarshal([]byte(`"90s"`), &d))
+
+	dt, err := ParseDateTime("2021-09-17T04:42:54+03:00")
+	require.NoError(t, err)
+	b, err = json.Marshal(dt)
+	require.NoError(t, err)
+	require.Equal(t, `"2021-09-17T04:42:54+03:00"`, string(b))
+	var resDT DateTime
+	require.NoError(t, json.Unmarshal(b, &resDT))
+	require.True(t, dt.Equal(resDT.Time))
+	b, err = json.Marshal(NewDateTime(dt.Time))
+	require.NoError(t, err)
+	require.Equal(t, `"2021-09-17T01:42:54Z"`, string(b))
+	require.Error(t, json.Unmarshal([]byte(`"yesterday"`), &resDT))
+}
@@ -0,0 +1,142 @@
+package mpd
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// NormalizeLang validates lang as BCP-47 language tag and returns its canonical form,
+// e.g. "en-us" becomes "en-US" and "eng" becomes "en".
+func NormalizeLang(lang string) (string, error) {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return "", fmt.Errorf("invalid lang %q: %w", lang, err)
+	}
+	return tag.String(), nil
+}
+
+// NormalizeLangs replaces every lang attribute of MPD with its canonical form.
+// It returns an error on the first invalid language tag, leaving the rest of attributes untouched.
+func (m *MPD) NormalizeLangs() error {
+	return m.forEachLang(func(path string, lang *string) error {
+		res, err := NormalizeLang(*lang)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		*lang = res
+		return nil
+	})
+}
+
+// forEachLang calls fn for every lang attribute of MPD with its path.
+func (m *MPD) forEachLang(fn func(path string, lang *string) error) error {
+	visit := func(path string, lang *string) error {
+		if lang == nil {
+			return nil
+		}
+		return fn(path+"@lang", lang)
+	}
+	for i := range m.ProgramInformation {
+		if err := visit(fmt.Sprintf("MPD/ProgramInformation[%d]", i), m.ProgramInformation[i].Lang); err != nil {
+			return err
+		}
+	}
+	for pi, p := range m.Period {
+		for i := range p.Preselections {
+			if err := visit(fmt.Sprintf("%s/Preselection[%d]", periodPath(pi), i), p.Preselections[i].Lang); err != nil {
+				return err
+			}
+		}
+		for ai, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			path := adaptationSetPath(pi, ai)
+			if err := visit(path, as.Lang); err != nil {
+				return err
+			}
+			for ci := range as.ContentComponents {
+				if err := visit(fmt.Sprintf("%s/ContentComponent[%d]", path, ci), as.ContentComponents[ci].Lang); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateLangs reports lang attributes which are not valid BCP-47 language tags.
+func validateLangs(m *MPD) []Finding {
+	var res []Finding
+	_ = m.forEachLang(func(path string, lang *string) error {
+		if _, err := language.Parse(*lang); err != nil {
+			res = append(res, Finding{Severity: SeverityError, Path: path, Message: fmt.Sprintf("invalid lang %q", *lang)})
+		}
+		return nil
+	})
+	return res
+}
+
+// AdaptationSetsForLanguages returns AdaptationSets of Period with the language which best matches
+// preferred languages given in priority order. Matching falls back from the exact tag to close ones,
+// e.g. "en-GB" matches "en" and "pt-BR" matches "pt-PT", a more preferred language wins over a better
+// match of a less preferred one. AdaptationSets without lang or with invalid one are never matched.
+// It returns nil if none of the languages matches.
+func (p *Period) AdaptationSetsForLanguages(preferred ...string) []*AdaptationSet {
+	var sets []*AdaptationSet
+	var langs []string
+	for _, as := range p.AdaptationSets {
+		if as != nil && as.Lang != nil {
+			sets = append(sets, as)
+			langs = append(langs, *as.Lang)
+		}
+	}
+
+	var res []*AdaptationSet
+	for _, i := range matchLanguages(langs, preferred) {
+		res = append(res, sets[i])
+	}
+	return res
+}
+
+// matchLanguages returns indexes of langs with the language which best matches preferred languages given
+// in priority order, as described in AdaptationSetsForLanguages. Invalid langs are never matched.
+// It returns nil if none of the languages matches.
+func matchLanguages(langs, preferred []string) []int {
+	var supported []language.Tag
+	var groups [][]int
+	index := map[language.Tag]int{}
+	for li, lang := range langs {
+		tag, err := language.Parse(lang)
+		if err != nil {
+			continue
+		}
+		i, ok := index[tag]
+		if !ok {
+			i = len(supported)
+			index[tag] = i
+			supported = append(supported, tag)
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], li)
+	}
+	if len(supported) == 0 {
+		return nil
+	}
+
+	var prefs []language.Tag
+	for _, lang := range preferred {
+		if tag, err := language.Parse(lang); err == nil {
+			prefs = append(prefs, tag)
+		}
+	}
+	if len(prefs) == 0 {
+		return nil
+	}
+	_, i, confidence := language.NewMatcher(supported).Match(prefs...)
+	if confidence == language.No {
+		return nil
+	}
+	return groups[i]
+}
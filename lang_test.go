@@ -0,0 +1,49 @@
+package mpd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeLang(t *testing.T) {
+	for lang, expected := range map[string]string{"en": "en", "en-us": "en-US", "eng": "en", "zh-hans-cn": "zh-Hans-CN", "und": "und"} {
+		res, err := NormalizeLang(lang)
+		require.NoError(t, err)
+		require.Equal(t, expected, res, lang)
+	}
+	_, err := NormalizeLang("e")
+	require.EqualError(t, err, `invalid lang "e": language: tag is not well-formed`)
+}
+
+func TestNormalizeLangs(t *testing.T) {
+	m := &MPD{Period: []Period{{AdaptationSets: []*AdaptationSet{
+		{Lang: stringPtr("en-gb"), ContentComponents: []ContentComponent{{Lang: stringPtr("FRE")}}},
+		{},
+	}}}}
+	require.NoError(t, m.NormalizeLangs())
+	require.Equal(t, "en-GB", *m.Period[0].AdaptationSets[0].Lang)
+	require.Equal(t, "fr", *m.Period[0].AdaptationSets[0].ContentComponents[0].Lang)
+	require.Nil(t, m.Period[0].AdaptationSets[1].Lang)
+	require.Empty(t, validateLangs(m))
+
+	m.Period[0].AdaptationSets[1].Lang = stringPtr("english language")
+	require.EqualError(t, m.NormalizeLangs(),
+		`MPD/Period[0]/AdaptationSet[1]@lang: invalid lang "english language": language: tag is not well-formed`)
+	require.Equal(t, []Finding{{Severity: SeverityError, Path: "MPD/Period[0]/AdaptationSet[1]@lang", Message: `invalid lang "english language"`}},
+		validateLangs(m))
+}
+
+func TestAdaptationSetsForLanguages(t *testing.T) {
+	en, enMain, pt, ru := &AdaptationSet{Lang: stringPtr("en")}, &AdaptationSet{Lang: stringPtr("EN")},
+		&AdaptationSet{Lang: stringPtr("pt-PT")}, &AdaptationSet{Lang: stringPtr("ru-RU")}
+	p := &Period{AdaptationSets: []*AdaptationSet{{}, en, pt, nil, enMain, ru, {Lang: stringPtr("e")}}}
+
+	require.Equal(t, []*AdaptationSet{en, enMain}, p.AdaptationSetsForLanguages("en"))
+	require.Equal(t, []*AdaptationSet{en, enMain}, p.AdaptationSetsForLanguages("en-GB"))
+	require.Equal(t, []*AdaptationSet{pt}, p.AdaptationSetsForLanguages("de", "pt-BR", "en"))
+	require.Equal(t, []*AdaptationSet{ru}, p.AdaptationSetsForLanguages("ru", "en"))
+	require.Nil(t, p.AdaptationSetsForLanguages("de", "fr"))
+	require.Nil(t, p.AdaptationSetsForLanguages())
+	require.Nil(t, (&Period{}).AdaptationSetsForLanguages("en"))
+}
@@ -0,0 +1,187 @@
+package mpd
+
+import (
+	"fmt"
+	"time"
+)
+
+// LatencyParams holds low-latency DASH parameters used for latency computations.
+// Zero value of a field means "not signaled".
+type LatencyParams struct {
+	// TargetLatency, MinLatency and MaxLatency come from ServiceDescription Latency element.
+	TargetLatency time.Duration
+	MinLatency    time.Duration
+	MaxLatency    time.Duration
+	// AvailabilityTimeOffset is a time segments are available before their end: the greatest sum of
+	// SegmentTemplate and BaseURL availabilityTimeOffset values of a Representation, except "INF".
+	AvailabilityTimeOffset time.Duration
+	// SegmentDuration is the longest media segment duration.
+	SegmentDuration time.Duration
+	// ChunkDuration is a CMAF chunk duration, SegmentDuration - AvailabilityTimeOffset if not known.
+	ChunkDuration              time.Duration
+	SuggestedPresentationDelay time.Duration
+}
+
+// LatencyParams extracts low-latency parameters from MPD.
+func (m *MPD) LatencyParams() (LatencyParams, error) {
+	var p LatencyParams
+	var err error
+	if p.SuggestedPresentationDelay, err = parseOptionalDuration(m.SuggestedPresentationDelay); err != nil {
+		return p, err
+	}
+	// the first ServiceDescription with Latency applies
+	for _, sd := range m.ServiceDescription {
+		if l := sd.Latency; l != nil {
+			p.TargetLatency, p.MinLatency, p.MaxLatency = millisToDuration(l.Target), millisToDuration(l.Min), millisToDuration(l.Max)
+			break
+		}
+	}
+
+	for pi := range m.Period {
+		period := &m.Period[pi]
+		for _, as := range period.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			for ri := range as.Representations {
+				r := &as.Representations[ri]
+				st := period.EffectiveSegmentTemplate(as, r)
+				ato := m.baseURLAvailabilityTimeOffset(period, as, r)
+				if st != nil && st.AvailabilityTimeOffset != nil {
+					ato += *st.AvailabilityTimeOffset
+				}
+				if !ato.IsInf() && ato.Duration() > p.AvailabilityTimeOffset {
+					p.AvailabilityTimeOffset = ato.Duration()
+				}
+				if st == nil {
+					continue
+				}
+				for _, s := range st.SegmentTimelineS {
+					if d := ticksToDuration(s.D, st.EffectiveTimescale()); d > p.SegmentDuration {
+						p.SegmentDuration = d
+					}
+				}
+				if st.Duration != nil {
+					if d := ticksToDuration(*st.Duration, st.EffectiveTimescale()); d > p.SegmentDuration {
+						p.SegmentDuration = d
+					}
+				}
+			}
+		}
+	}
+	return p, nil
+}
+
+// baseURLAvailabilityTimeOffset returns the sum of availabilityTimeOffset of BaseURLs from MPD to Representation
+// level which are used for segment URLs resolution, i.e. the first ones in document order.
+func (m *MPD) baseURLAvailabilityTimeOffset(p *Period, as *AdaptationSet, r *Representation) AvailabilityTimeOffset {
+	var res AvailabilityTimeOffset
+	for _, level := range [][]BaseURL{m.BaseURL, p.BaseURL, as.BaseURL, r.BaseURL} {
+		for _, b := range level {
+			if b.Value == "" {
+				continue
+			}
+			if b.AvailabilityTimeOffset != nil {
+				res += *b.AvailabilityTimeOffset
+			}
+			break
+		}
+	}
+	return res
+}
+
+// millisToDuration converts optional milliseconds value, nil is zero.
+func millisToDuration(ms *uint64) time.Duration {
+	if ms == nil {
+		return 0
+	}
+	return time.Duration(*ms) * time.Millisecond
+}
+
+// chunkDuration returns known or derived chunk duration.
+func (p LatencyParams) chunkDuration() time.Duration {
+	if p.ChunkDuration > 0 {
+		return p.ChunkDuration
+	}
+	if p.AvailabilityTimeOffset > 0 && p.AvailabilityTimeOffset < p.SegmentDuration {
+		return p.SegmentDuration - p.AvailabilityTimeOffset
+	}
+	return p.SegmentDuration
+}
+
+// AchievableLatency returns the lowest latency a client can get: it has to wait for
+// the first chunk (or the whole segment without availabilityTimeOffset) to become available.
+func (p LatencyParams) AchievableLatency() time.Duration {
+	l := p.SegmentDuration - p.AvailabilityTimeOffset
+	if c := p.chunkDuration(); l < c {
+		l = c
+	}
+	if l < 0 {
+		return 0
+	}
+	return l
+}
+
+// ExpectedLatency returns the latency a client is going to use: target latency if signaled,
+// suggestedPresentationDelay otherwise, but not less than AchievableLatency.
+func (p LatencyParams) ExpectedLatency() time.Duration {
+	l := p.TargetLatency
+	if l == 0 {
+		l = p.SuggestedPresentationDelay
+	}
+	if a := p.AchievableLatency(); l < a {
+		l = a
+	}
+	return l
+}
+
+// Check verifies that low-latency parameters are mutually consistent and returns found problems.
+func (p LatencyParams) Check() []string {
+	var res []string
+	add := func(format string, args ...interface{}) {
+		res = append(res, fmt.Sprintf(format, args...))
+	}
+
+	if p.SegmentDuration > 0 && p.AvailabilityTimeOffset > p.SegmentDuration {
+		add("availabilityTimeOffset %s is greater than segment duration %s", p.AvailabilityTimeOffset, p.SegmentDuration)
+	}
+	if p.SegmentDuration > 0 && p.ChunkDuration > p.SegmentDuration {
+		add("chunk duration %s is greater than segment duration %s", p.ChunkDuration, p.SegmentDuration)
+	}
+	if p.MinLatency > 0 && p.MaxLatency > 0 && p.MinLatency > p.MaxLatency {
+		add("minimum latency %s is greater than maximum latency %s", p.MinLatency, p.MaxLatency)
+	}
+	if p.TargetLatency > 0 {
+		if p.MinLatency > 0 && p.TargetLatency < p.MinLatency {
+			add("target latency %s is less than minimum latency %s", p.TargetLatency, p.MinLatency)
+		}
+		if p.MaxLatency > 0 && p.TargetLatency > p.MaxLatency {
+			add("target latency %s is greater than maximum latency %s", p.TargetLatency, p.MaxLatency)
+		}
+		if a := p.AchievableLatency(); p.TargetLatency < a {
+			add("target latency %s is less than achievable latency %s", p.TargetLatency, a)
+		}
+	}
+	if p.SuggestedPresentationDelay > 0 {
+		if a := p.AchievableLatency(); p.SuggestedPresentationDelay < a {
+			add("suggestedPresentationDelay %s is less than achievable latency %s", p.SuggestedPresentationDelay, a)
+		}
+	}
+	return res
+}
+
+// validateLatency checks low-latency parameters of dynamic MPD.
+func validateLatency(m *MPD) []Finding {
+	if !m.IsDynamic() {
+		return nil
+	}
+	p, err := m.LatencyParams()
+	if err != nil {
+		return []Finding{{Severity: SeverityError, Path: "MPD", Message: err.Error()}}
+	}
+	var res []Finding
+	for _, problem := range p.Check() {
+		res = append(res, Finding{Severity: SeverityWarning, Path: "MPD", Message: problem})
+	}
+	return res
+}
@@ -0,0 +1,102 @@
+package mpd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyParams(t *testing.T) {
+	m := decodeFixture(t, "fixture_flussonic_live.mpd")
+	p, err := m.LatencyParams()
+	require.NoError(t, err)
+	require.Equal(t, LatencyParams{
+		SegmentDuration:            8 * time.Second,
+		SuggestedPresentationDelay: 17 * time.Second,
+	}, p)
+	require.Equal(t, 8*time.Second, p.AchievableLatency())
+	require.Equal(t, 17*time.Second, p.ExpectedLatency())
+	require.Empty(t, p.Check())
+
+	m.SuggestedPresentationDelay = stringPtr("PT4S")
+	require.Equal(t, []Finding{{
+		Severity: SeverityWarning,
+		Path:     "MPD",
+		Message:  "suggestedPresentationDelay 4s is less than achievable latency 8s",
+	}}, m.Validate())
+}
+
+func TestLatencyParamsTemplateDuration(t *testing.T) {
+	m := decodeFixture(t, "fixture_segment_template_duration.mpd")
+	p, err := m.LatencyParams()
+	require.NoError(t, err)
+	require.Equal(t, 2*time.Second, p.SegmentDuration)
+}
+
+func TestLatencyParamsBaseURL(t *testing.T) {
+	m := decodeFixture(t, "fixture_base_url_attributes.mpd")
+	p, err := m.LatencyParams()
+	require.NoError(t, err)
+	require.Equal(t, 1500*time.Millisecond, p.AvailabilityTimeOffset)
+
+	// BaseURL and SegmentTemplate values along the hierarchy add up
+	ato := AvailabilityTimeOffset(0.5)
+	m.Period[0].BaseURL[0].AvailabilityTimeOffset = &ato
+	m.Period[0].AdaptationSets[0].Representations[0].SegmentTemplate.AvailabilityTimeOffset = &ato
+	p, err = m.LatencyParams()
+	require.NoError(t, err)
+	require.Equal(t, 2500*time.Millisecond, p.AvailabilityTimeOffset)
+
+	// "INF" on any level means segments are always available
+	m.BaseURL[0].AvailabilityTimeOffset = &InfiniteAvailabilityTimeOffset
+	p, err = m.LatencyParams()
+	require.NoError(t, err)
+	require.Equal(t, time.Duration(0), p.AvailabilityTimeOffset)
+}
+
+func TestLatencyParamsServiceDescription(t *testing.T) {
+	m := decodeFixture(t, "fixture_low_latency.mpd")
+	p, err := m.LatencyParams()
+	require.NoError(t, err)
+	require.Equal(t, LatencyParams{
+		TargetLatency:          3 * time.Second,
+		MinLatency:             2 * time.Second,
+		MaxLatency:             6 * time.Second,
+		AvailabilityTimeOffset: 1500 * time.Millisecond,
+		SegmentDuration:        2 * time.Second,
+	}, p)
+	require.Equal(t, 3*time.Second, p.ExpectedLatency())
+}
+
+func TestLatencyParamsLowLatency(t *testing.T) {
+	p := LatencyParams{
+		TargetLatency:          3 * time.Second,
+		MinLatency:             2 * time.Second,
+		MaxLatency:             6 * time.Second,
+		AvailabilityTimeOffset: 1500 * time.Millisecond,
+		SegmentDuration:        2 * time.Second,
+	}
+	require.Equal(t, 500*time.Millisecond, p.AchievableLatency())
+	require.Equal(t, 3*time.Second, p.ExpectedLatency())
+	require.Empty(t, p.Check())
+
+	p.ChunkDuration = time.Second
+	require.Equal(t, time.Second, p.AchievableLatency())
+
+	p = LatencyParams{
+		TargetLatency:          time.Second,
+		MinLatency:             3 * time.Second,
+		MaxLatency:             2 * time.Second,
+		AvailabilityTimeOffset: 3 * time.Second,
+		SegmentDuration:        2 * time.Second,
+		ChunkDuration:          4 * time.Second,
+	}
+	require.Equal(t, []string{
+		"availabilityTimeOffset 3s is greater than segment duration 2s",
+		"chunk duration 4s is greater than segment duration 2s",
+		"minimum latency 3s is greater than maximum latency 2s",
+		"target latency 1s is less than minimum latency 3s",
+		"target latency 1s is less than achievable latency 4s",
+	}, p.Check())
+}
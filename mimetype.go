@@ -0,0 +1,135 @@
+package mpd
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/mc2soft/mpd/codecs"
+)
+
+// Content types of AdaptationSet@contentType.
+const (
+	ContentTypeVideo = "video"
+	ContentTypeAudio = "audio"
+	ContentTypeText  = "text"
+	ContentTypeImage = "image"
+)
+
+// sampleEntryContentTypes maps codecs sample entries to content types.
+var sampleEntryContentTypes = map[string]string{
+	"avc1": ContentTypeVideo, "avc2": ContentTypeVideo, "avc3": ContentTypeVideo, "avc4": ContentTypeVideo,
+	"hvc1": ContentTypeVideo, "hev1": ContentTypeVideo, "dvh1": ContentTypeVideo, "dvhe": ContentTypeVideo,
+	"av01": ContentTypeVideo, "vp08": ContentTypeVideo, "vp09": ContentTypeVideo,
+	"mp4a": ContentTypeAudio, "ac-3": ContentTypeAudio, "ec-3": ContentTypeAudio, "ac-4": ContentTypeAudio,
+	"opus": ContentTypeAudio, "Opus": ContentTypeAudio, "fLaC": ContentTypeAudio, "flac": ContentTypeAudio,
+	"mha1": ContentTypeAudio, "mhm1": ContentTypeAudio, "dtsc": ContentTypeAudio, "dtsx": ContentTypeAudio,
+	"stpp": ContentTypeText, "wvtt": ContentTypeText,
+	"jpeg": ContentTypeImage, "png": ContentTypeImage,
+}
+
+// InferContentType returns content type (ContentTypeVideo, ContentTypeAudio, ContentTypeText or ContentTypeImage)
+// of codecs attribute value. Video wins over other types of multiplexed content, empty string is returned
+// for unknown or invalid codecs.
+func InferContentType(codecsValue string) string {
+	cs, err := codecs.ParseList(codecsValue)
+	if err != nil {
+		return ""
+	}
+	var res string
+	for _, c := range cs {
+		t, ok := sampleEntryContentTypes[c.SampleEntry()]
+		if !ok {
+			return ""
+		}
+		if res == "" || t == ContentTypeVideo {
+			res = t
+		}
+	}
+	return res
+}
+
+// InferMimeType returns mimeType of segments with codecs, media is a segment URL or template used to detect
+// container by its extension: ISO BMFF (default), WebM, MPEG-2 TS, WebVTT, JPEG and PNG are recognized.
+// Empty string is returned if the type can't be inferred.
+func InferMimeType(codecsValue, media string) string {
+	if i := strings.IndexAny(media, "?#"); i >= 0 {
+		media = media[:i]
+	}
+	ext := strings.ToLower(path.Ext(media))
+	switch ext {
+	case ".jpg", ".jpeg":
+		return "image/jpeg"
+	case ".png":
+		return "image/png"
+	case ".vtt":
+		return "text/vtt"
+	case ".ts":
+		return "video/mp2t"
+	}
+
+	contentType := InferContentType(codecsValue)
+	switch {
+	case contentType == ContentTypeImage:
+		if codecsValue == "png" {
+			return "image/png"
+		}
+		return "image/jpeg"
+	case ext == ".webm" && (contentType == ContentTypeVideo || contentType == ContentTypeAudio):
+		return contentType + "/webm"
+	case contentType == ContentTypeVideo || contentType == ContentTypeAudio:
+		return contentType + "/mp4"
+	case contentType == ContentTypeText:
+		return "application/mp4"
+	}
+	return ""
+}
+
+// mimeTypeContentType returns content type of mimeType: its top-level type, ISO BMFF subtitles are text.
+func mimeTypeContentType(mimeType string) string {
+	if strings.HasPrefix(mimeType, "application/mp4") || strings.HasPrefix(mimeType, "application/ttml+xml") {
+		return ContentTypeText
+	}
+	if i := strings.Index(mimeType, "/"); i > 0 {
+		return mimeType[:i]
+	}
+	return ""
+}
+
+// validateContentTypes checks that AdaptationSet@contentType, mimeType and codecs of AdaptationSet
+// and Representations describe the same content type.
+func validateContentTypes(m *MPD) []Finding {
+	var res []Finding
+	add := func(path, format string, args ...interface{}) {
+		res = append(res, Finding{Severity: SeverityWarning, Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for pi, p := range m.Period {
+		for ai, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			path := adaptationSetPath(pi, ai)
+			contentType := stringValue(as.ContentType)
+			mimeType := mimeTypeContentType(as.MimeType)
+			if contentType != "" && mimeType != "" && contentType != mimeType {
+				add(path, "contentType %q doesn't match mimeType %q", contentType, as.MimeType)
+			}
+			if contentType == "" {
+				contentType = mimeType
+			}
+			if contentType == "" {
+				continue
+			}
+			if t := InferContentType(stringValue(as.Codecs)); t != "" && t != contentType {
+				add(path, "codecs %q don't match content type %q", *as.Codecs, contentType)
+			}
+			for ri, r := range as.Representations {
+				if t := InferContentType(stringValue(r.Codecs)); t != "" && t != contentType {
+					add(representationPath(pi, ai, ri), "codecs %q don't match content type %q", *r.Codecs, contentType)
+				}
+			}
+		}
+	}
+	return res
+}
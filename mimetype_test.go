@@ -0,0 +1,61 @@
+package mpd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInferContentType(t *testing.T) {
+	for codecs, expected := range map[string]string{
+		"avc1.64001f":           ContentTypeVideo,
+		"mp4a.40.2":             ContentTypeAudio,
+		"ec-3":                  ContentTypeAudio,
+		"stpp.ttml.im1t":        ContentTypeText,
+		"jpeg":                  ContentTypeImage,
+		"mp4a.40.2,avc1.64001f": ContentTypeVideo,
+		"":                      "",
+		"unknown":               "",
+		"avc1":                  "",
+	} {
+		require.Equal(t, expected, InferContentType(codecs), codecs)
+	}
+}
+
+func TestInferMimeType(t *testing.T) {
+	for _, c := range []struct {
+		codecs, media, expected string
+	}{
+		{"avc1.64001f", "$RepresentationID$/$Number$.m4s", "video/mp4"},
+		{"mp4a.40.2", "", "audio/mp4"},
+		{"stpp.ttml.im1t", "text/$Number$.mp4", "application/mp4"},
+		{"jpeg", "thumbs/$Number$.jpg", "image/jpeg"},
+		{"png", "", "image/png"},
+		{"", "thumbs/$Number$.png?token=1", "image/png"},
+		{"vp09.00.10.08", "video.webm", "video/webm"},
+		{"avc1.64001f,mp4a.40.2", "segment.ts", "video/mp2t"},
+		{"wvtt", "subtitles.vtt", "text/vtt"},
+		{"unknown", "segment.m4s", ""},
+	} {
+		require.Equal(t, c.expected, InferMimeType(c.codecs, c.media), c)
+	}
+}
+
+func TestValidateContentTypes(t *testing.T) {
+	m := decodeFixture(t, "fixture_elemental_delta_vod_multi_drm.mpd")
+	audio := m.Period[0].AdaptationSets[1]
+	audio.ContentType = stringPtr(ContentTypeAudio)
+	require.Empty(t, m.Validate())
+
+	audio.Representations[1].Codecs = stringPtr("avc1.64001f")
+	audio.Codecs = stringPtr("stpp")
+	audio.ContentType = stringPtr(ContentTypeVideo)
+	require.Equal(t, []Finding{
+		{SeverityWarning, "MPD/Period[0]/AdaptationSet[1]", `contentType "video" doesn't match mimeType "audio/mp4"`},
+		{SeverityWarning, "MPD/Period[0]/AdaptationSet[1]", `codecs "stpp" don't match content type "video"`},
+		{SeverityWarning, "MPD/Period[0]/AdaptationSet[1]/Representation[0]", `codecs "mp4a.40.2" don't match content type "video"`},
+		{SeverityWarning, "MPD/Period[0]/AdaptationSet[1]/Representation[2]", `codecs "mp4a.40.2" don't match content type "video"`},
+		{SeverityWarning, "MPD/Period[0]/AdaptationSet[1]/Representation[3]", `codecs "mp4a.40.2" don't match content type "video"`},
+		{SeverityWarning, "MPD/Period[0]/AdaptationSet[1]/Representation[4]", `codecs "mp4a.40.2" don't match content type "video"`},
+	}, m.Validate())
+}
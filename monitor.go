@@ -0,0 +1,141 @@
+package mpd
+
+import (
+	"fmt"
+	"time"
+)
+
+// AlertKind describes the kind of problem detected by Monitor.
+type AlertKind string
+
+// Alert kinds.
+const (
+	// AlertStall means that timeline got no new segments within expected cadence.
+	AlertStall AlertKind = "stall"
+	// AlertLiveEdgeRegression means that the last segment end moved back in time.
+	AlertLiveEdgeRegression AlertKind = "live_edge_regression"
+	// AlertDVRWindowShrink means that available segments window or timeShiftBufferDepth got shorter.
+	AlertDVRWindowShrink AlertKind = "dvr_window_shrink"
+)
+
+// Alert represents single problem detected by Monitor.
+type Alert struct {
+	Kind           AlertKind
+	Period         string
+	Representation string
+	Message        string
+}
+
+// String implements fmt.Stringer.
+func (a Alert) String() string {
+	if a.Representation == "" {
+		return fmt.Sprintf("%s: %s", a.Kind, a.Message)
+	}
+	return fmt.Sprintf("%s: Period %s Representation %s: %s", a.Kind, a.Period, a.Representation, a.Message)
+}
+
+type monitorTrack struct {
+	first, end   float64   // first segment start and last segment end, seconds
+	lastDuration float64   // last segment duration, seconds
+	advanced     time.Time // when end changed last time
+}
+
+// Monitor compares successive versions of dynamic MPD and detects stalled timelines,
+// regressed live edge and shrinking DVR window.
+// It is transport-agnostic: feed it with every manifest version fetched by your refresh loop.
+type Monitor struct {
+	// Tolerance is added to the expected cadence and allowed window shrink.
+	Tolerance time.Duration
+
+	tracks map[string]*monitorTrack
+	tsbd   *time.Duration
+}
+
+// NewMonitor creates Monitor with given tolerance.
+func NewMonitor(tolerance time.Duration) *Monitor {
+	return &Monitor{
+		Tolerance: tolerance,
+		tracks:    make(map[string]*monitorTrack),
+	}
+}
+
+// Update processes new MPD version fetched at time now and returns detected problems.
+// Timelines are checked for the last Period only.
+func (mon *Monitor) Update(m *MPD, now time.Time) ([]Alert, error) {
+	timelines, err := m.Timelines()
+	if err != nil {
+		return nil, err
+	}
+	tolerance := mon.Tolerance.Seconds()
+
+	var res []Alert
+	if m.TimeShiftBufferDepth != nil {
+		tsbd, err := parseDuration(*m.TimeShiftBufferDepth)
+		if err != nil {
+			return nil, err
+		}
+		if mon.tsbd != nil && tsbd < *mon.tsbd-mon.Tolerance {
+			res = append(res, Alert{
+				Kind:    AlertDVRWindowShrink,
+				Message: fmt.Sprintf("timeShiftBufferDepth decreased from %s to %s", *mon.tsbd, tsbd),
+			})
+		}
+		mon.tsbd = &tsbd
+	}
+
+	// earlier Periods of multi-period MPD are complete and are removed from the front as the window slides,
+	// only the last Period is checked
+	var lastPeriod string
+	if len(m.Period) > 0 {
+		lastPeriod = periodName(len(m.Period)-1, m.Period[len(m.Period)-1].ID)
+	}
+	tracks := make(map[string]*monitorTrack)
+	for _, tl := range timelines {
+		if len(tl.Segments) == 0 || tl.Period != lastPeriod {
+			continue
+		}
+		first := tl.Segments[0]
+		last := tl.Segments[len(tl.Segments)-1]
+		cur := &monitorTrack{
+			first:        first.Start,
+			end:          last.Start + last.Duration,
+			lastDuration: last.Duration,
+			advanced:     now,
+		}
+
+		key := tl.Period + "\x00" + tl.Representation
+		prev := mon.tracks[key]
+		tracks[key] = cur
+		if prev == nil {
+			continue
+		}
+
+		alert := func(kind AlertKind, format string, args ...interface{}) {
+			res = append(res, Alert{
+				Kind:           kind,
+				Period:         tl.Period,
+				Representation: tl.Representation,
+				Message:        fmt.Sprintf(format, args...),
+			})
+		}
+
+		switch {
+		case cur.end < prev.end:
+			alert(AlertLiveEdgeRegression, "live edge moved back from %gs to %gs", prev.end, cur.end)
+		case cur.end == prev.end:
+			cur.advanced = prev.advanced
+			cadence := time.Duration((prev.lastDuration + tolerance) * float64(time.Second))
+			if stalled := now.Sub(prev.advanced); stalled > cadence {
+				alert(AlertStall, "no new segments for %s, expected every %gs", stalled, prev.lastDuration)
+			}
+		}
+
+		prevWindow, curWindow := prev.end-prev.first, cur.end-cur.first
+		if curWindow < prevWindow-tolerance {
+			alert(AlertDVRWindowShrink, "segments window decreased from %gs to %gs", prevWindow, curWindow)
+		}
+	}
+	// Representations and Periods absent from the current version are forgotten
+	mon.tracks = tracks
+	return res, nil
+}
@@ -0,0 +1,110 @@
+package mpd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitor(t *testing.T) {
+	m := decodeFixture(t, "fixture_flussonic_live.mpd")
+	m.Period[0].AdaptationSets = m.Period[0].AdaptationSets[1:]
+	st := m.Period[0].AdaptationSets[0].Representations[0].SegmentTemplate
+	now := time.Date(2021, 9, 21, 14, 28, 50, 0, time.UTC)
+
+	mon := NewMonitor(time.Second)
+	alerts, err := mon.Update(m, now)
+	require.NoError(t, err)
+	require.Empty(t, alerts)
+
+	// one more segment, the oldest one is removed
+	*st.SegmentTimelineS[0].T += 8000
+	alerts, err = mon.Update(m, now.Add(8*time.Second))
+	require.NoError(t, err)
+	require.Empty(t, alerts)
+
+	// same timeline within cadence is fine
+	alerts, err = mon.Update(m, now.Add(12*time.Second))
+	require.NoError(t, err)
+	require.Empty(t, alerts)
+
+	alerts, err = mon.Update(m, now.Add(20*time.Second))
+	require.NoError(t, err)
+	require.Equal(t, []Alert{{
+		Kind:           AlertStall,
+		Period:         "1631853774",
+		Representation: "tracks-a1",
+		Message:        "no new segments for 12s, expected every 8s",
+	}}, alerts)
+
+	// live edge goes back and window shrinks
+	*st.SegmentTimelineS[0].R = 10
+	m.TimeShiftBufferDepth = stringPtr("PT60S")
+	alerts, err = mon.Update(m, now.Add(24*time.Second))
+	require.NoError(t, err)
+	require.Equal(t, []Alert{
+		{Kind: AlertDVRWindowShrink, Message: "timeShiftBufferDepth decreased from 2m16s to 1m0s"},
+		{
+			Kind:           AlertLiveEdgeRegression,
+			Period:         "1631853774",
+			Representation: "tracks-a1",
+			Message:        "live edge moved back from 380764.753s to 380716.753s",
+		},
+		{
+			Kind:           AlertDVRWindowShrink,
+			Period:         "1631853774",
+			Representation: "tracks-a1",
+			Message:        "segments window decreased from 136s to 88s",
+		},
+	}, alerts)
+}
+
+func TestMonitorMultiPeriod(t *testing.T) {
+	period := func(id, start string, t0, r int64) Period {
+		return Period{
+			ID:    stringPtr(id),
+			Start: stringPtr(start),
+			AdaptationSets: []*AdaptationSet{{Representations: []Representation{{
+				ID: stringPtr("v1"),
+				SegmentTemplate: &SegmentTemplate{
+					Timescale: uint64Ptr(1000),
+					Media:     stringPtr("$Time$.m4s"),
+					SegmentTimelineS: []SegmentTimelineS{
+						{T: uint64Ptr(uint64(t0)), D: 2000, R: int64Ptr(r)},
+					},
+				},
+			}}}},
+		}
+	}
+	m := &MPD{
+		Type:                  PresentationTypeOf(PresentationTypeDynamic),
+		AvailabilityStartTime: dateTimePtr("2021-01-01T00:00:00Z"),
+		Period:                []Period{period("p0", "PT0S", 0, 3), period("p1", "PT8S", 0, 1)},
+	}
+	now := time.Date(2021, 1, 1, 0, 0, 12, 0, time.UTC)
+
+	mon := NewMonitor(time.Second)
+	alerts, err := mon.Update(m, now)
+	require.NoError(t, err)
+	require.Empty(t, alerts)
+
+	// the complete first Period loses its oldest segment, the last Period doesn't advance
+	m.Period[0] = period("p0", "PT0S", 2000, 2)
+	alerts, err = mon.Update(m, now.Add(4*time.Second))
+	require.NoError(t, err)
+	require.Equal(t, []Alert{{
+		Kind:           AlertStall,
+		Period:         "p1",
+		Representation: "v1",
+		Message:        "no new segments for 4s, expected every 2s",
+	}}, alerts)
+
+	// the first Period is gone, a new Period is added: it is not compared with anything, p1 is forgotten
+	m.Period = []Period{period("p1", "PT8S", 0, 1), period("p2", "PT12S", 0, 1)}
+	alerts, err = mon.Update(m, now.Add(6*time.Second))
+	require.NoError(t, err)
+	require.Empty(t, alerts)
+	require.Len(t, mon.tracks, 1)
+	require.Contains(t, mon.tracks, "p2\x00v1")
+}
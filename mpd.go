@@ -3,11 +3,14 @@ package mpd
 
 import (
 	"bytes"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
-	"io"
+	"math"
 	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
 	copyobj "github.com/mc2soft/mpd/utils"
 )
@@ -16,7 +19,8 @@ import (
 // https://www.brendanlong.com/the-structure-of-an-mpeg-dash-mpd.html
 // http://standards.iso.org/ittf/PubliclyAvailableStandards/MPEG-DASH_schema_files/DASH-MPD.xsd
 
-var emptyElementRE = regexp.MustCompile(`></[A-Za-z]+>`)
+// emptyElementRE matches start tag immediately followed by its end tag.
+var emptyElementRE = regexp.MustCompile(`(<[A-Za-z](?:[^<>]*[^<>/])?)></[A-Za-z][A-Za-z0-9:._-]*>`)
 
 // ConditionalUint (ConditionalUintType) defined in XSD as a union of unsignedInt and boolean.
 type ConditionalUint struct {
@@ -24,6 +28,38 @@ type ConditionalUint struct {
 	b *bool
 }
 
+// CondUint returns ConditionalUint set to unsigned integer u.
+func CondUint(u uint64) ConditionalUint {
+	return ConditionalUint{u: &u}
+}
+
+// CondBool returns ConditionalUint set to boolean b.
+func CondBool(b bool) ConditionalUint {
+	return ConditionalUint{b: &b}
+}
+
+// Uint returns unsigned integer value, ok is false if it's not set.
+func (c ConditionalUint) Uint() (u uint64, ok bool) {
+	if c.u == nil {
+		return 0, false
+	}
+	return *c.u, true
+}
+
+// Bool returns boolean value, ok is false if it's not set.
+func (c ConditionalUint) Bool() (b bool, ok bool) {
+	if c.b == nil {
+		return false, false
+	}
+	return *c.b, true
+}
+
+// IsTrue reports whether the condition holds: value is true or an unsigned integer, which identifies
+// a group of elements the condition holds for. Unset value is false.
+func (c ConditionalUint) IsTrue() bool {
+	return c.u != nil || c.b != nil && *c.b
+}
+
 // MarshalXMLAttr encodes ConditionalUint.
 func (c ConditionalUint) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
 	if c.u != nil {
@@ -55,89 +91,414 @@ func (c *ConditionalUint) UnmarshalXMLAttr(attr xml.Attr) error {
 	return fmt.Errorf("ConditionalUint: can't UnmarshalXMLAttr %#v", attr)
 }
 
+// MarshalJSON encodes ConditionalUint as JSON number or boolean, unset value is null.
+func (c ConditionalUint) MarshalJSON() ([]byte, error) {
+	switch {
+	case c.u != nil:
+		return json.Marshal(*c.u)
+	case c.b != nil:
+		return json.Marshal(*c.b)
+	default:
+		return []byte("null"), nil
+	}
+}
+
+// UnmarshalJSON decodes ConditionalUint from JSON number or boolean.
+func (c *ConditionalUint) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	var u uint64
+	if err := json.Unmarshal(b, &u); err == nil {
+		*c = CondUint(u)
+		return nil
+	}
+	var v bool
+	if err := json.Unmarshal(b, &v); err == nil {
+		*c = CondBool(v)
+		return nil
+	}
+	return fmt.Errorf("ConditionalUint: can't UnmarshalJSON %s", b)
+}
+
 // check interfaces
 var (
 	_ xml.MarshalerAttr   = ConditionalUint{}
 	_ xml.UnmarshalerAttr = &ConditionalUint{}
+	_ json.Marshaler      = ConditionalUint{}
+	_ json.Unmarshaler    = &ConditionalUint{}
 )
 
-// MPD represents root XML element for parse.
-type MPD struct {
-	XMLName                    xml.Name `xml:"MPD"`
-	XMLNS                      *string  `xml:"xmlns,attr"`
-	Type                       *string  `xml:"type,attr"`
-	MinimumUpdatePeriod        *string  `xml:"minimumUpdatePeriod,attr"`
-	AvailabilityStartTime      *string  `xml:"availabilityStartTime,attr"`
-	MediaPresentationDuration  *string  `xml:"mediaPresentationDuration,attr"`
-	MinBufferTime              *string  `xml:"minBufferTime,attr"`
-	SuggestedPresentationDelay *string  `xml:"suggestedPresentationDelay,attr"`
-	TimeShiftBufferDepth       *string  `xml:"timeShiftBufferDepth,attr"`
-	PublishTime                *string  `xml:"publishTime,attr"`
-	Profiles                   string   `xml:"profiles,attr"`
-	XSI                        *string  `xml:"xsi,attr,omitempty"`
-	SCTE35                     *string  `xml:"scte35,attr,omitempty"`
-	XSISchemaLocation          *string  `xml:"schemaLocation,attr"`
-	ID                         *string  `xml:"id,attr"`
-	BaseURL                    *string  `xml:"BaseURL,omitempty"`
-	Period                     []Period `xml:"Period,omitempty"`
+// AvailabilityTimeOffset represents availabilityTimeOffset attribute defined in XSD as a union of double and "INF".
+// Value is in seconds, "INF" is represented by positive infinity.
+type AvailabilityTimeOffset float64
+
+// InfiniteAvailabilityTimeOffset is the "INF" value: segments are available at any time.
+var InfiniteAvailabilityTimeOffset = AvailabilityTimeOffset(math.Inf(1))
+
+// IsInf returns true for the "INF" value.
+func (a AvailabilityTimeOffset) IsInf() bool {
+	return math.IsInf(float64(a), 1)
 }
 
-// MPD represents root XML element for Marshal.
-type mpdMarshal struct {
-	XMLName                    xml.Name        `xml:"MPD"`
-	XSI                        *string         `xml:"xmlns:xsi,attr,omitempty"`
-	XMLNS                      *string         `xml:"xmlns,attr"`
-	XSISchemaLocation          *string         `xml:"xsi:schemaLocation,attr"`
-	ID                         *string         `xml:"id,attr"`
-	Type                       *string         `xml:"type,attr"`
-	PublishTime                *string         `xml:"publishTime,attr"`
-	MinimumUpdatePeriod        *string         `xml:"minimumUpdatePeriod,attr"`
-	AvailabilityStartTime      *string         `xml:"availabilityStartTime,attr"`
-	MediaPresentationDuration  *string         `xml:"mediaPresentationDuration,attr"`
-	MinBufferTime              *string         `xml:"minBufferTime,attr"`
-	SuggestedPresentationDelay *string         `xml:"suggestedPresentationDelay,attr"`
-	TimeShiftBufferDepth       *string         `xml:"timeShiftBufferDepth,attr"`
-	Profiles                   string          `xml:"profiles,attr"`
-	SCTE35                     *string         `xml:"xmlns:scte35,attr,omitempty"`
-	BaseURL                    *string         `xml:"BaseURL,omitempty"`
-	Period                     []periodMarshal `xml:"Period,omitempty"`
+// Duration returns offset as time.Duration, "INF" is returned as the maximal duration.
+func (a AvailabilityTimeOffset) Duration() time.Duration {
+	if a.IsInf() {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(math.Round(float64(a) * float64(time.Second)))
 }
 
-// Do not try to use encoding.TextMarshaler and encoding.TextUnmarshaler:
-// https://github.com/golang/go/issues/6859#issuecomment-118890463
+// MarshalXMLAttr encodes AvailabilityTimeOffset.
+func (a *AvailabilityTimeOffset) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	// encoding/xml calls it for nil pointers too
+	if a == nil {
+		return xml.Attr{}, nil
+	}
+	if a.IsInf() {
+		return xml.Attr{Name: name, Value: "INF"}, nil
+	}
+	return xml.Attr{Name: name, Value: strconv.FormatFloat(float64(*a), 'f', -1, 64)}, nil
+}
 
-// Encode generates MPD XML.
-func (m *MPD) Encode() ([]byte, error) {
-	x := new(bytes.Buffer)
-	e := xml.NewEncoder(x)
-	e.Indent("", "  ")
+// UnmarshalXMLAttr decodes AvailabilityTimeOffset.
+func (a *AvailabilityTimeOffset) UnmarshalXMLAttr(attr xml.Attr) error {
+	if attr.Value == "INF" {
+		*a = InfiniteAvailabilityTimeOffset
+		return nil
+	}
 
-	xml := modifyMPD(m)
+	f, err := strconv.ParseFloat(attr.Value, 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return fmt.Errorf("AvailabilityTimeOffset: can't UnmarshalXMLAttr %#v", attr)
+	}
+	*a = AvailabilityTimeOffset(f)
+	return nil
+}
 
-	err := e.Encode(xml)
-	if err != nil {
-		return nil, err
+// MarshalJSON encodes AvailabilityTimeOffset as JSON number, "INF" is encoded as string.
+func (a AvailabilityTimeOffset) MarshalJSON() ([]byte, error) {
+	if a.IsInf() {
+		return []byte(`"INF"`), nil
 	}
+	return json.Marshal(float64(a))
+}
 
-	// hacks for self-closing tags
-	res := new(bytes.Buffer)
-	res.WriteString(`<?xml version="1.0" encoding="utf-8"?>`)
-	res.WriteByte('\n')
-	for {
-		s, err := x.ReadString('\n')
-		if s != "" {
-			s = emptyElementRE.ReplaceAllString(s, `/>`)
-			res.WriteString(s)
+// UnmarshalJSON decodes AvailabilityTimeOffset from JSON number or "INF" string.
+func (a *AvailabilityTimeOffset) UnmarshalJSON(b []byte) error {
+	if string(b) == `"INF"` {
+		*a = InfiniteAvailabilityTimeOffset
+		return nil
+	}
+	var f float64
+	if err := json.Unmarshal(b, &f); err != nil {
+		return fmt.Errorf("AvailabilityTimeOffset: can't UnmarshalJSON %s", b)
+	}
+	*a = AvailabilityTimeOffset(f)
+	return nil
+}
+
+// check interfaces
+var (
+	_ xml.MarshalerAttr   = new(AvailabilityTimeOffset)
+	_ xml.UnmarshalerAttr = new(AvailabilityTimeOffset)
+	_ json.Marshaler      = AvailabilityTimeOffset(0)
+	_ json.Unmarshaler    = new(AvailabilityTimeOffset)
+)
+
+// FrameRate represents XSD's FrameRateType: "F" or "F/D", e.g. "25" or "30000/1001".
+// It keeps the attribute text, so it's encoded exactly as decoded.
+type FrameRate string
+
+// NewFrameRate returns FrameRate num/den, den 1 is omitted.
+func NewFrameRate(num, den uint64) FrameRate {
+	if den == 1 {
+		return FrameRate(strconv.FormatUint(num, 10))
+	}
+	return FrameRate(strconv.FormatUint(num, 10) + "/" + strconv.FormatUint(den, 10))
+}
+
+// Ratio returns frame rate numerator and denominator, denominator is 1 if it's not set.
+func (f FrameRate) Ratio() (num, den uint64, err error) {
+	parts := strings.Split(string(f), "/")
+	if len(parts) > 2 {
+		return 0, 0, fmt.Errorf("invalid frame rate %q", f)
+	}
+	if num, err = strconv.ParseUint(parts[0], 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid frame rate %q", f)
+	}
+	den = 1
+	if len(parts) == 2 {
+		if den, err = strconv.ParseUint(parts[1], 10, 64); err != nil || den == 0 {
+			return 0, 0, fmt.Errorf("invalid frame rate %q", f)
 		}
-		if err == io.EOF {
-			break
+	}
+	return num, den, nil
+}
+
+// Float returns frame rate in frames per second.
+func (f FrameRate) Float() (float64, error) {
+	num, den, err := f.Ratio()
+	if err != nil {
+		return 0, err
+	}
+	return float64(num) / float64(den), nil
+}
+
+// Ratio represents XSD's RatioType of sar and par attributes: "W:H", e.g. "16:9".
+// It keeps the attribute text, so it's encoded exactly as decoded.
+type Ratio string
+
+// NewRatio returns Ratio w:h.
+func NewRatio(w, h uint64) Ratio {
+	return Ratio(strconv.FormatUint(w, 10) + ":" + strconv.FormatUint(h, 10))
+}
+
+// Values returns ratio width and height, it fails if ratio isn't "W:H" with non-negative integers.
+func (r Ratio) Values() (w, h uint64, err error) {
+	parts := strings.Split(string(r), ":")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid ratio %q", r)
+	}
+	if w, err = strconv.ParseUint(parts[0], 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid ratio %q", r)
+	}
+	if h, err = strconv.ParseUint(parts[1], 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid ratio %q", r)
+	}
+	return w, h, nil
+}
+
+// Float returns ratio as W/H, it fails for invalid ratio or zero H.
+func (r Ratio) Float() (float64, error) {
+	w, h, err := r.Values()
+	if err != nil {
+		return 0, err
+	}
+	if h == 0 {
+		return 0, fmt.Errorf("invalid ratio %q", r)
+	}
+	return float64(w) / float64(h), nil
+}
+
+// AudioSamplingRate represents audioSamplingRate attribute: either a single sampling rate in Hz
+// or a "min max" pair for variable rate. It keeps the attribute text, so it's encoded exactly as decoded.
+type AudioSamplingRate string
+
+// NewAudioSamplingRate returns AudioSamplingRate of a single rate.
+func NewAudioSamplingRate(rate uint64) AudioSamplingRate {
+	return AudioSamplingRate(strconv.FormatUint(rate, 10))
+}
+
+// NewAudioSamplingRateRange returns AudioSamplingRate of min and max rates.
+func NewAudioSamplingRateRange(min, max uint64) AudioSamplingRate {
+	return AudioSamplingRate(strconv.FormatUint(min, 10) + " " + strconv.FormatUint(max, 10))
+}
+
+// Values returns minimum and maximum sampling rates, they are equal for a single rate.
+// It fails if the attribute isn't one or two non-negative integers or min exceeds max.
+func (a AudioSamplingRate) Values() (min, max uint64, err error) {
+	fields := strings.Fields(string(a))
+	if len(fields) == 0 || len(fields) > 2 {
+		return 0, 0, fmt.Errorf("invalid audioSamplingRate %q", a)
+	}
+	if min, err = strconv.ParseUint(fields[0], 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid audioSamplingRate %q", a)
+	}
+	max = min
+	if len(fields) == 2 {
+		if max, err = strconv.ParseUint(fields[1], 10, 64); err != nil || max < min {
+			return 0, 0, fmt.Errorf("invalid audioSamplingRate %q", a)
 		}
+	}
+	return min, max, nil
+}
+
+// PresentationType represents MPD@type.
+type PresentationType string
+
+// Presentation types.
+const (
+	PresentationTypeStatic  PresentationType = "static"
+	PresentationTypeDynamic PresentationType = "dynamic"
+)
+
+// PresentationTypeOf returns pointer to t to set MPD.Type.
+func PresentationTypeOf(t PresentationType) *PresentationType {
+	return &t
+}
+
+// UnmarshalXMLAttr decodes PresentationType, failing for unknown values.
+func (t *PresentationType) UnmarshalXMLAttr(attr xml.Attr) error {
+	switch v := PresentationType(attr.Value); v {
+	case PresentationTypeStatic, PresentationTypeDynamic:
+		*t = v
+		return nil
+	}
+	return fmt.Errorf("PresentationType: can't UnmarshalXMLAttr %#v", attr)
+}
+
+// check interfaces
+var (
+	_ xml.UnmarshalerAttr = new(PresentationType)
+)
+
+// StringVector represents XSD's StringVectorType and ListOfFourCCType: whitespace-separated list of strings.
+type StringVector []string
+
+// MarshalXMLAttr encodes StringVector.
+func (v StringVector) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if len(v) == 0 {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: strings.Join(v, " ")}, nil
+}
+
+// UnmarshalXMLAttr decodes StringVector.
+func (v *StringVector) UnmarshalXMLAttr(attr xml.Attr) error {
+	*v = strings.Fields(attr.Value)
+	return nil
+}
+
+// check interfaces
+var (
+	_ xml.MarshalerAttr   = StringVector{}
+	_ xml.UnmarshalerAttr = new(StringVector)
+)
+
+// UIntVector represents XSD's UIntVectorType: whitespace-separated list of unsigned integers.
+type UIntVector []uint64
+
+// MarshalXMLAttr encodes UIntVector.
+func (v UIntVector) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if len(v) == 0 {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: v.String()}, nil
+}
+
+// UnmarshalXMLAttr decodes UIntVector.
+func (v *UIntVector) UnmarshalXMLAttr(attr xml.Attr) error {
+	res, err := parseUIntVector(attr.Value)
+	if err != nil {
+		return fmt.Errorf("UIntVector: can't UnmarshalXMLAttr %#v", attr)
+	}
+	*v = res
+	return nil
+}
+
+// String returns space-separated values.
+func (v UIntVector) String() string {
+	s := make([]string, 0, len(v))
+	for _, u := range v {
+		s = append(s, strconv.FormatUint(u, 10))
+	}
+	return strings.Join(s, " ")
+}
+
+func parseUIntVector(s string) (UIntVector, error) {
+	var res UIntVector
+	for _, f := range strings.Fields(s) {
+		u, err := strconv.ParseUint(f, 10, 64)
 		if err != nil {
 			return nil, err
 		}
+		res = append(res, u)
 	}
-	res.WriteByte('\n')
-	return res.Bytes(), err
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ xml.MarshalerAttr   = UIntVector{}
+	_ xml.UnmarshalerAttr = new(UIntVector)
+)
+
+// MPD represents root XML element for parse.
+type MPD struct {
+	XMLName                    xml.Name             `xml:"MPD" json:"-"`
+	XMLNS                      *string              `xml:"xmlns,attr" json:"xmlns,omitempty"`
+	Type                       *PresentationType    `xml:"type,attr" json:"type,omitempty"`
+	MinimumUpdatePeriod        *string              `xml:"minimumUpdatePeriod,attr" json:"minimumUpdatePeriod,omitempty"`
+	AvailabilityStartTime      *DateTime            `xml:"availabilityStartTime,attr" json:"availabilityStartTime,omitempty"`
+	AvailabilityEndTime        *DateTime            `xml:"availabilityEndTime,attr" json:"availabilityEndTime,omitempty"`
+	MediaPresentationDuration  *string              `xml:"mediaPresentationDuration,attr" json:"mediaPresentationDuration,omitempty"`
+	MinBufferTime              *string              `xml:"minBufferTime,attr" json:"minBufferTime,omitempty"`
+	SuggestedPresentationDelay *string              `xml:"suggestedPresentationDelay,attr" json:"suggestedPresentationDelay,omitempty"`
+	TimeShiftBufferDepth       *string              `xml:"timeShiftBufferDepth,attr" json:"timeShiftBufferDepth,omitempty"`
+	PublishTime                *DateTime            `xml:"publishTime,attr" json:"publishTime,omitempty"`
+	Profiles                   Profiles             `xml:"profiles,attr" json:"profiles,omitempty"`
+	XSI                        *string              `xml:"xsi,attr,omitempty" json:"xsi,omitempty"`
+	SCTE35                     *string              `xml:"scte35,attr,omitempty" json:"scte35,omitempty"`
+	DVB                        *string              `xml:"dvb,attr,omitempty" json:"dvb,omitempty"`
+	XLink                      *string              `xml:"xlink,attr,omitempty" json:"xLink,omitempty"`
+	XSISchemaLocation          *string              `xml:"schemaLocation,attr" json:"xsiSchemaLocation,omitempty"`
+	ID                         *string              `xml:"id,attr" json:"id,omitempty"`
+	ProgramInformation         []ProgramInformation `xml:"ProgramInformation,omitempty" json:"programInformation,omitempty"`
+	BaseURL                    []BaseURL            `xml:"BaseURL,omitempty" json:"baseURL,omitempty"`
+	Location                   []string             `xml:"Location" json:"location,omitempty"`
+	PatchLocation              []PatchLocation      `xml:"PatchLocation,omitempty" json:"patchLocation,omitempty"`
+	ServiceDescription         []ServiceDescription `xml:"ServiceDescription,omitempty" json:"serviceDescription,omitempty"`
+	InitializationSet          []InitializationSet  `xml:"InitializationSet,omitempty" json:"initializationSet,omitempty"`
+	InitializationGroup        []UIntVWithID        `xml:"InitializationGroup,omitempty" json:"initializationGroup,omitempty"`
+	InitializationPresentation []UIntVWithID        `xml:"InitializationPresentation,omitempty" json:"initializationPresentation,omitempty"`
+	ContentSteering            *ContentSteering     `xml:"ContentSteering,omitempty" json:"contentSteering,omitempty"`
+	Period                     []Period             `xml:"Period,omitempty" json:"period,omitempty"`
+	Metrics                    []Metrics            `xml:"Metrics,omitempty" json:"metrics,omitempty"`
+	SupplementalProperty       []Descriptor         `xml:"SupplementalProperty,omitempty" json:"supplementalProperty,omitempty"`
+	UTCTiming                  []Descriptor         `xml:"UTCTiming,omitempty" json:"utcTiming,omitempty"`
+	ExtensionAttrs             []xml.Attr           `xml:",any,attr" json:"extensionAttrs,omitempty"`
+	Extensions                 []Extension          `xml:",any" json:"extensions,omitempty"`
+}
+
+// MPD represents root XML element for Marshal.
+// Child elements fields must be declared in XSD order, encoding/xml emits them in declaration order.
+type mpdMarshal struct {
+	XMLName                    xml.Name                   `xml:"MPD"`
+	XSI                        *string                    `xml:"xmlns:xsi,attr,omitempty"`
+	XMLNS                      *string                    `xml:"xmlns,attr"`
+	XSISchemaLocation          *string                    `xml:"xsi:schemaLocation,attr"`
+	ID                         *string                    `xml:"id,attr"`
+	Type                       *PresentationType          `xml:"type,attr"`
+	PublishTime                *DateTime                  `xml:"publishTime,attr"`
+	MinimumUpdatePeriod        *string                    `xml:"minimumUpdatePeriod,attr"`
+	AvailabilityStartTime      *DateTime                  `xml:"availabilityStartTime,attr"`
+	AvailabilityEndTime        *DateTime                  `xml:"availabilityEndTime,attr"`
+	MediaPresentationDuration  *string                    `xml:"mediaPresentationDuration,attr"`
+	MinBufferTime              *string                    `xml:"minBufferTime,attr"`
+	SuggestedPresentationDelay *string                    `xml:"suggestedPresentationDelay,attr"`
+	TimeShiftBufferDepth       *string                    `xml:"timeShiftBufferDepth,attr"`
+	Profiles                   Profiles                   `xml:"profiles,attr"`
+	SCTE35                     *string                    `xml:"xmlns:scte35,attr,omitempty"`
+	DVB                        *string                    `xml:"xmlns:dvb,attr,omitempty"`
+	XLink                      *string                    `xml:"xmlns:xlink,attr,omitempty"`
+	ProgramInformation         []ProgramInformation       `xml:"ProgramInformation,omitempty"`
+	BaseURL                    []baseURLMarshal           `xml:"BaseURL,omitempty"`
+	Location                   []string                   `xml:"Location"`
+	PatchLocation              []PatchLocation            `xml:"PatchLocation,omitempty"`
+	ServiceDescription         []ServiceDescription       `xml:"ServiceDescription,omitempty"`
+	InitializationSet          []initializationSetMarshal `xml:"InitializationSet,omitempty"`
+	InitializationGroup        []UIntVWithID              `xml:"InitializationGroup,omitempty"`
+	InitializationPresentation []UIntVWithID              `xml:"InitializationPresentation,omitempty"`
+	ContentSteering            *ContentSteering           `xml:"ContentSteering,omitempty"`
+	Period                     []periodMarshal            `xml:"Period,omitempty"`
+	Metrics                    []metricsMarshal           `xml:"Metrics,omitempty"`
+	SupplementalProperty       []Descriptor               `xml:"SupplementalProperty,omitempty"`
+	UTCTiming                  []Descriptor               `xml:"UTCTiming,omitempty"`
+	ExtensionAttrs             []xml.Attr                 `xml:",any,attr"`
+	Extensions                 []extensionMarshal         `xml:",any"`
+}
+
+// Do not try to use encoding.TextMarshaler and encoding.TextUnmarshaler:
+// https://github.com/golang/go/issues/6859#issuecomment-118890463
+
+// Encode generates MPD XML, opts configure the output format.
+func (m *MPD) Encode(opts ...EncodeOption) ([]byte, error) {
+	res := new(bytes.Buffer)
+	if err := NewEncoder(res, opts...).Encode(m); err != nil {
+		return nil, err
+	}
+	return res.Bytes(), nil
 }
 
 // Decode parses MPD XML.
@@ -145,99 +506,602 @@ func (m *MPD) Decode(b []byte) error {
 	return xml.Unmarshal(b, m)
 }
 
+// UTCTiming schemes defined by ISO/IEC 23009-1.
+const (
+	UTCTimingNTP        = "urn:mpeg:dash:utc:ntp:2014"
+	UTCTimingSNTP       = "urn:mpeg:dash:utc:sntp:2014"
+	UTCTimingHTTPHead   = "urn:mpeg:dash:utc:http-head:2014"
+	UTCTimingHTTPXSDate = "urn:mpeg:dash:utc:http-xsdate:2014"
+	UTCTimingHTTPISO    = "urn:mpeg:dash:utc:http-iso:2014"
+	UTCTimingHTTPNTP    = "urn:mpeg:dash:utc:http-ntp:2014"
+	UTCTimingDirect     = "urn:mpeg:dash:utc:direct:2014"
+)
+
+// XLinkNamespace is a namespace of xlink:href and xlink:actuate attributes.
+const XLinkNamespace = "http://www.w3.org/1999/xlink"
+
+// xlink:actuate values.
+const (
+	XLinkActuateOnLoad    = "onLoad"
+	XLinkActuateOnRequest = "onRequest"
+)
+
+// ProgramInformation represents XSD's ProgramInformationType.
+type ProgramInformation struct {
+	Lang               *string `xml:"lang,attr" json:"lang,omitempty"`
+	MoreInformationURL *string `xml:"moreInformationURL,attr" json:"moreInformationURL,omitempty"`
+	Title              *string `xml:"Title,omitempty" json:"title,omitempty"`
+	Source             *string `xml:"Source,omitempty" json:"source,omitempty"`
+	Copyright          *string `xml:"Copyright,omitempty" json:"copyright,omitempty"`
+}
+
+// PatchLocation represents XSD's PatchLocationType.
+type PatchLocation struct {
+	// TTL is a time in seconds the patch location is valid after MPD publishTime.
+	TTL   *float64 `xml:"ttl,attr" json:"ttl,omitempty"`
+	Value string   `xml:",chardata" json:"value,omitempty"`
+}
+
+// Metrics represents XSD's MetricsType.
+type Metrics struct {
+	Metrics   string         `xml:"metrics,attr" json:"metrics,omitempty"`
+	Reporting []Reporting    `xml:"Reporting,omitempty" json:"reporting,omitempty"`
+	Range     []MetricsRange `xml:"Range,omitempty" json:"range,omitempty"`
+}
+
+type metricsMarshal struct {
+	Metrics   string             `xml:"metrics,attr"`
+	Reporting []reportingMarshal `xml:"Reporting,omitempty"`
+	Range     []MetricsRange     `xml:"Range,omitempty"`
+}
+
+// ContentSteering represents XSD's ContentSteeringType, Value is a steering server URL.
+type ContentSteering struct {
+	Value                  string  `xml:",chardata" json:"value,omitempty"`
+	DefaultServiceLocation *string `xml:"defaultServiceLocation,attr" json:"defaultServiceLocation,omitempty"`
+	QueryBeforeStart       *bool   `xml:"queryBeforeStart,attr" json:"queryBeforeStart,omitempty"`
+	ClientRequirement      *bool   `xml:"clientRequirement,attr" json:"clientRequirement,omitempty"`
+	ProxyServerURL         *string `xml:"proxyServerURL,attr" json:"proxyServerURL,omitempty"`
+}
+
+// InitializationSet represents XSD's InitializationSetType: a common initialization
+// for AdaptationSets referencing it by initializationSetRef.
+type InitializationSet struct {
+	XLinkHref            *string      `xml:"href,attr" json:"xLinkHref,omitempty"`
+	XLinkActuate         *string      `xml:"actuate,attr" json:"xLinkActuate,omitempty"`
+	ID                   *uint64      `xml:"id,attr" json:"id,omitempty"`
+	InAllPeriods         *bool        `xml:"inAllPeriods,attr" json:"inAllPeriods,omitempty"`
+	ContentType          *string      `xml:"contentType,attr" json:"contentType,omitempty"`
+	Par                  *Ratio       `xml:"par,attr" json:"par,omitempty"`
+	MaxWidth             *uint64      `xml:"maxWidth,attr" json:"maxWidth,omitempty"`
+	MaxHeight            *uint64      `xml:"maxHeight,attr" json:"maxHeight,omitempty"`
+	MaxFrameRate         *FrameRate   `xml:"maxFrameRate,attr" json:"maxFrameRate,omitempty"`
+	Initialization       *string      `xml:"initialization,attr" json:"initialization,omitempty"`
+	Profiles             *string      `xml:"profiles,attr" json:"profiles,omitempty"`
+	MimeType             *string      `xml:"mimeType,attr" json:"mimeType,omitempty"`
+	Codecs               *string      `xml:"codecs,attr" json:"codecs,omitempty"`
+	EssentialProperty    []Descriptor `xml:"EssentialProperty,omitempty" json:"essentialProperty,omitempty"`
+	SupplementalProperty []Descriptor `xml:"SupplementalProperty,omitempty" json:"supplementalProperty,omitempty"`
+	Accessibility        []Descriptor `xml:"Accessibility,omitempty" json:"accessibility,omitempty"`
+	Role                 []Descriptor `xml:"Role,omitempty" json:"role,omitempty"`
+	Rating               []Descriptor `xml:"Rating,omitempty" json:"rating,omitempty"`
+	Viewpoint            []Descriptor `xml:"Viewpoint,omitempty" json:"viewpoint,omitempty"`
+}
+
+type initializationSetMarshal struct {
+	XLinkHref            *string      `xml:"xlink:href,attr,omitempty"`
+	XLinkActuate         *string      `xml:"xlink:actuate,attr,omitempty"`
+	ID                   *uint64      `xml:"id,attr"`
+	InAllPeriods         *bool        `xml:"inAllPeriods,attr"`
+	ContentType          *string      `xml:"contentType,attr"`
+	Par                  *Ratio       `xml:"par,attr"`
+	MaxWidth             *uint64      `xml:"maxWidth,attr"`
+	MaxHeight            *uint64      `xml:"maxHeight,attr"`
+	MaxFrameRate         *FrameRate   `xml:"maxFrameRate,attr"`
+	Initialization       *string      `xml:"initialization,attr"`
+	Profiles             *string      `xml:"profiles,attr"`
+	MimeType             *string      `xml:"mimeType,attr"`
+	Codecs               *string      `xml:"codecs,attr"`
+	EssentialProperty    []Descriptor `xml:"EssentialProperty,omitempty"`
+	SupplementalProperty []Descriptor `xml:"SupplementalProperty,omitempty"`
+	Accessibility        []Descriptor `xml:"Accessibility,omitempty"`
+	Role                 []Descriptor `xml:"Role,omitempty"`
+	Rating               []Descriptor `xml:"Rating,omitempty"`
+	Viewpoint            []Descriptor `xml:"Viewpoint,omitempty"`
+}
+
+// UIntVWithID represents XSD's UIntVWithIDType of InitializationGroup and InitializationPresentation elements.
+type UIntVWithID struct {
+	// Value is a whitespace-separated list of InitializationSet ids, see IDs.
+	Value       string  `xml:",chardata" json:"value,omitempty"`
+	ID          *uint64 `xml:"id,attr" json:"id,omitempty"`
+	Profiles    *string `xml:"profiles,attr" json:"profiles,omitempty"`
+	ContentType *string `xml:"contentType,attr" json:"contentType,omitempty"`
+}
+
+// IDs parses Value.
+func (u *UIntVWithID) IDs() (UIntVector, error) {
+	return parseUIntVector(u.Value)
+}
+
+// Switching represents XSD's SwitchingType: switching points interval in timescale units,
+// Type is "media" (default) or "bitstream".
+type Switching struct {
+	Interval *uint64 `xml:"interval,attr" json:"interval,omitempty"`
+	Type     *string `xml:"type,attr" json:"type,omitempty"`
+}
+
+// RandomAccess represents XSD's RandomAccessType: random access points interval in timescale units,
+// Type is "closed" (default), "open" or "gradual".
+type RandomAccess struct {
+	Interval      *uint64 `xml:"interval,attr" json:"interval,omitempty"`
+	Type          *string `xml:"type,attr" json:"type,omitempty"`
+	MinBufferTime *string `xml:"minBufferTime,attr" json:"minBufferTime,omitempty"`
+	Bandwidth     *uint64 `xml:"bandwidth,attr" json:"bandwidth,omitempty"`
+}
+
+// Resync represents XSD's ResyncType: resynchronization points within segments.
+type Resync struct {
+	Type   *uint64  `xml:"type,attr" json:"type,omitempty"`
+	DT     *uint64  `xml:"dT,attr" json:"dt,omitempty"`
+	DIMax  *float64 `xml:"dImax,attr" json:"diMax,omitempty"`
+	DIMin  *float64 `xml:"dImin,attr" json:"diMin,omitempty"`
+	Marker *bool    `xml:"marker,attr" json:"marker,omitempty"`
+}
+
+// ServiceDescription represents XSD's ServiceDescriptionType.
+type ServiceDescription struct {
+	ID                 *uint64              `xml:"id,attr" json:"id,omitempty"`
+	Scope              []Descriptor         `xml:"Scope,omitempty" json:"scope,omitempty"`
+	Latency            *Latency             `xml:"Latency,omitempty" json:"latency,omitempty"`
+	PlaybackRate       *PlaybackRate        `xml:"PlaybackRate,omitempty" json:"playbackRate,omitempty"`
+	OperatingQuality   []OperatingQuality   `xml:"OperatingQuality,omitempty" json:"operatingQuality,omitempty"`
+	OperatingBandwidth []OperatingBandwidth `xml:"OperatingBandwidth,omitempty" json:"operatingBandwidth,omitempty"`
+}
+
+// Latency represents XSD's LatencyType, values are in milliseconds.
+type Latency struct {
+	ReferenceID *uint64 `xml:"referenceId,attr" json:"referenceID,omitempty"`
+	Target      *uint64 `xml:"target,attr" json:"target,omitempty"`
+	Max         *uint64 `xml:"max,attr" json:"max,omitempty"`
+	Min         *uint64 `xml:"min,attr" json:"min,omitempty"`
+}
+
+// PlaybackRate represents XSD's PlaybackRateType.
+type PlaybackRate struct {
+	Max *float64 `xml:"max,attr" json:"max,omitempty"`
+	Min *float64 `xml:"min,attr" json:"min,omitempty"`
+}
+
+// OperatingQuality represents XSD's OperatingQualityType.
+type OperatingQuality struct {
+	MediaType     *string `xml:"mediaType,attr" json:"mediaType,omitempty"`
+	Min           *uint64 `xml:"min,attr" json:"min,omitempty"`
+	Max           *uint64 `xml:"max,attr" json:"max,omitempty"`
+	Target        *uint64 `xml:"target,attr" json:"target,omitempty"`
+	Type          *string `xml:"type,attr" json:"type,omitempty"`
+	MaxDifference *uint64 `xml:"maxDifference,attr" json:"maxDifference,omitempty"`
+}
+
+// OperatingBandwidth represents XSD's OperatingBandwidthType, values are in bits per second.
+type OperatingBandwidth struct {
+	MediaType *string `xml:"mediaType,attr" json:"mediaType,omitempty"`
+	Min       *uint64 `xml:"min,attr" json:"min,omitempty"`
+	Max       *uint64 `xml:"max,attr" json:"max,omitempty"`
+	Target    *uint64 `xml:"target,attr" json:"target,omitempty"`
+}
+
+// BaseURL represents XSD's BaseURLType with DVB-DASH extension attributes.
+type BaseURL struct {
+	Value                    string                  `xml:",chardata" json:"value,omitempty"`
+	ServiceLocation          *string                 `xml:"serviceLocation,attr" json:"serviceLocation,omitempty"`
+	ByteRange                *string                 `xml:"byteRange,attr" json:"byteRange,omitempty"`
+	AvailabilityTimeOffset   *AvailabilityTimeOffset `xml:"availabilityTimeOffset,attr" json:"availabilityTimeOffset,omitempty"`
+	AvailabilityTimeComplete *bool                   `xml:"availabilityTimeComplete,attr" json:"availabilityTimeComplete,omitempty"`
+	DVBPriority              *uint64                 `xml:"priority,attr" json:"dvbPriority,omitempty"`
+	DVBWeight                *uint64                 `xml:"weight,attr" json:"dvbWeight,omitempty"`
+}
+
+type baseURLMarshal struct {
+	Value                    string                  `xml:",chardata"`
+	ServiceLocation          *string                 `xml:"serviceLocation,attr"`
+	ByteRange                *string                 `xml:"byteRange,attr"`
+	AvailabilityTimeOffset   *AvailabilityTimeOffset `xml:"availabilityTimeOffset,attr"`
+	AvailabilityTimeComplete *bool                   `xml:"availabilityTimeComplete,attr"`
+	DVBPriority              *uint64                 `xml:"dvb:priority,attr"`
+	DVBWeight                *uint64                 `xml:"dvb:weight,attr"`
+}
+
+// Reporting represents XSD's DescriptorType of Reporting element with DVB-DASH extension attributes.
+type Reporting struct {
+	SchemeIDURI     *string `xml:"schemeIdUri,attr" json:"schemeIDURI,omitempty"`
+	Value           *string `xml:"value,attr" json:"value,omitempty"`
+	ID              *string `xml:"id,attr" json:"id,omitempty"`
+	DVBReportingURL *string `xml:"reportingUrl,attr" json:"dvbReportingURL,omitempty"`
+	DVBProbability  *uint64 `xml:"probability,attr" json:"dvbProbability,omitempty"`
+}
+
+type reportingMarshal struct {
+	SchemeIDURI     *string `xml:"schemeIdUri,attr"`
+	Value           *string `xml:"value,attr"`
+	ID              *string `xml:"id,attr"`
+	DVBReportingURL *string `xml:"dvb:reportingUrl,attr"`
+	DVBProbability  *uint64 `xml:"dvb:probability,attr"`
+}
+
+// MetricsRange represents XSD's RangeType.
+type MetricsRange struct {
+	Starttime *string `xml:"starttime,attr" json:"starttime,omitempty"`
+	Duration  *string `xml:"duration,attr" json:"duration,omitempty"`
+}
+
 // Period represents XSD's PeriodType.
 type Period struct {
-	Start          *string          `xml:"start,attr"`
-	ID             *string          `xml:"id,attr"`
-	Duration       *string          `xml:"duration,attr"`
-	AdaptationSets []*AdaptationSet `xml:"AdaptationSet,omitempty"`
+	Start           *string          `xml:"start,attr" json:"start,omitempty"`
+	ID              *string          `xml:"id,attr" json:"id,omitempty"`
+	Duration        *string          `xml:"duration,attr" json:"duration,omitempty"`
+	XLinkHref       *string          `xml:"href,attr" json:"xLinkHref,omitempty"`
+	XLinkActuate    *string          `xml:"actuate,attr" json:"xLinkActuate,omitempty"`
+	BaseURL         []BaseURL        `xml:"BaseURL,omitempty" json:"baseURL,omitempty"`
+	SegmentBase     *SegmentBase     `xml:"SegmentBase,omitempty" json:"segmentBase,omitempty"`
+	SegmentList     *SegmentList     `xml:"SegmentList,omitempty" json:"segmentList,omitempty"`
+	SegmentTemplate *SegmentTemplate `xml:"SegmentTemplate,omitempty" json:"segmentTemplate,omitempty"`
+	AssetIdentifier *Descriptor      `xml:"AssetIdentifier,omitempty" json:"assetIdentifier,omitempty"`
+	EventStreams    []EventStream    `xml:"EventStream,omitempty" json:"eventStreams,omitempty"`
+	AdaptationSets  []*AdaptationSet `xml:"AdaptationSet,omitempty" json:"adaptationSets,omitempty"`
+	Subsets         []Subset         `xml:"Subset,omitempty" json:"subsets,omitempty"`
+	Preselections   []Preselection   `xml:"Preselection,omitempty" json:"preselections,omitempty"`
+	ExtensionAttrs  []xml.Attr       `xml:",any,attr" json:"extensionAttrs,omitempty"`
+	Extensions      []Extension      `xml:",any" json:"extensions,omitempty"`
 }
 
 // Period represents XSD's PeriodType.
 type periodMarshal struct {
-	Start          *string                 `xml:"start,attr"`
-	ID             *string                 `xml:"id,attr"`
-	Duration       *string                 `xml:"duration,attr"`
-	AdaptationSets []*adaptationSetMarshal `xml:"AdaptationSet,omitempty"`
+	Start           *string                 `xml:"start,attr"`
+	ID              *string                 `xml:"id,attr"`
+	Duration        *string                 `xml:"duration,attr"`
+	XLinkHref       *string                 `xml:"xlink:href,attr,omitempty"`
+	XLinkActuate    *string                 `xml:"xlink:actuate,attr,omitempty"`
+	BaseURL         []baseURLMarshal        `xml:"BaseURL,omitempty"`
+	SegmentBase     *SegmentBase            `xml:"SegmentBase,omitempty"`
+	SegmentList     *segmentListMarshal     `xml:"SegmentList,omitempty"`
+	SegmentTemplate *segmentTemplateMarshal `xml:"SegmentTemplate,omitempty"`
+	AssetIdentifier *Descriptor             `xml:"AssetIdentifier,omitempty"`
+	EventStreams    []eventStreamMarshal    `xml:"EventStream,omitempty"`
+	AdaptationSets  []*adaptationSetMarshal `xml:"AdaptationSet,omitempty"`
+	Subsets         []Subset                `xml:"Subset,omitempty"`
+	Preselections   []Preselection          `xml:"Preselection,omitempty"`
+	ExtensionAttrs  []xml.Attr              `xml:",any,attr"`
+	Extensions      []extensionMarshal      `xml:",any"`
+}
+
+// EventStream represents XSD's EventStreamType.
+type EventStream struct {
+	SchemeIDURI            *string `xml:"schemeIdUri,attr" json:"schemeIDURI,omitempty"`
+	Value                  *string `xml:"value,attr" json:"value,omitempty"`
+	Timescale              *uint64 `xml:"timescale,attr" json:"timescale,omitempty"`
+	PresentationTimeOffset *uint64 `xml:"presentationTimeOffset,attr" json:"presentationTimeOffset,omitempty"`
+	XLinkHref              *string `xml:"href,attr" json:"xLinkHref,omitempty"`
+	XLinkActuate           *string `xml:"actuate,attr" json:"xLinkActuate,omitempty"`
+	Events                 []Event `xml:"Event,omitempty" json:"events,omitempty"`
+}
+
+type eventStreamMarshal struct {
+	SchemeIDURI            *string        `xml:"schemeIdUri,attr"`
+	Value                  *string        `xml:"value,attr"`
+	Timescale              *uint64        `xml:"timescale,attr"`
+	PresentationTimeOffset *uint64        `xml:"presentationTimeOffset,attr"`
+	XLinkHref              *string        `xml:"xlink:href,attr,omitempty"`
+	XLinkActuate           *string        `xml:"xlink:actuate,attr,omitempty"`
+	Events                 []eventMarshal `xml:"Event,omitempty"`
+}
+
+// Event represents XSD's EventType.
+type Event struct {
+	PresentationTime *uint64 `xml:"presentationTime,attr" json:"presentationTime,omitempty"`
+	Duration         *uint64 `xml:"duration,attr" json:"duration,omitempty"`
+	ID               *uint64 `xml:"id,attr" json:"id,omitempty"`
+	ContentEncoding  *string `xml:"contentEncoding,attr" json:"contentEncoding,omitempty"`
+	MessageData      *string `xml:"messageData,attr" json:"messageData,omitempty"`
+	// Signal and SpliceInfoSection are SCTE-35 splice information of SCTE35SchemeXMLBin and
+	// SCTE35SchemeXML event streams.
+	Signal            *SCTE35Signal            `xml:"Signal,omitempty" json:"signal,omitempty"`
+	SpliceInfoSection *SCTE35SpliceInfoSection `xml:"SpliceInfoSection,omitempty" json:"spliceInfoSection,omitempty"`
+	// Payload is the raw content of Event element: text or nested XML, it's written only if
+	// Signal and SpliceInfoSection are nil.
+	Payload string `xml:",innerxml" json:"payload,omitempty"`
+}
+
+type eventMarshal struct {
+	PresentationTime  *uint64                         `xml:"presentationTime,attr"`
+	Duration          *uint64                         `xml:"duration,attr"`
+	ID                *uint64                         `xml:"id,attr"`
+	ContentEncoding   *string                         `xml:"contentEncoding,attr"`
+	MessageData       *string                         `xml:"messageData,attr"`
+	Signal            *scte35SignalMarshal            `xml:"scte35:Signal,omitempty"`
+	SpliceInfoSection *scte35SpliceInfoSectionMarshal `xml:"scte35:SpliceInfoSection,omitempty"`
+	Payload           string                          `xml:",innerxml"`
+}
+
+// Subset represents XSD's SubsetType: a combination of AdaptationSets which may be presented together.
+type Subset struct {
+	// Contains lists ids of AdaptationSets.
+	Contains UIntVector `xml:"contains,attr" json:"contains,omitempty"`
+	ID       *string    `xml:"id,attr" json:"id,omitempty"`
+}
+
+// Preselection represents XSD's PreselectionType: a combination of AdaptationSets
+// (e.g. next-generation audio components) played together.
+type Preselection struct {
+	ID *string `xml:"id,attr" json:"id,omitempty"`
+	// PreselectionComponents lists ids of AdaptationSets or ContentComponents, main one first.
+	PreselectionComponents    StringVector       `xml:"preselectionComponents,attr" json:"preselectionComponents,omitempty"`
+	Lang                      *string            `xml:"lang,attr" json:"lang,omitempty"`
+	Order                     *string            `xml:"order,attr" json:"order,omitempty"`
+	Codecs                    *string            `xml:"codecs,attr" json:"codecs,omitempty"`
+	AudioSamplingRate         *AudioSamplingRate `xml:"audioSamplingRate,attr" json:"audioSamplingRate,omitempty"`
+	AudioChannelConfiguration []Descriptor       `xml:"AudioChannelConfiguration,omitempty" json:"audioChannelConfiguration,omitempty"`
+	EssentialProperty         []Descriptor       `xml:"EssentialProperty,omitempty" json:"essentialProperty,omitempty"`
+	SupplementalProperty      []Descriptor       `xml:"SupplementalProperty,omitempty" json:"supplementalProperty,omitempty"`
+	Accessibility             []Descriptor       `xml:"Accessibility,omitempty" json:"accessibility,omitempty"`
+	Role                      []Descriptor       `xml:"Role,omitempty" json:"role,omitempty"`
+	Rating                    []Descriptor       `xml:"Rating,omitempty" json:"rating,omitempty"`
+	Viewpoint                 []Descriptor       `xml:"Viewpoint,omitempty" json:"viewpoint,omitempty"`
 }
 
 // AdaptationSet represents XSD's AdaptationSetType.
 type AdaptationSet struct {
-	MimeType                string           `xml:"mimeType,attr"`
-	SegmentAlignment        ConditionalUint  `xml:"segmentAlignment,attr"`
-	StartWithSAP            *uint64          `xml:"startWithSAP,attr"`
-	BitstreamSwitching      *bool            `xml:"bitstreamSwitching,attr"`
-	SubsegmentAlignment     ConditionalUint  `xml:"subsegmentAlignment,attr"`
-	SubsegmentStartsWithSAP *uint64          `xml:"subsegmentStartsWithSAP,attr"`
-	Lang                    *string          `xml:"lang,attr"`
-	ContentProtections      []DRMDescriptor  `xml:"ContentProtection,omitempty"`
-	Representations         []Representation `xml:"Representation,omitempty"`
-	Codecs                  *string          `xml:"codecs,attr"`
+	ID                        *string            `xml:"id,attr" json:"id,omitempty"`
+	MimeType                  string             `xml:"mimeType,attr" json:"mimeType,omitempty"`
+	SegmentAlignment          ConditionalUint    `xml:"segmentAlignment,attr" json:"segmentAlignment,omitempty"`
+	StartWithSAP              *uint64            `xml:"startWithSAP,attr" json:"startWithSAP,omitempty"`
+	BitstreamSwitching        *bool              `xml:"bitstreamSwitching,attr" json:"bitstreamSwitching,omitempty"`
+	SubsegmentAlignment       ConditionalUint    `xml:"subsegmentAlignment,attr" json:"subsegmentAlignment,omitempty"`
+	SubsegmentStartsWithSAP   *uint64            `xml:"subsegmentStartsWithSAP,attr" json:"subsegmentStartsWithSAP,omitempty"`
+	Lang                      *string            `xml:"lang,attr" json:"lang,omitempty"`
+	ContentType               *string            `xml:"contentType,attr" json:"contentType,omitempty"`
+	Group                     *uint64            `xml:"group,attr" json:"group,omitempty"`
+	MinBandwidth              *uint64            `xml:"minBandwidth,attr" json:"minBandwidth,omitempty"`
+	MaxBandwidth              *uint64            `xml:"maxBandwidth,attr" json:"maxBandwidth,omitempty"`
+	MinWidth                  *uint64            `xml:"minWidth,attr" json:"minWidth,omitempty"`
+	MaxWidth                  *uint64            `xml:"maxWidth,attr" json:"maxWidth,omitempty"`
+	MinHeight                 *uint64            `xml:"minHeight,attr" json:"minHeight,omitempty"`
+	MaxHeight                 *uint64            `xml:"maxHeight,attr" json:"maxHeight,omitempty"`
+	MinFrameRate              *FrameRate         `xml:"minFrameRate,attr" json:"minFrameRate,omitempty"`
+	MaxFrameRate              *FrameRate         `xml:"maxFrameRate,attr" json:"maxFrameRate,omitempty"`
+	Profiles                  *string            `xml:"profiles,attr" json:"profiles,omitempty"`
+	ScanType                  *string            `xml:"scanType,attr" json:"scanType,omitempty"`
+	CodingDependency          *bool              `xml:"codingDependency,attr" json:"codingDependency,omitempty"`
+	MaximumSAPPeriod          *float64           `xml:"maximumSAPPeriod,attr" json:"maximumSAPPeriod,omitempty"`
+	MaxPlayoutRate            *float64           `xml:"maxPlayoutRate,attr" json:"maxPlayoutRate,omitempty"`
+	SelectionPriority         *uint64            `xml:"selectionPriority,attr" json:"selectionPriority,omitempty"`
+	Tag                       *string            `xml:"tag,attr" json:"tag,omitempty"`
+	SegmentProfiles           StringVector       `xml:"segmentProfiles,attr,omitempty" json:"segmentProfiles,omitempty"`
+	InitializationSetRef      UIntVector         `xml:"initializationSetRef,attr,omitempty" json:"initializationSetRef,omitempty"`
+	XLinkHref                 *string            `xml:"href,attr" json:"xLinkHref,omitempty"`
+	XLinkActuate              *string            `xml:"actuate,attr" json:"xLinkActuate,omitempty"`
+	FramePacking              []Descriptor       `xml:"FramePacking,omitempty" json:"framePacking,omitempty"`
+	AudioChannelConfiguration []Descriptor       `xml:"AudioChannelConfiguration,omitempty" json:"audioChannelConfiguration,omitempty"`
+	ContentProtections        []DRMDescriptor    `xml:"ContentProtection,omitempty" json:"contentProtections,omitempty"`
+	EssentialProperty         []Descriptor       `xml:"EssentialProperty,omitempty" json:"essentialProperty,omitempty"`
+	SupplementalProperty      []Descriptor       `xml:"SupplementalProperty,omitempty" json:"supplementalProperty,omitempty"`
+	InbandEventStreams        []Descriptor       `xml:"InbandEventStream,omitempty" json:"inbandEventStreams,omitempty"`
+	Switching                 []Switching        `xml:"Switching,omitempty" json:"switching,omitempty"`
+	RandomAccess              []RandomAccess     `xml:"RandomAccess,omitempty" json:"randomAccess,omitempty"`
+	Resync                    []Resync           `xml:"Resync,omitempty" json:"resync,omitempty"`
+	Accessibility             []Descriptor       `xml:"Accessibility,omitempty" json:"accessibility,omitempty"`
+	Role                      []Descriptor       `xml:"Role,omitempty" json:"role,omitempty"`
+	Rating                    []Descriptor       `xml:"Rating,omitempty" json:"rating,omitempty"`
+	ContentComponents         []ContentComponent `xml:"ContentComponent,omitempty" json:"contentComponents,omitempty"`
+	BaseURL                   []BaseURL          `xml:"BaseURL,omitempty" json:"baseURL,omitempty"`
+	SegmentBase               *SegmentBase       `xml:"SegmentBase,omitempty" json:"segmentBase,omitempty"`
+	SegmentList               *SegmentList       `xml:"SegmentList,omitempty" json:"segmentList,omitempty"`
+	SegmentTemplate           *SegmentTemplate   `xml:"SegmentTemplate,omitempty" json:"segmentTemplate,omitempty"`
+	Representations           []Representation   `xml:"Representation,omitempty" json:"representations,omitempty"`
+	ExtensionAttrs            []xml.Attr         `xml:",any,attr" json:"extensionAttrs,omitempty"`
+	Extensions                []Extension        `xml:",any" json:"extensions,omitempty"`
+	Codecs                    *string            `xml:"codecs,attr" json:"codecs,omitempty"`
 }
 
 type adaptationSetMarshal struct {
-	MimeType                string                  `xml:"mimeType,attr"`
-	SegmentAlignment        ConditionalUint         `xml:"segmentAlignment,attr"`
-	StartWithSAP            *uint64                 `xml:"startWithSAP,attr"`
-	BitstreamSwitching      *bool                   `xml:"bitstreamSwitching,attr"`
-	SubsegmentAlignment     ConditionalUint         `xml:"subsegmentAlignment,attr"`
-	SubsegmentStartsWithSAP *uint64                 `xml:"subsegmentStartsWithSAP,attr"`
-	Lang                    *string                 `xml:"lang,attr"`
-	ContentProtections      []drmDescriptorMarshal  `xml:"ContentProtection,omitempty"`
-	Representations         []representationMarshal `xml:"Representation,omitempty"`
-	Codecs                  *string                 `xml:"codecs,attr"`
+	ID                        *string                 `xml:"id,attr"`
+	MimeType                  string                  `xml:"mimeType,attr"`
+	SegmentAlignment          ConditionalUint         `xml:"segmentAlignment,attr"`
+	StartWithSAP              *uint64                 `xml:"startWithSAP,attr"`
+	BitstreamSwitching        *bool                   `xml:"bitstreamSwitching,attr"`
+	SubsegmentAlignment       ConditionalUint         `xml:"subsegmentAlignment,attr"`
+	SubsegmentStartsWithSAP   *uint64                 `xml:"subsegmentStartsWithSAP,attr"`
+	Lang                      *string                 `xml:"lang,attr"`
+	ContentType               *string                 `xml:"contentType,attr"`
+	Group                     *uint64                 `xml:"group,attr"`
+	MinBandwidth              *uint64                 `xml:"minBandwidth,attr"`
+	MaxBandwidth              *uint64                 `xml:"maxBandwidth,attr"`
+	MinWidth                  *uint64                 `xml:"minWidth,attr"`
+	MaxWidth                  *uint64                 `xml:"maxWidth,attr"`
+	MinHeight                 *uint64                 `xml:"minHeight,attr"`
+	MaxHeight                 *uint64                 `xml:"maxHeight,attr"`
+	MinFrameRate              *FrameRate              `xml:"minFrameRate,attr"`
+	MaxFrameRate              *FrameRate              `xml:"maxFrameRate,attr"`
+	Profiles                  *string                 `xml:"profiles,attr"`
+	ScanType                  *string                 `xml:"scanType,attr"`
+	CodingDependency          *bool                   `xml:"codingDependency,attr"`
+	MaximumSAPPeriod          *float64                `xml:"maximumSAPPeriod,attr"`
+	MaxPlayoutRate            *float64                `xml:"maxPlayoutRate,attr"`
+	SelectionPriority         *uint64                 `xml:"selectionPriority,attr"`
+	Tag                       *string                 `xml:"tag,attr"`
+	SegmentProfiles           StringVector            `xml:"segmentProfiles,attr,omitempty"`
+	InitializationSetRef      UIntVector              `xml:"initializationSetRef,attr,omitempty"`
+	XLinkHref                 *string                 `xml:"xlink:href,attr,omitempty"`
+	XLinkActuate              *string                 `xml:"xlink:actuate,attr,omitempty"`
+	FramePacking              []Descriptor            `xml:"FramePacking,omitempty"`
+	AudioChannelConfiguration []Descriptor            `xml:"AudioChannelConfiguration,omitempty"`
+	ContentProtections        []drmDescriptorMarshal  `xml:"ContentProtection,omitempty"`
+	EssentialProperty         []Descriptor            `xml:"EssentialProperty,omitempty"`
+	SupplementalProperty      []Descriptor            `xml:"SupplementalProperty,omitempty"`
+	InbandEventStreams        []Descriptor            `xml:"InbandEventStream,omitempty"`
+	Switching                 []Switching             `xml:"Switching,omitempty"`
+	RandomAccess              []RandomAccess          `xml:"RandomAccess,omitempty"`
+	Resync                    []Resync                `xml:"Resync,omitempty"`
+	Accessibility             []Descriptor            `xml:"Accessibility,omitempty"`
+	Role                      []Descriptor            `xml:"Role,omitempty"`
+	Rating                    []Descriptor            `xml:"Rating,omitempty"`
+	ContentComponents         []ContentComponent      `xml:"ContentComponent,omitempty"`
+	BaseURL                   []baseURLMarshal        `xml:"BaseURL,omitempty"`
+	SegmentBase               *SegmentBase            `xml:"SegmentBase,omitempty"`
+	SegmentList               *segmentListMarshal     `xml:"SegmentList,omitempty"`
+	SegmentTemplate           *segmentTemplateMarshal `xml:"SegmentTemplate,omitempty"`
+	Representations           []representationMarshal `xml:"Representation,omitempty"`
+	ExtensionAttrs            []xml.Attr              `xml:",any,attr"`
+	Extensions                []extensionMarshal      `xml:",any"`
+	Codecs                    *string                 `xml:"codecs,attr"`
+}
+
+// ContentComponent represents XSD's ContentComponentType.
+type ContentComponent struct {
+	ID            *string      `xml:"id,attr" json:"id,omitempty"`
+	Lang          *string      `xml:"lang,attr" json:"lang,omitempty"`
+	ContentType   *string      `xml:"contentType,attr" json:"contentType,omitempty"`
+	Par           *Ratio       `xml:"par,attr" json:"par,omitempty"`
+	Accessibility []Descriptor `xml:"Accessibility,omitempty" json:"accessibility,omitempty"`
+	Role          []Descriptor `xml:"Role,omitempty" json:"role,omitempty"`
+	Rating        []Descriptor `xml:"Rating,omitempty" json:"rating,omitempty"`
+	Viewpoint     []Descriptor `xml:"Viewpoint,omitempty" json:"viewpoint,omitempty"`
 }
 
 // Representation represents XSD's RepresentationType.
 type Representation struct {
-	ID                 *string          `xml:"id,attr"`
-	Width              *uint64          `xml:"width,attr"`
-	Height             *uint64          `xml:"height,attr"`
-	SAR                *string          `xml:"sar,attr"`
-	FrameRate          *string          `xml:"frameRate,attr"`
-	Bandwidth          *uint64          `xml:"bandwidth,attr"`
-	AudioSamplingRate  *string          `xml:"audioSamplingRate,attr"`
-	Codecs             *string          `xml:"codecs,attr"`
-	BaseURL            *string          `xml:"BaseURL,omitempty"`
-	ContentProtections []DRMDescriptor  `xml:"ContentProtection,omitempty"`
-	SegmentTemplate    *SegmentTemplate `xml:"SegmentTemplate,omitempty"`
+	ID                        *string            `xml:"id,attr" json:"id,omitempty"`
+	Width                     *uint64            `xml:"width,attr" json:"width,omitempty"`
+	Height                    *uint64            `xml:"height,attr" json:"height,omitempty"`
+	SAR                       *Ratio             `xml:"sar,attr" json:"sar,omitempty"`
+	FrameRate                 *FrameRate         `xml:"frameRate,attr" json:"frameRate,omitempty"`
+	Bandwidth                 *uint64            `xml:"bandwidth,attr" json:"bandwidth,omitempty"`
+	AudioSamplingRate         *AudioSamplingRate `xml:"audioSamplingRate,attr" json:"audioSamplingRate,omitempty"`
+	Codecs                    *string            `xml:"codecs,attr" json:"codecs,omitempty"`
+	QualityRanking            *uint64            `xml:"qualityRanking,attr,omitempty" json:"qualityRanking,omitempty"`
+	DependencyID              StringVector       `xml:"dependencyId,attr,omitempty" json:"dependencyID,omitempty"`
+	AssociationID             StringVector       `xml:"associationId,attr,omitempty" json:"associationID,omitempty"`
+	AssociationType           StringVector       `xml:"associationType,attr,omitempty" json:"associationType,omitempty"`
+	MediaStreamStructureID    StringVector       `xml:"mediaStreamStructureId,attr,omitempty" json:"mediaStreamStructureID,omitempty"`
+	Profiles                  *string            `xml:"profiles,attr" json:"profiles,omitempty"`
+	ScanType                  *string            `xml:"scanType,attr" json:"scanType,omitempty"`
+	CodingDependency          *bool              `xml:"codingDependency,attr" json:"codingDependency,omitempty"`
+	MaximumSAPPeriod          *float64           `xml:"maximumSAPPeriod,attr" json:"maximumSAPPeriod,omitempty"`
+	MaxPlayoutRate            *float64           `xml:"maxPlayoutRate,attr" json:"maxPlayoutRate,omitempty"`
+	SelectionPriority         *uint64            `xml:"selectionPriority,attr" json:"selectionPriority,omitempty"`
+	Tag                       *string            `xml:"tag,attr" json:"tag,omitempty"`
+	SegmentProfiles           StringVector       `xml:"segmentProfiles,attr,omitempty" json:"segmentProfiles,omitempty"`
+	FramePacking              []Descriptor       `xml:"FramePacking,omitempty" json:"framePacking,omitempty"`
+	AudioChannelConfiguration []Descriptor       `xml:"AudioChannelConfiguration,omitempty" json:"audioChannelConfiguration,omitempty"`
+	ContentProtections        []DRMDescriptor    `xml:"ContentProtection,omitempty" json:"contentProtections,omitempty"`
+	EssentialProperty         []Descriptor       `xml:"EssentialProperty,omitempty" json:"essentialProperty,omitempty"`
+	SupplementalProperty      []Descriptor       `xml:"SupplementalProperty,omitempty" json:"supplementalProperty,omitempty"`
+	InbandEventStreams        []Descriptor       `xml:"InbandEventStream,omitempty" json:"inbandEventStreams,omitempty"`
+	Switching                 []Switching        `xml:"Switching,omitempty" json:"switching,omitempty"`
+	RandomAccess              []RandomAccess     `xml:"RandomAccess,omitempty" json:"randomAccess,omitempty"`
+	Resync                    []Resync           `xml:"Resync,omitempty" json:"resync,omitempty"`
+	BaseURL                   []BaseURL          `xml:"BaseURL,omitempty" json:"baseURL,omitempty"`
+	SegmentBase               *SegmentBase       `xml:"SegmentBase,omitempty" json:"segmentBase,omitempty"`
+	SegmentList               *SegmentList       `xml:"SegmentList,omitempty" json:"segmentList,omitempty"`
+	SegmentTemplate           *SegmentTemplate   `xml:"SegmentTemplate,omitempty" json:"segmentTemplate,omitempty"`
+	ExtensionAttrs            []xml.Attr         `xml:",any,attr" json:"extensionAttrs,omitempty"`
+	Extensions                []Extension        `xml:",any" json:"extensions,omitempty"`
 }
 
 type representationMarshal struct {
-	ID                 *string                `xml:"id,attr"`
-	Width              *uint64                `xml:"width,attr"`
-	Height             *uint64                `xml:"height,attr"`
-	SAR                *string                `xml:"sar,attr"`
-	FrameRate          *string                `xml:"frameRate,attr"`
-	Bandwidth          *uint64                `xml:"bandwidth,attr"`
-	AudioSamplingRate  *string                `xml:"audioSamplingRate,attr"`
-	Codecs             *string                `xml:"codecs,attr"`
-	BaseURL            *string                `xml:"BaseURL,omitempty"`
-	ContentProtections []drmDescriptorMarshal `xml:"ContentProtection,omitempty"`
-	SegmentTemplate    *SegmentTemplate       `xml:"SegmentTemplate,omitempty"`
+	ID                        *string                 `xml:"id,attr"`
+	Width                     *uint64                 `xml:"width,attr"`
+	Height                    *uint64                 `xml:"height,attr"`
+	SAR                       *Ratio                  `xml:"sar,attr"`
+	FrameRate                 *FrameRate              `xml:"frameRate,attr"`
+	Bandwidth                 *uint64                 `xml:"bandwidth,attr"`
+	AudioSamplingRate         *AudioSamplingRate      `xml:"audioSamplingRate,attr"`
+	Codecs                    *string                 `xml:"codecs,attr"`
+	QualityRanking            *uint64                 `xml:"qualityRanking,attr,omitempty"`
+	DependencyID              StringVector            `xml:"dependencyId,attr,omitempty"`
+	AssociationID             StringVector            `xml:"associationId,attr,omitempty"`
+	AssociationType           StringVector            `xml:"associationType,attr,omitempty"`
+	MediaStreamStructureID    StringVector            `xml:"mediaStreamStructureId,attr,omitempty"`
+	Profiles                  *string                 `xml:"profiles,attr"`
+	ScanType                  *string                 `xml:"scanType,attr"`
+	CodingDependency          *bool                   `xml:"codingDependency,attr"`
+	MaximumSAPPeriod          *float64                `xml:"maximumSAPPeriod,attr"`
+	MaxPlayoutRate            *float64                `xml:"maxPlayoutRate,attr"`
+	SelectionPriority         *uint64                 `xml:"selectionPriority,attr"`
+	Tag                       *string                 `xml:"tag,attr"`
+	SegmentProfiles           StringVector            `xml:"segmentProfiles,attr,omitempty"`
+	FramePacking              []Descriptor            `xml:"FramePacking,omitempty"`
+	AudioChannelConfiguration []Descriptor            `xml:"AudioChannelConfiguration,omitempty"`
+	ContentProtections        []drmDescriptorMarshal  `xml:"ContentProtection,omitempty"`
+	EssentialProperty         []Descriptor            `xml:"EssentialProperty,omitempty"`
+	SupplementalProperty      []Descriptor            `xml:"SupplementalProperty,omitempty"`
+	InbandEventStreams        []Descriptor            `xml:"InbandEventStream,omitempty"`
+	Switching                 []Switching             `xml:"Switching,omitempty"`
+	RandomAccess              []RandomAccess          `xml:"RandomAccess,omitempty"`
+	Resync                    []Resync                `xml:"Resync,omitempty"`
+	BaseURL                   []baseURLMarshal        `xml:"BaseURL,omitempty"`
+	SegmentBase               *SegmentBase            `xml:"SegmentBase,omitempty"`
+	SegmentList               *segmentListMarshal     `xml:"SegmentList,omitempty"`
+	SegmentTemplate           *segmentTemplateMarshal `xml:"SegmentTemplate,omitempty"`
+	ExtensionAttrs            []xml.Attr              `xml:",any,attr"`
+	Extensions                []extensionMarshal      `xml:",any"`
 }
 
 // Descriptor represents XSD's DescriptorType.
+type Descriptor struct {
+	SchemeIDURI *string `xml:"schemeIdUri,attr" json:"schemeIDURI,omitempty"`
+	Value       *string `xml:"value,attr" json:"value,omitempty"`
+	ID          *string `xml:"id,attr" json:"id,omitempty"`
+}
+
+// DRMDescriptor represents XSD's DescriptorType of ContentProtection element.
 type DRMDescriptor struct {
-	SchemeIDURI    *string `xml:"schemeIdUri,attr"`
-	Value          *string `xml:"value,attr,omitempty"`
-	CencDefaultKID *string `xml:"default_KID,attr,omitempty"`
-	Cenc           *string `xml:"cenc,attr,omitempty"`
-	Pssh           *Pssh   `xml:"pssh"`
+	SchemeIDURI *string `xml:"schemeIdUri,attr" json:"schemeIDURI,omitempty"`
+	Value       *string `xml:"value,attr,omitempty" json:"value,omitempty"`
+	// Robustness is a DRM specific security level required to play the content, e.g. HW_SECURE_ALL.
+	Robustness *string `xml:"robustness,attr,omitempty" json:"robustness,omitempty"`
+	// RefID identifies ContentProtection referenced by other ones with Ref instead of repeating its content.
+	RefID          *string `xml:"refId,attr,omitempty" json:"refID,omitempty"`
+	Ref            *string `xml:"ref,attr,omitempty" json:"ref,omitempty"`
+	CencDefaultKID *string `xml:"default_KID,attr,omitempty" json:"cencDefaultKID,omitempty"`
+	Cenc           *string `xml:"cenc,attr,omitempty" json:"cenc,omitempty"`
+	MSPR           *string `xml:"mspr,attr,omitempty" json:"mspr,omitempty"`
+	MAS            *string `xml:"mas,attr,omitempty" json:"mas,omitempty"`
+	Pssh           *Pssh   `xml:"pssh" json:"pssh,omitempty"`
+	Pro            *Pro    `xml:"pro" json:"pro,omitempty"`
+	// Laurl is ClearKey license acquisition URL, LegacyLaurl is its older form.
+	Laurl       *Laurl       `xml:"laurl" json:"laurl,omitempty"`
+	LegacyLaurl *LegacyLaurl `xml:"Laurl" json:"legacyLaurl,omitempty"`
+	// AuthzURL is DASH-IF authorization service URL providing tokens for license requests,
+	// AuthzScheme is the authorization scheme used with them.
+	AuthzURL         *DashIFValue      `xml:"authzurl" json:"authzURL,omitempty"`
+	AuthzScheme      *DashIFValue      `xml:"authzscheme" json:"authzScheme,omitempty"`
+	MarlinContentIDs *MarlinContentIDs `xml:"MarlinContentIds" json:"marlinContentIDs,omitempty"`
 }
 
 type drmDescriptorMarshal struct {
-	SchemeIDURI    *string      `xml:"schemeIdUri,attr"`
-	Value          *string      `xml:"value,attr,omitempty"`
-	CencDefaultKID *string      `xml:"cenc:default_KID,attr,omitempty"`
-	Cenc           *string      `xml:"xmlns:cenc,attr,omitempty"`
-	Pssh           *psshMarshal `xml:"cenc:pssh"`
+	SchemeIDURI      *string                  `xml:"schemeIdUri,attr"`
+	Value            *string                  `xml:"value,attr,omitempty"`
+	Robustness       *string                  `xml:"robustness,attr,omitempty"`
+	RefID            *string                  `xml:"refId,attr,omitempty"`
+	Ref              *string                  `xml:"ref,attr,omitempty"`
+	CencDefaultKID   *string                  `xml:"cenc:default_KID,attr,omitempty"`
+	Cenc             *string                  `xml:"xmlns:cenc,attr,omitempty"`
+	MSPR             *string                  `xml:"xmlns:mspr,attr,omitempty"`
+	MAS              *string                  `xml:"xmlns:mas,attr,omitempty"`
+	Pssh             *psshMarshal             `xml:"cenc:pssh"`
+	Pro              *proMarshal              `xml:"mspr:pro"`
+	Laurl            *laurlMarshal            `xml:"dashif:laurl"`
+	LegacyLaurl      *legacyLaurlMarshal      `xml:"clearkey:Laurl"`
+	AuthzURL         *dashIFValueMarshal      `xml:"dashif:authzurl"`
+	AuthzScheme      *dashIFValueMarshal      `xml:"dashif:authzscheme"`
+	MarlinContentIDs *marlinContentIDsMarshal `xml:"mas:MarlinContentIds"`
 }
 
 // Pssh represents XSD's CencPsshType .
 type Pssh struct {
-	Cenc  *string `xml:"cenc,attr"`
-	Value *string `xml:",chardata"`
+	Cenc  *string `xml:"cenc,attr" json:"cenc,omitempty"`
+	Value *string `xml:",chardata" json:"value,omitempty"`
 }
 
 type psshMarshal struct {
@@ -245,56 +1109,533 @@ type psshMarshal struct {
 	Value *string `xml:",chardata"`
 }
 
+// MSPRNamespace is a namespace of PlayReady elements in ContentProtection.
+const MSPRNamespace = "urn:microsoft:playready"
+
+// Pro represents PlayReady mspr:pro element: base64 encoded PlayReady Object.
+type Pro struct {
+	MSPR  *string `xml:"mspr,attr" json:"mspr,omitempty"`
+	Value *string `xml:",chardata" json:"value,omitempty"`
+}
+
+type proMarshal struct {
+	MSPR  *string `xml:"xmlns:mspr,attr"`
+	Value *string `xml:",chardata"`
+}
+
+// Namespaces of ClearKey license acquisition URL elements in ContentProtection.
+const (
+	DashIFCPSNamespace = "https://dashif.org/CPS"
+	ClearKeyNamespace  = "http://dashif.org/guidelines/clearKey"
+)
+
+// Laurl represents DASH-IF dashif:laurl element: ClearKey license acquisition URL.
+type Laurl struct {
+	DashIF      *string `xml:"dashif,attr" json:"dashIF,omitempty"`
+	LicenseType *string `xml:"licenseType,attr" json:"licenseType,omitempty"`
+	Value       *string `xml:",chardata" json:"value,omitempty"`
+}
+
+type laurlMarshal struct {
+	DashIF      *string `xml:"xmlns:dashif,attr"`
+	LicenseType *string `xml:"licenseType,attr"`
+	Value       *string `xml:",chardata"`
+}
+
+// LegacyLaurl represents clearkey:Laurl element used before dashif:laurl was defined.
+type LegacyLaurl struct {
+	ClearKey *string `xml:"clearkey,attr" json:"clearKey,omitempty"`
+	LicType  *string `xml:"Lic_type,attr" json:"licType,omitempty"`
+	Value    *string `xml:",chardata" json:"value,omitempty"`
+}
+
+type legacyLaurlMarshal struct {
+	ClearKey *string `xml:"xmlns:clearkey,attr"`
+	LicType  *string `xml:"Lic_type,attr"`
+	Value    *string `xml:",chardata"`
+}
+
+// DashIFValue represents DASH-IF element with text content, e.g. dashif:authzurl.
+type DashIFValue struct {
+	DashIF *string `xml:"dashif,attr" json:"dashIF,omitempty"`
+	Value  *string `xml:",chardata" json:"value,omitempty"`
+}
+
+type dashIFValueMarshal struct {
+	DashIF *string `xml:"xmlns:dashif,attr"`
+	Value  *string `xml:",chardata"`
+}
+
+// MASNamespace is a namespace of Marlin elements in ContentProtection.
+const MASNamespace = "urn:marlin:mas:1-0:services:schemas:mpd"
+
+// MarlinContentIDs represents Marlin mas:MarlinContentIds element.
+type MarlinContentIDs struct {
+	ContentIDs []string `xml:"MarlinContentId" json:"contentIDs,omitempty"`
+}
+
+type marlinContentIDsMarshal struct {
+	ContentIDs []string `xml:"mas:MarlinContentId"`
+}
+
 // SegmentTemplate represents XSD's SegmentTemplateType.
 type SegmentTemplate struct {
-	Timescale              *uint64            `xml:"timescale,attr"`
-	Media                  *string            `xml:"media,attr"`
-	Initialization         *string            `xml:"initialization,attr"`
-	StartNumber            *uint64            `xml:"startNumber,attr"`
-	PresentationTimeOffset *uint64            `xml:"presentationTimeOffset,attr"`
-	SegmentTimelineS       []SegmentTimelineS `xml:"SegmentTimeline>S,omitempty"`
+	Timescale                *uint64                 `xml:"timescale,attr" json:"timescale,omitempty"`
+	Duration                 *uint64                 `xml:"duration,attr" json:"duration,omitempty"`
+	Media                    *string                 `xml:"media,attr" json:"media,omitempty"`
+	Index                    *string                 `xml:"index,attr" json:"index,omitempty"`
+	Initialization           *string                 `xml:"initialization,attr" json:"initialization,omitempty"`
+	BitstreamSwitching       *string                 `xml:"bitstreamSwitching,attr" json:"bitstreamSwitching,omitempty"`
+	StartNumber              *uint64                 `xml:"startNumber,attr" json:"startNumber,omitempty"`
+	EndNumber                *uint64                 `xml:"endNumber,attr" json:"endNumber,omitempty"`
+	PresentationTimeOffset   *uint64                 `xml:"presentationTimeOffset,attr" json:"presentationTimeOffset,omitempty"`
+	AvailabilityTimeOffset   *AvailabilityTimeOffset `xml:"availabilityTimeOffset,attr" json:"availabilityTimeOffset,omitempty"`
+	AvailabilityTimeComplete *bool                   `xml:"availabilityTimeComplete,attr" json:"availabilityTimeComplete,omitempty"`
+	// InitializationURL is Initialization element, not to be confused with initialization attribute.
+	InitializationURL   *URLType           `xml:"Initialization,omitempty" json:"initializationURL,omitempty"`
+	RepresentationIndex *URLType           `xml:"RepresentationIndex,omitempty" json:"representationIndex,omitempty"`
+	SegmentTimelineS    []SegmentTimelineS `xml:"SegmentTimeline>S,omitempty" json:"segmentTimelineS,omitempty"`
+}
+
+// segmentTemplateMarshal omits empty SegmentTimeline element, see segmentListMarshal.
+type segmentTemplateMarshal struct {
+	Timescale                *uint64                 `xml:"timescale,attr"`
+	Duration                 *uint64                 `xml:"duration,attr"`
+	Media                    *string                 `xml:"media,attr"`
+	Index                    *string                 `xml:"index,attr"`
+	Initialization           *string                 `xml:"initialization,attr"`
+	BitstreamSwitching       *string                 `xml:"bitstreamSwitching,attr"`
+	StartNumber              *uint64                 `xml:"startNumber,attr"`
+	EndNumber                *uint64                 `xml:"endNumber,attr"`
+	PresentationTimeOffset   *uint64                 `xml:"presentationTimeOffset,attr"`
+	AvailabilityTimeOffset   *AvailabilityTimeOffset `xml:"availabilityTimeOffset,attr"`
+	AvailabilityTimeComplete *bool                   `xml:"availabilityTimeComplete,attr"`
+	InitializationURL        *URLType                `xml:"Initialization,omitempty"`
+	RepresentationIndex      *URLType                `xml:"RepresentationIndex,omitempty"`
+	SegmentTimeline          *segmentTimelineMarshal `xml:"SegmentTimeline,omitempty"`
+}
+
+// SegmentBase represents XSD's SegmentBaseType.
+type SegmentBase struct {
+	Timescale                *uint64                 `xml:"timescale,attr" json:"timescale,omitempty"`
+	PresentationTimeOffset   *uint64                 `xml:"presentationTimeOffset,attr" json:"presentationTimeOffset,omitempty"`
+	IndexRange               *string                 `xml:"indexRange,attr" json:"indexRange,omitempty"`
+	IndexRangeExact          *bool                   `xml:"indexRangeExact,attr" json:"indexRangeExact,omitempty"`
+	AvailabilityTimeOffset   *AvailabilityTimeOffset `xml:"availabilityTimeOffset,attr" json:"availabilityTimeOffset,omitempty"`
+	AvailabilityTimeComplete *bool                   `xml:"availabilityTimeComplete,attr" json:"availabilityTimeComplete,omitempty"`
+	Initialization           *URLType                `xml:"Initialization,omitempty" json:"initialization,omitempty"`
+	RepresentationIndex      *URLType                `xml:"RepresentationIndex,omitempty" json:"representationIndex,omitempty"`
+}
+
+// SegmentList represents XSD's SegmentListType.
+type SegmentList struct {
+	Timescale                *uint64                 `xml:"timescale,attr" json:"timescale,omitempty"`
+	Duration                 *uint64                 `xml:"duration,attr" json:"duration,omitempty"`
+	StartNumber              *uint64                 `xml:"startNumber,attr" json:"startNumber,omitempty"`
+	PresentationTimeOffset   *uint64                 `xml:"presentationTimeOffset,attr" json:"presentationTimeOffset,omitempty"`
+	AvailabilityTimeOffset   *AvailabilityTimeOffset `xml:"availabilityTimeOffset,attr" json:"availabilityTimeOffset,omitempty"`
+	AvailabilityTimeComplete *bool                   `xml:"availabilityTimeComplete,attr" json:"availabilityTimeComplete,omitempty"`
+	Initialization           *URLType                `xml:"Initialization,omitempty" json:"initialization,omitempty"`
+	RepresentationIndex      *URLType                `xml:"RepresentationIndex,omitempty" json:"representationIndex,omitempty"`
+	SegmentTimelineS         []SegmentTimelineS      `xml:"SegmentTimeline>S,omitempty" json:"segmentTimelineS,omitempty"`
+	SegmentURLs              []SegmentListURL        `xml:"SegmentURL,omitempty" json:"segmentURLs,omitempty"`
+}
+
+// segmentListMarshal omits empty SegmentTimeline element: encoding/xml writes parent element of
+// "SegmentTimeline>S" even for empty slice.
+type segmentListMarshal struct {
+	Timescale                *uint64                 `xml:"timescale,attr"`
+	Duration                 *uint64                 `xml:"duration,attr"`
+	StartNumber              *uint64                 `xml:"startNumber,attr"`
+	PresentationTimeOffset   *uint64                 `xml:"presentationTimeOffset,attr"`
+	AvailabilityTimeOffset   *AvailabilityTimeOffset `xml:"availabilityTimeOffset,attr"`
+	AvailabilityTimeComplete *bool                   `xml:"availabilityTimeComplete,attr"`
+	Initialization           *URLType                `xml:"Initialization,omitempty"`
+	RepresentationIndex      *URLType                `xml:"RepresentationIndex,omitempty"`
+	SegmentTimeline          *segmentTimelineMarshal `xml:"SegmentTimeline,omitempty"`
+	SegmentURLs              []SegmentListURL        `xml:"SegmentURL,omitempty"`
+}
+
+type segmentTimelineMarshal struct {
+	S []SegmentTimelineS `xml:"S"`
+}
+
+// URLType represents XSD's URLType.
+type URLType struct {
+	SourceURL *string `xml:"sourceURL,attr" json:"sourceURL,omitempty"`
+	Range     *string `xml:"range,attr" json:"range,omitempty"`
+}
+
+// SegmentListURL represents XSD's SegmentURLType.
+type SegmentListURL struct {
+	Media      *string `xml:"media,attr" json:"media,omitempty"`
+	MediaRange *string `xml:"mediaRange,attr" json:"mediaRange,omitempty"`
+	Index      *string `xml:"index,attr" json:"index,omitempty"`
+	IndexRange *string `xml:"indexRange,attr" json:"indexRange,omitempty"`
 }
 
 // SegmentTimelineS represents XSD's SegmentTimelineType's inner S elements.
 type SegmentTimelineS struct {
-	T *uint64 `xml:"t,attr"`
-	D uint64  `xml:"d,attr"`
-	R *int64  `xml:"r,attr"`
+	T *uint64 `xml:"t,attr" json:"t,omitempty"`
+	// N is a number of the first segment, numbering continues from the previous S if not set.
+	N *uint64 `xml:"n,attr" json:"n,omitempty"`
+	D uint64  `xml:"d,attr" json:"d,omitempty"`
+	R *int64  `xml:"r,attr" json:"r,omitempty"`
 }
 
 // modifyMPD generates true xml struct for MPD .
 func modifyMPD(mpd *MPD) *mpdMarshal {
-	return &mpdMarshal{
+	ns := mpd.declaredNamespaces()
+	// xlink attributes are written with prefix, so namespace must be declared
+	needsXLink := mpd.XLink == nil && mpd.usesXLink()
+	if needsXLink {
+		ns.add(XLinkNamespace, "xlink")
+	}
+	// SCTE-35 elements are written with scte35 prefix too
+	needsSCTE35 := mpd.SCTE35 == nil && mpd.usesSCTE35()
+	if needsSCTE35 {
+		ns.add(SCTE35Namespace, "scte35")
+	}
+	res := &mpdMarshal{
 		XMLNS:                      copyobj.String(mpd.XMLNS),
 		MinimumUpdatePeriod:        copyobj.String(mpd.MinimumUpdatePeriod),
-		AvailabilityStartTime:      copyobj.String(mpd.AvailabilityStartTime),
+		AvailabilityStartTime:      copyDateTime(mpd.AvailabilityStartTime),
+		AvailabilityEndTime:        copyDateTime(mpd.AvailabilityEndTime),
 		MediaPresentationDuration:  copyobj.String(mpd.MediaPresentationDuration),
 		MinBufferTime:              copyobj.String(mpd.MinBufferTime),
 		SuggestedPresentationDelay: copyobj.String(mpd.SuggestedPresentationDelay),
 		TimeShiftBufferDepth:       copyobj.String(mpd.TimeShiftBufferDepth),
-		PublishTime:                copyobj.String(mpd.PublishTime),
-		Type:                       copyobj.String(mpd.Type),
+		PublishTime:                copyDateTime(mpd.PublishTime),
+		Type:                       copyPresentationType(mpd.Type),
 		Profiles:                   mpd.Profiles,
 		XSI:                        copyobj.String(mpd.XSI),
 		SCTE35:                     copyobj.String(mpd.SCTE35),
+		DVB:                        copyobj.String(mpd.DVB),
+		XLink:                      copyobj.String(mpd.XLink),
 		XSISchemaLocation:          copyobj.String(mpd.XSISchemaLocation),
 		ID:                         copyobj.String(mpd.ID),
-		BaseURL:                    copyobj.String(mpd.BaseURL),
-		Period:                     modifyPeriod(mpd.Period),
+		ProgramInformation:         copyProgramInformation(mpd.ProgramInformation),
+		BaseURL:                    copyBaseURLs(mpd.BaseURL),
+		Location:                   copyobj.Strings(mpd.Location),
+		PatchLocation:              copyPatchLocation(mpd.PatchLocation),
+		ServiceDescription:         copyServiceDescriptions(mpd.ServiceDescription),
+		InitializationSet:          modifyInitializationSets(mpd.InitializationSet),
+		InitializationGroup:        copyUIntVWithIDs(mpd.InitializationGroup),
+		InitializationPresentation: copyUIntVWithIDs(mpd.InitializationPresentation),
+		ContentSteering:            copyContentSteering(mpd.ContentSteering),
+		Period:                     modifyPeriod(mpd.Period, ns),
+		Metrics:                    modifyMetrics(mpd.Metrics),
+		SupplementalProperty:       copyDescriptors(mpd.SupplementalProperty),
+		UTCTiming:                  copyDescriptors(mpd.UTCTiming),
+		ExtensionAttrs:             modifyAttrs(mpd.ExtensionAttrs, ns),
+		Extensions:                 modifyExtensions(mpd.Extensions, ns),
+	}
+	if needsXLink {
+		xlink := XLinkNamespace
+		res.XLink = &xlink
+	}
+	if needsSCTE35 {
+		scte35 := SCTE35Namespace
+		res.SCTE35 = &scte35
+	}
+	return res
+}
+
+// usesXLink returns true if any element of MPD has xlink attributes.
+func (m *MPD) usesXLink() bool {
+	for _, is := range m.InitializationSet {
+		if is.XLinkHref != nil || is.XLinkActuate != nil {
+			return true
+		}
+	}
+	for _, p := range m.Period {
+		if p.XLinkHref != nil || p.XLinkActuate != nil {
+			return true
+		}
+		for _, es := range p.EventStreams {
+			if es.XLinkHref != nil || es.XLinkActuate != nil {
+				return true
+			}
+		}
+		for _, as := range p.AdaptationSets {
+			if as != nil && (as.XLinkHref != nil || as.XLinkActuate != nil) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func copyProgramInformation(pis []ProgramInformation) []ProgramInformation {
+	if pis == nil {
+		return nil
+	}
+	res := make([]ProgramInformation, 0, len(pis))
+	for _, pi := range pis {
+		res = append(res, ProgramInformation{
+			Lang:               copyobj.String(pi.Lang),
+			MoreInformationURL: copyobj.String(pi.MoreInformationURL),
+			Title:              copyobj.String(pi.Title),
+			Source:             copyobj.String(pi.Source),
+			Copyright:          copyobj.String(pi.Copyright),
+		})
+	}
+	return res
+}
+
+func copyContentSteering(cs *ContentSteering) *ContentSteering {
+	if cs == nil {
+		return nil
+	}
+	return &ContentSteering{
+		Value:                  cs.Value,
+		DefaultServiceLocation: copyobj.String(cs.DefaultServiceLocation),
+		QueryBeforeStart:       copyobj.Bool(cs.QueryBeforeStart),
+		ClientRequirement:      copyobj.Bool(cs.ClientRequirement),
+		ProxyServerURL:         copyobj.String(cs.ProxyServerURL),
+	}
+}
+
+func modifyInitializationSets(iss []InitializationSet) []initializationSetMarshal {
+	if iss == nil {
+		return nil
+	}
+	res := make([]initializationSetMarshal, 0, len(iss))
+	for _, is := range iss {
+		res = append(res, initializationSetMarshal{
+			XLinkHref:            copyobj.String(is.XLinkHref),
+			XLinkActuate:         copyobj.String(is.XLinkActuate),
+			ID:                   copyobj.UInt64(is.ID),
+			InAllPeriods:         copyobj.Bool(is.InAllPeriods),
+			ContentType:          copyobj.String(is.ContentType),
+			Par:                  copyRatio(is.Par),
+			MaxWidth:             copyobj.UInt64(is.MaxWidth),
+			MaxHeight:            copyobj.UInt64(is.MaxHeight),
+			MaxFrameRate:         copyFrameRate(is.MaxFrameRate),
+			Initialization:       copyobj.String(is.Initialization),
+			Profiles:             copyobj.String(is.Profiles),
+			MimeType:             copyobj.String(is.MimeType),
+			Codecs:               copyobj.String(is.Codecs),
+			EssentialProperty:    copyDescriptors(is.EssentialProperty),
+			SupplementalProperty: copyDescriptors(is.SupplementalProperty),
+			Accessibility:        copyDescriptors(is.Accessibility),
+			Role:                 copyDescriptors(is.Role),
+			Rating:               copyDescriptors(is.Rating),
+			Viewpoint:            copyDescriptors(is.Viewpoint),
+		})
+	}
+	return res
+}
+
+func copyUIntVWithIDs(us []UIntVWithID) []UIntVWithID {
+	if us == nil {
+		return nil
+	}
+	res := make([]UIntVWithID, 0, len(us))
+	for _, u := range us {
+		res = append(res, UIntVWithID{
+			Value:       u.Value,
+			ID:          copyobj.UInt64(u.ID),
+			Profiles:    copyobj.String(u.Profiles),
+			ContentType: copyobj.String(u.ContentType),
+		})
+	}
+	return res
+}
+
+func copySwitchings(ss []Switching) []Switching {
+	if ss == nil {
+		return nil
+	}
+	res := make([]Switching, 0, len(ss))
+	for _, s := range ss {
+		res = append(res, Switching{
+			Interval: copyobj.UInt64(s.Interval),
+			Type:     copyobj.String(s.Type),
+		})
+	}
+	return res
+}
+
+func copyRandomAccesses(ras []RandomAccess) []RandomAccess {
+	if ras == nil {
+		return nil
+	}
+	res := make([]RandomAccess, 0, len(ras))
+	for _, ra := range ras {
+		res = append(res, RandomAccess{
+			Interval:      copyobj.UInt64(ra.Interval),
+			Type:          copyobj.String(ra.Type),
+			MinBufferTime: copyobj.String(ra.MinBufferTime),
+			Bandwidth:     copyobj.UInt64(ra.Bandwidth),
+		})
+	}
+	return res
+}
+
+func copyResyncs(rs []Resync) []Resync {
+	if rs == nil {
+		return nil
+	}
+	res := make([]Resync, 0, len(rs))
+	for _, r := range rs {
+		res = append(res, Resync{
+			Type:   copyobj.UInt64(r.Type),
+			DT:     copyobj.UInt64(r.DT),
+			DIMax:  copyobj.Float64(r.DIMax),
+			DIMin:  copyobj.Float64(r.DIMin),
+			Marker: copyobj.Bool(r.Marker),
+		})
+	}
+	return res
+}
+
+func copyServiceDescriptions(sds []ServiceDescription) []ServiceDescription {
+	if sds == nil {
+		return nil
+	}
+	res := make([]ServiceDescription, 0, len(sds))
+	for _, sd := range sds {
+		serviceDescription := ServiceDescription{
+			ID:    copyobj.UInt64(sd.ID),
+			Scope: copyDescriptors(sd.Scope),
+		}
+		if sd.Latency != nil {
+			serviceDescription.Latency = &Latency{
+				ReferenceID: copyobj.UInt64(sd.Latency.ReferenceID),
+				Target:      copyobj.UInt64(sd.Latency.Target),
+				Max:         copyobj.UInt64(sd.Latency.Max),
+				Min:         copyobj.UInt64(sd.Latency.Min),
+			}
+		}
+		if sd.PlaybackRate != nil {
+			serviceDescription.PlaybackRate = &PlaybackRate{
+				Max: copyobj.Float64(sd.PlaybackRate.Max),
+				Min: copyobj.Float64(sd.PlaybackRate.Min),
+			}
+		}
+		if sd.OperatingQuality != nil {
+			serviceDescription.OperatingQuality = make([]OperatingQuality, 0, len(sd.OperatingQuality))
+			for _, oq := range sd.OperatingQuality {
+				serviceDescription.OperatingQuality = append(serviceDescription.OperatingQuality, OperatingQuality{
+					MediaType:     copyobj.String(oq.MediaType),
+					Min:           copyobj.UInt64(oq.Min),
+					Max:           copyobj.UInt64(oq.Max),
+					Target:        copyobj.UInt64(oq.Target),
+					Type:          copyobj.String(oq.Type),
+					MaxDifference: copyobj.UInt64(oq.MaxDifference),
+				})
+			}
+		}
+		if sd.OperatingBandwidth != nil {
+			serviceDescription.OperatingBandwidth = make([]OperatingBandwidth, 0, len(sd.OperatingBandwidth))
+			for _, ob := range sd.OperatingBandwidth {
+				serviceDescription.OperatingBandwidth = append(serviceDescription.OperatingBandwidth, OperatingBandwidth{
+					MediaType: copyobj.String(ob.MediaType),
+					Min:       copyobj.UInt64(ob.Min),
+					Max:       copyobj.UInt64(ob.Max),
+					Target:    copyobj.UInt64(ob.Target),
+				})
+			}
+		}
+		res = append(res, serviceDescription)
+	}
+	return res
+}
+
+func copyBaseURLs(bs []BaseURL) []baseURLMarshal {
+	if bs == nil {
+		return nil
+	}
+	res := make([]baseURLMarshal, 0, len(bs))
+	for _, b := range bs {
+		res = append(res, baseURLMarshal{
+			Value:                    b.Value,
+			ServiceLocation:          copyobj.String(b.ServiceLocation),
+			ByteRange:                copyobj.String(b.ByteRange),
+			AvailabilityTimeOffset:   copyAvailabilityTimeOffset(b.AvailabilityTimeOffset),
+			AvailabilityTimeComplete: copyobj.Bool(b.AvailabilityTimeComplete),
+			DVBPriority:              copyobj.UInt64(b.DVBPriority),
+			DVBWeight:                copyobj.UInt64(b.DVBWeight),
+		})
+	}
+	return res
+}
+
+func copyPatchLocation(pls []PatchLocation) []PatchLocation {
+	if pls == nil {
+		return nil
+	}
+	res := make([]PatchLocation, 0, len(pls))
+	for _, pl := range pls {
+		patchLocation := PatchLocation{Value: pl.Value}
+		if pl.TTL != nil {
+			ttl := *pl.TTL
+			patchLocation.TTL = &ttl
+		}
+		res = append(res, patchLocation)
+	}
+	return res
+}
+
+func modifyMetrics(ms []Metrics) []metricsMarshal {
+	if ms == nil {
+		return nil
 	}
+	res := make([]metricsMarshal, 0, len(ms))
+	for _, m := range ms {
+		metrics := metricsMarshal{
+			Metrics: m.Metrics,
+		}
+		if m.Reporting != nil {
+			metrics.Reporting = make([]reportingMarshal, 0, len(m.Reporting))
+			for _, r := range m.Reporting {
+				metrics.Reporting = append(metrics.Reporting, reportingMarshal{
+					SchemeIDURI:     copyobj.String(r.SchemeIDURI),
+					Value:           copyobj.String(r.Value),
+					ID:              copyobj.String(r.ID),
+					DVBReportingURL: copyobj.String(r.DVBReportingURL),
+					DVBProbability:  copyobj.UInt64(r.DVBProbability),
+				})
+			}
+		}
+		if m.Range != nil {
+			metrics.Range = make([]MetricsRange, 0, len(m.Range))
+			for _, r := range m.Range {
+				metrics.Range = append(metrics.Range, MetricsRange{
+					Starttime: copyobj.String(r.Starttime),
+					Duration:  copyobj.String(r.Duration),
+				})
+			}
+		}
+		res = append(res, metrics)
+	}
+	return res
 }
 
-func modifyPeriod(ps []Period) []periodMarshal {
+func modifyPeriod(ps []Period, ns namespaces) []periodMarshal {
 	if ps == nil {
 		return nil
 	}
 	pms := make([]periodMarshal, 0, len(ps))
 	for _, p := range ps {
 		period := periodMarshal{
-			Duration:       copyobj.String(p.Duration),
-			ID:             copyobj.String(p.ID),
-			Start:          copyobj.String(p.Start),
-			AdaptationSets: modifyAdaptationSets(p.AdaptationSets),
+			Duration:        copyobj.String(p.Duration),
+			XLinkHref:       copyobj.String(p.XLinkHref),
+			XLinkActuate:    copyobj.String(p.XLinkActuate),
+			ID:              copyobj.String(p.ID),
+			Start:           copyobj.String(p.Start),
+			BaseURL:         copyBaseURLs(p.BaseURL),
+			SegmentBase:     copySegmentBase(p.SegmentBase),
+			SegmentList:     modifySegmentList(p.SegmentList),
+			SegmentTemplate: modifySegmentTemplate(p.SegmentTemplate),
+			AssetIdentifier: copyDescriptor(p.AssetIdentifier),
+			EventStreams:    modifyEventStreams(p.EventStreams, ns),
+			AdaptationSets:  modifyAdaptationSets(p.AdaptationSets, ns.with(p.ExtensionAttrs)),
+			Subsets:         copySubsets(p.Subsets),
+			Preselections:   copyPreselections(p.Preselections),
+			ExtensionAttrs:  modifyAttrs(p.ExtensionAttrs, ns),
+			Extensions:      modifyExtensions(p.Extensions, ns.with(p.ExtensionAttrs)),
 		}
 		pms = append(pms, period)
 	}
@@ -302,69 +1643,354 @@ func modifyPeriod(ps []Period) []periodMarshal {
 	return pms
 }
 
-func modifyAdaptationSets(as []*AdaptationSet) []*adaptationSetMarshal {
+func modifyEventStreams(ess []EventStream, ns namespaces) []eventStreamMarshal {
+	if ess == nil {
+		return nil
+	}
+	res := make([]eventStreamMarshal, 0, len(ess))
+	for _, es := range ess {
+		eventStream := eventStreamMarshal{
+			SchemeIDURI:            copyobj.String(es.SchemeIDURI),
+			Value:                  copyobj.String(es.Value),
+			Timescale:              copyobj.UInt64(es.Timescale),
+			PresentationTimeOffset: copyobj.UInt64(es.PresentationTimeOffset),
+			XLinkHref:              copyobj.String(es.XLinkHref),
+			XLinkActuate:           copyobj.String(es.XLinkActuate),
+		}
+		if es.Events != nil {
+			eventStream.Events = make([]eventMarshal, 0, len(es.Events))
+			for _, e := range es.Events {
+				event := eventMarshal{
+					PresentationTime:  copyobj.UInt64(e.PresentationTime),
+					Duration:          copyobj.UInt64(e.Duration),
+					ID:                copyobj.UInt64(e.ID),
+					ContentEncoding:   copyobj.String(e.ContentEncoding),
+					MessageData:       copyobj.String(e.MessageData),
+					Signal:            modifySCTE35Signal(e.Signal, ns),
+					SpliceInfoSection: modifySCTE35SpliceInfoSection(e.SpliceInfoSection, ns),
+				}
+				if e.Signal == nil && e.SpliceInfoSection == nil {
+					event.Payload = e.Payload
+				}
+				eventStream.Events = append(eventStream.Events, event)
+			}
+		}
+		res = append(res, eventStream)
+	}
+	return res
+}
+
+func copySubsets(ss []Subset) []Subset {
+	if ss == nil {
+		return nil
+	}
+	res := make([]Subset, 0, len(ss))
+	for _, s := range ss {
+		res = append(res, Subset{
+			Contains: UIntVector(copyobj.UInt64s(s.Contains)),
+			ID:       copyobj.String(s.ID),
+		})
+	}
+	return res
+}
+
+func copyPreselections(ps []Preselection) []Preselection {
+	if ps == nil {
+		return nil
+	}
+	res := make([]Preselection, 0, len(ps))
+	for _, p := range ps {
+		res = append(res, Preselection{
+			ID:                        copyobj.String(p.ID),
+			PreselectionComponents:    StringVector(copyobj.Strings(p.PreselectionComponents)),
+			Lang:                      copyobj.String(p.Lang),
+			Order:                     copyobj.String(p.Order),
+			Codecs:                    copyobj.String(p.Codecs),
+			AudioSamplingRate:         copyAudioSamplingRate(p.AudioSamplingRate),
+			AudioChannelConfiguration: copyDescriptors(p.AudioChannelConfiguration),
+			EssentialProperty:         copyDescriptors(p.EssentialProperty),
+			SupplementalProperty:      copyDescriptors(p.SupplementalProperty),
+			Accessibility:             copyDescriptors(p.Accessibility),
+			Role:                      copyDescriptors(p.Role),
+			Rating:                    copyDescriptors(p.Rating),
+			Viewpoint:                 copyDescriptors(p.Viewpoint),
+		})
+	}
+	return res
+}
+
+func modifyAdaptationSets(as []*AdaptationSet, ns namespaces) []*adaptationSetMarshal {
 	if as == nil {
 		return nil
 	}
 	asm := make([]*adaptationSetMarshal, 0, len(as))
 	for _, a := range as {
 		adaptationSet := &adaptationSetMarshal{
-			BitstreamSwitching:      copyobj.Bool(a.BitstreamSwitching),
-			Codecs:                  copyobj.String(a.Codecs),
-			Lang:                    copyobj.String(a.Lang),
-			MimeType:                a.MimeType,
-			SegmentAlignment:        a.SegmentAlignment,
-			StartWithSAP:            copyobj.UInt64(a.StartWithSAP),
-			SubsegmentAlignment:     a.SubsegmentAlignment,
-			SubsegmentStartsWithSAP: copyobj.UInt64(a.SubsegmentStartsWithSAP),
-			Representations:         modifyRepresentations(a.Representations),
-			ContentProtections:      modifyContentProtections(a.ContentProtections),
+			ID:                        copyobj.String(a.ID),
+			BitstreamSwitching:        copyobj.Bool(a.BitstreamSwitching),
+			Codecs:                    copyobj.String(a.Codecs),
+			Lang:                      copyobj.String(a.Lang),
+			ContentType:               copyobj.String(a.ContentType),
+			Group:                     copyobj.UInt64(a.Group),
+			MinBandwidth:              copyobj.UInt64(a.MinBandwidth),
+			MaxBandwidth:              copyobj.UInt64(a.MaxBandwidth),
+			MinWidth:                  copyobj.UInt64(a.MinWidth),
+			MaxWidth:                  copyobj.UInt64(a.MaxWidth),
+			MinHeight:                 copyobj.UInt64(a.MinHeight),
+			MaxHeight:                 copyobj.UInt64(a.MaxHeight),
+			MinFrameRate:              copyFrameRate(a.MinFrameRate),
+			MaxFrameRate:              copyFrameRate(a.MaxFrameRate),
+			Profiles:                  copyobj.String(a.Profiles),
+			ScanType:                  copyobj.String(a.ScanType),
+			CodingDependency:          copyobj.Bool(a.CodingDependency),
+			MaximumSAPPeriod:          copyobj.Float64(a.MaximumSAPPeriod),
+			MaxPlayoutRate:            copyobj.Float64(a.MaxPlayoutRate),
+			SelectionPriority:         copyobj.UInt64(a.SelectionPriority),
+			Tag:                       copyobj.String(a.Tag),
+			SegmentProfiles:           StringVector(copyobj.Strings(a.SegmentProfiles)),
+			InitializationSetRef:      UIntVector(copyobj.UInt64s(a.InitializationSetRef)),
+			XLinkHref:                 copyobj.String(a.XLinkHref),
+			XLinkActuate:              copyobj.String(a.XLinkActuate),
+			MimeType:                  a.MimeType,
+			SegmentAlignment:          a.SegmentAlignment,
+			StartWithSAP:              copyobj.UInt64(a.StartWithSAP),
+			SubsegmentAlignment:       a.SubsegmentAlignment,
+			SubsegmentStartsWithSAP:   copyobj.UInt64(a.SubsegmentStartsWithSAP),
+			FramePacking:              copyDescriptors(a.FramePacking),
+			AudioChannelConfiguration: copyDescriptors(a.AudioChannelConfiguration),
+			InbandEventStreams:        copyDescriptors(a.InbandEventStreams),
+			Switching:                 copySwitchings(a.Switching),
+			RandomAccess:              copyRandomAccesses(a.RandomAccess),
+			Resync:                    copyResyncs(a.Resync),
+			Accessibility:             copyDescriptors(a.Accessibility),
+			Role:                      copyDescriptors(a.Role),
+			Rating:                    copyDescriptors(a.Rating),
+			BaseURL:                   copyBaseURLs(a.BaseURL),
+			SegmentBase:               copySegmentBase(a.SegmentBase),
+			SegmentList:               modifySegmentList(a.SegmentList),
+			SegmentTemplate:           modifySegmentTemplate(a.SegmentTemplate),
+			ContentComponents:         copyContentComponents(a.ContentComponents),
+			Representations:           modifyRepresentations(a.Representations, ns.with(a.ExtensionAttrs)),
+			ExtensionAttrs:            modifyAttrs(a.ExtensionAttrs, ns),
+			Extensions:                modifyExtensions(a.Extensions, ns.with(a.ExtensionAttrs)),
+			ContentProtections:        modifyContentProtections(a.ContentProtections, ns.with(a.ExtensionAttrs)),
+			EssentialProperty:         copyDescriptors(a.EssentialProperty),
+			SupplementalProperty:      copyDescriptors(a.SupplementalProperty),
 		}
 		asm = append(asm, adaptationSet)
 	}
 	return asm
 }
 
-func modifyRepresentations(rs []Representation) []representationMarshal {
+func copyContentComponents(ccs []ContentComponent) []ContentComponent {
+	if ccs == nil {
+		return nil
+	}
+	res := make([]ContentComponent, 0, len(ccs))
+	for _, cc := range ccs {
+		res = append(res, ContentComponent{
+			ID:            copyobj.String(cc.ID),
+			Lang:          copyobj.String(cc.Lang),
+			ContentType:   copyobj.String(cc.ContentType),
+			Par:           copyRatio(cc.Par),
+			Accessibility: copyDescriptors(cc.Accessibility),
+			Role:          copyDescriptors(cc.Role),
+			Rating:        copyDescriptors(cc.Rating),
+			Viewpoint:     copyDescriptors(cc.Viewpoint),
+		})
+	}
+	return res
+}
+
+func modifyRepresentations(rs []Representation, ns namespaces) []representationMarshal {
 	rsm := make([]representationMarshal, 0, len(rs))
 	for _, r := range rs {
 		representation := representationMarshal{
-			AudioSamplingRate:  copyobj.String(r.AudioSamplingRate),
-			Bandwidth:          copyobj.UInt64(r.Bandwidth),
-			Codecs:             copyobj.String(r.Codecs),
-			FrameRate:          copyobj.String(r.FrameRate),
-			Height:             copyobj.UInt64(r.Height),
-			ID:                 copyobj.String(r.ID),
-			Width:              copyobj.UInt64(r.Width),
-			SegmentTemplate:    copySegmentTemplate(r.SegmentTemplate),
-			SAR:                copyobj.String(r.SAR),
-			ContentProtections: modifyContentProtections(r.ContentProtections),
-			BaseURL:            copyobj.String(r.BaseURL),
+			AudioSamplingRate:         copyAudioSamplingRate(r.AudioSamplingRate),
+			Bandwidth:                 copyobj.UInt64(r.Bandwidth),
+			Codecs:                    copyobj.String(r.Codecs),
+			QualityRanking:            copyobj.UInt64(r.QualityRanking),
+			DependencyID:              StringVector(copyobj.Strings(r.DependencyID)),
+			AssociationID:             StringVector(copyobj.Strings(r.AssociationID)),
+			AssociationType:           StringVector(copyobj.Strings(r.AssociationType)),
+			MediaStreamStructureID:    StringVector(copyobj.Strings(r.MediaStreamStructureID)),
+			Profiles:                  copyobj.String(r.Profiles),
+			ScanType:                  copyobj.String(r.ScanType),
+			CodingDependency:          copyobj.Bool(r.CodingDependency),
+			MaximumSAPPeriod:          copyobj.Float64(r.MaximumSAPPeriod),
+			MaxPlayoutRate:            copyobj.Float64(r.MaxPlayoutRate),
+			SelectionPriority:         copyobj.UInt64(r.SelectionPriority),
+			Tag:                       copyobj.String(r.Tag),
+			SegmentProfiles:           StringVector(copyobj.Strings(r.SegmentProfiles)),
+			FrameRate:                 copyFrameRate(r.FrameRate),
+			Height:                    copyobj.UInt64(r.Height),
+			ID:                        copyobj.String(r.ID),
+			Width:                     copyobj.UInt64(r.Width),
+			FramePacking:              copyDescriptors(r.FramePacking),
+			AudioChannelConfiguration: copyDescriptors(r.AudioChannelConfiguration),
+			InbandEventStreams:        copyDescriptors(r.InbandEventStreams),
+			Switching:                 copySwitchings(r.Switching),
+			RandomAccess:              copyRandomAccesses(r.RandomAccess),
+			Resync:                    copyResyncs(r.Resync),
+			BaseURL:                   copyBaseURLs(r.BaseURL),
+			SegmentBase:               copySegmentBase(r.SegmentBase),
+			SegmentList:               modifySegmentList(r.SegmentList),
+			SegmentTemplate:           modifySegmentTemplate(r.SegmentTemplate),
+			ExtensionAttrs:            modifyAttrs(r.ExtensionAttrs, ns),
+			Extensions:                modifyExtensions(r.Extensions, ns.with(r.ExtensionAttrs)),
+			SAR:                       copyRatio(r.SAR),
+			ContentProtections:        modifyContentProtections(r.ContentProtections, ns.with(r.ExtensionAttrs)),
+			EssentialProperty:         copyDescriptors(r.EssentialProperty),
+			SupplementalProperty:      copyDescriptors(r.SupplementalProperty),
 		}
 		rsm = append(rsm, representation)
 	}
 	return rsm
 }
 
-func copySegmentTemplate(st *SegmentTemplate) *SegmentTemplate {
+func modifySegmentTemplate(st *SegmentTemplate) *segmentTemplateMarshal {
 	if st == nil {
 		return nil
 	}
-	return &SegmentTemplate{
-		Timescale:              copyobj.UInt64(st.Timescale),
-		Media:                  copyobj.String(st.Media),
-		Initialization:         copyobj.String(st.Initialization),
-		StartNumber:            copyobj.UInt64(st.StartNumber),
-		PresentationTimeOffset: copyobj.UInt64(st.PresentationTimeOffset),
-		SegmentTimelineS:       copySegmentTimelineS(st.SegmentTimelineS),
+	res := &segmentTemplateMarshal{
+		Timescale:                copyobj.UInt64(st.Timescale),
+		Duration:                 copyobj.UInt64(st.Duration),
+		Media:                    copyobj.String(st.Media),
+		Index:                    copyobj.String(st.Index),
+		Initialization:           copyobj.String(st.Initialization),
+		BitstreamSwitching:       copyobj.String(st.BitstreamSwitching),
+		StartNumber:              copyobj.UInt64(st.StartNumber),
+		EndNumber:                copyobj.UInt64(st.EndNumber),
+		PresentationTimeOffset:   copyobj.UInt64(st.PresentationTimeOffset),
+		AvailabilityTimeOffset:   copyAvailabilityTimeOffset(st.AvailabilityTimeOffset),
+		AvailabilityTimeComplete: copyobj.Bool(st.AvailabilityTimeComplete),
+		InitializationURL:        copyURLType(st.InitializationURL),
+		RepresentationIndex:      copyURLType(st.RepresentationIndex),
+	}
+	if len(st.SegmentTimelineS) > 0 {
+		res.SegmentTimeline = &segmentTimelineMarshal{S: copySegmentTimelineS(st.SegmentTimelineS)}
+	}
+	return res
+}
+
+func copySegmentBase(sb *SegmentBase) *SegmentBase {
+	if sb == nil {
+		return nil
+	}
+	return &SegmentBase{
+		Timescale:                copyobj.UInt64(sb.Timescale),
+		PresentationTimeOffset:   copyobj.UInt64(sb.PresentationTimeOffset),
+		IndexRange:               copyobj.String(sb.IndexRange),
+		IndexRangeExact:          copyobj.Bool(sb.IndexRangeExact),
+		AvailabilityTimeOffset:   copyAvailabilityTimeOffset(sb.AvailabilityTimeOffset),
+		AvailabilityTimeComplete: copyobj.Bool(sb.AvailabilityTimeComplete),
+		Initialization:           copyURLType(sb.Initialization),
+		RepresentationIndex:      copyURLType(sb.RepresentationIndex),
+	}
+}
+
+func modifySegmentList(sl *SegmentList) *segmentListMarshal {
+	if sl == nil {
+		return nil
+	}
+	res := &segmentListMarshal{
+		Timescale:                copyobj.UInt64(sl.Timescale),
+		Duration:                 copyobj.UInt64(sl.Duration),
+		StartNumber:              copyobj.UInt64(sl.StartNumber),
+		PresentationTimeOffset:   copyobj.UInt64(sl.PresentationTimeOffset),
+		AvailabilityTimeOffset:   copyAvailabilityTimeOffset(sl.AvailabilityTimeOffset),
+		AvailabilityTimeComplete: copyobj.Bool(sl.AvailabilityTimeComplete),
+		Initialization:           copyURLType(sl.Initialization),
+		RepresentationIndex:      copyURLType(sl.RepresentationIndex),
+	}
+	if len(sl.SegmentTimelineS) > 0 {
+		res.SegmentTimeline = &segmentTimelineMarshal{S: copySegmentTimelineS(sl.SegmentTimelineS)}
+	}
+	if sl.SegmentURLs != nil {
+		res.SegmentURLs = make([]SegmentListURL, 0, len(sl.SegmentURLs))
+		for _, u := range sl.SegmentURLs {
+			res.SegmentURLs = append(res.SegmentURLs, SegmentListURL{
+				Media:      copyobj.String(u.Media),
+				MediaRange: copyobj.String(u.MediaRange),
+				Index:      copyobj.String(u.Index),
+				IndexRange: copyobj.String(u.IndexRange),
+			})
+		}
+	}
+	return res
+}
+
+func copyURLType(u *URLType) *URLType {
+	if u == nil {
+		return nil
+	}
+	return &URLType{
+		SourceURL: copyobj.String(u.SourceURL),
+		Range:     copyobj.String(u.Range),
+	}
+}
+
+func copyPresentationType(t *PresentationType) *PresentationType {
+	if t == nil {
+		return nil
+	}
+	cop := *t
+	return &cop
+}
+
+func copyDateTime(t *DateTime) *DateTime {
+	if t == nil {
+		return nil
+	}
+	cop := *t
+	return &cop
+}
+
+func copyConditionalUint(c ConditionalUint) ConditionalUint {
+	return ConditionalUint{u: copyobj.UInt64(c.u), b: copyobj.Bool(c.b)}
+}
+
+func copyRatio(r *Ratio) *Ratio {
+	if r == nil {
+		return nil
 	}
+	cop := *r
+	return &cop
+}
+
+func copyAudioSamplingRate(a *AudioSamplingRate) *AudioSamplingRate {
+	if a == nil {
+		return nil
+	}
+	cop := *a
+	return &cop
+}
+
+func copyFrameRate(f *FrameRate) *FrameRate {
+	if f == nil {
+		return nil
+	}
+	cop := *f
+	return &cop
+}
+
+func copyAvailabilityTimeOffset(a *AvailabilityTimeOffset) *AvailabilityTimeOffset {
+	if a == nil {
+		return nil
+	}
+	cop := *a
+	return &cop
 }
 
 func copySegmentTimelineS(st []SegmentTimelineS) []SegmentTimelineS {
+	if st == nil {
+		return nil
+	}
 	stm := make([]SegmentTimelineS, 0, len(st))
 	for _, s := range st {
 		segmentTimelineS := SegmentTimelineS{
-			T: s.T,
+			T: copyobj.UInt64(s.T),
+			N: copyobj.UInt64(s.N),
 			D: s.D,
 			R: copyobj.Int64(s.R),
 		}
@@ -373,21 +1999,110 @@ func copySegmentTimelineS(st []SegmentTimelineS) []SegmentTimelineS {
 	return stm
 }
 
-func modifyContentProtections(ds []DRMDescriptor) []drmDescriptorMarshal {
+func copyDescriptors(ds []Descriptor) []Descriptor {
+	if ds == nil {
+		return nil
+	}
+	res := make([]Descriptor, 0, len(ds))
+	for i := range ds {
+		res = append(res, *copyDescriptor(&ds[i]))
+	}
+	return res
+}
+
+func copyDescriptor(d *Descriptor) *Descriptor {
+	if d == nil {
+		return nil
+	}
+	return &Descriptor{
+		SchemeIDURI: copyobj.String(d.SchemeIDURI),
+		Value:       copyobj.String(d.Value),
+		ID:          copyobj.String(d.ID),
+	}
+}
+
+func modifyContentProtections(ds []DRMDescriptor, ns namespaces) []drmDescriptorMarshal {
 	dsm := make([]drmDescriptorMarshal, 0, len(ds))
 	for _, d := range ds {
 		descriptor := drmDescriptorMarshal{
 			CencDefaultKID: copyobj.String(d.CencDefaultKID),
 			SchemeIDURI:    copyobj.String(d.SchemeIDURI),
 			Value:          copyobj.String(d.Value),
+			Robustness:     copyobj.String(d.Robustness),
+			RefID:          copyobj.String(d.RefID),
+			Ref:            copyobj.String(d.Ref),
 			Cenc:           copyobj.String(d.Cenc),
+			MSPR:           copyobj.String(d.MSPR),
+			MAS:            copyobj.String(d.MAS),
 			Pssh:           modifyPssh(d.Pssh),
+			Pro:            modifyPro(d.Pro),
+		}
+		// prefixed elements are written with their namespace declared, unless it's already declared with
+		// the same prefix: the URI declared with another prefix is declared again with the fixed one
+		declared := func(uri, prefix string) bool {
+			return ns[uri] == prefix
+		}
+		// cenc prefix is often used without any declaration, so it's declared only if the URI has another prefix
+		if prefix, ok := ns[CENCNamespace]; ok && prefix != "cenc" && descriptor.Cenc == nil &&
+			(descriptor.CencDefaultKID != nil || (descriptor.Pssh != nil && descriptor.Pssh.Cenc == nil)) {
+			cenc := CENCNamespace
+			descriptor.Cenc = &cenc
+		}
+		if descriptor.Pro != nil && descriptor.MSPR == nil && descriptor.Pro.MSPR == nil && !declared(MSPRNamespace, "mspr") {
+			mspr := MSPRNamespace
+			descriptor.Pro.MSPR = &mspr
+		}
+		if l := d.Laurl; l != nil {
+			descriptor.Laurl = &laurlMarshal{
+				DashIF:      copyobj.String(l.DashIF),
+				LicenseType: copyobj.String(l.LicenseType),
+				Value:       copyobj.String(l.Value),
+			}
+			if l.DashIF == nil && !declared(DashIFCPSNamespace, "dashif") {
+				dashif := DashIFCPSNamespace
+				descriptor.Laurl.DashIF = &dashif
+			}
+		}
+		if l := d.LegacyLaurl; l != nil {
+			descriptor.LegacyLaurl = &legacyLaurlMarshal{
+				ClearKey: copyobj.String(l.ClearKey),
+				LicType:  copyobj.String(l.LicType),
+				Value:    copyobj.String(l.Value),
+			}
+			if l.ClearKey == nil && !declared(ClearKeyNamespace, "clearkey") {
+				clearkey := ClearKeyNamespace
+				descriptor.LegacyLaurl.ClearKey = &clearkey
+			}
+		}
+		descriptor.AuthzURL = modifyDashIFValue(d.AuthzURL, ns)
+		descriptor.AuthzScheme = modifyDashIFValue(d.AuthzScheme, ns)
+		if d.MarlinContentIDs != nil {
+			descriptor.MarlinContentIDs = &marlinContentIDsMarshal{ContentIDs: copyobj.Strings(d.MarlinContentIDs.ContentIDs)}
+			if d.MAS == nil && !declared(MASNamespace, "mas") {
+				mas := MASNamespace
+				descriptor.MAS = &mas
+			}
 		}
 		dsm = append(dsm, descriptor)
 	}
 	return dsm
 }
 
+func modifyDashIFValue(v *DashIFValue, ns namespaces) *dashIFValueMarshal {
+	if v == nil {
+		return nil
+	}
+	res := &dashIFValueMarshal{
+		DashIF: copyobj.String(v.DashIF),
+		Value:  copyobj.String(v.Value),
+	}
+	if ns[DashIFCPSNamespace] != "dashif" && res.DashIF == nil {
+		dashif := DashIFCPSNamespace
+		res.DashIF = &dashif
+	}
+	return res
+}
+
 func modifyPssh(p *Pssh) *psshMarshal {
 	if p == nil {
 		return nil
@@ -397,3 +2112,13 @@ func modifyPssh(p *Pssh) *psshMarshal {
 		Value: copyobj.String(p.Value),
 	}
 }
+
+func modifyPro(p *Pro) *proMarshal {
+	if p == nil {
+		return nil
+	}
+	return &proMarshal{
+		MSPR:  copyobj.String(p.MSPR),
+		Value: copyobj.String(p.Value),
+	}
+}
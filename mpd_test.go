@@ -1,10 +1,15 @@
 package mpd
 
 import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	. "gopkg.in/check.v1"
@@ -41,6 +46,15 @@ func testUnmarshalMarshal(c *C, name string) {
 	}
 }
 
+func decodeFixture(t *testing.T, name string) *MPD {
+	b, err := ioutil.ReadFile(name)
+	require.NoError(t, err)
+
+	mpd := new(MPD)
+	require.NoError(t, mpd.Decode(b))
+	return mpd
+}
+
 func (s *MPDSuite) TestUnmarshalMarshalLiveFlussonic(c *C) {
 	testUnmarshalMarshal(c, "fixture_flussonic_live.mpd")
 }
@@ -61,20 +75,395 @@ func (s *MPDSuite) TestUnmarshalMarshalVodBaseURL(c *C) {
 	testUnmarshalMarshal(c, "fixture_vod_with_base_url.mpd")
 }
 
+func (s *MPDSuite) TestUnmarshalMarshalProgramInformation(c *C) {
+	testUnmarshalMarshal(c, "fixture_program_information.mpd")
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalSegmentList(c *C) {
+	testUnmarshalMarshal(c, "fixture_segment_list.mpd")
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalSegmentBase(c *C) {
+	testUnmarshalMarshal(c, "fixture_segment_base.mpd")
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalSegmentTemplateDuration(c *C) {
+	testUnmarshalMarshal(c, "fixture_segment_template_duration.mpd")
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalAdaptationSetTemplate(c *C) {
+	testUnmarshalMarshal(c, "fixture_adaptation_set_template.mpd")
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalPeriodTemplate(c *C) {
+	testUnmarshalMarshal(c, "fixture_period_template.mpd")
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalEventStream(c *C) {
+	testUnmarshalMarshal(c, "fixture_event_stream.mpd")
+}
+
+func TestEventStreamEqual(t *testing.T) {
+	a := &EventStream{}
+	b := &eventStreamMarshal{}
+	require.Equal(t, 7, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and function modifyEventStreams")
+	require.Equal(t, reflect.ValueOf(a).Elem().NumField(), reflect.ValueOf(b).Elem().NumField(),
+		"EventStream element count not equal eventStreamMarshal")
+}
+
+func TestEventEqual(t *testing.T) {
+	a := &Event{}
+	b := &eventMarshal{}
+	require.Equal(t, 8, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and function modifyEventStreams")
+	require.Equal(t, reflect.ValueOf(a).Elem().NumField(), reflect.ValueOf(b).Elem().NumField(),
+		"Event element count not equal eventMarshal")
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalInbandEventStream(c *C) {
+	testUnmarshalMarshal(c, "fixture_inband_event_stream.mpd")
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalMetricsReporting(c *C) {
+	testUnmarshalMarshal(c, "fixture_metrics_reporting.mpd")
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalContentComponent(c *C) {
+	testUnmarshalMarshal(c, "fixture_content_component.mpd")
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalFramePacking(c *C) {
+	testUnmarshalMarshal(c, "fixture_frame_packing.mpd")
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalDescriptors(c *C) {
+	testUnmarshalMarshal(c, "fixture_descriptors.mpd")
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalXLink(c *C) {
+	testUnmarshalMarshal(c, "fixture_xlink.mpd")
+}
+
+func TestEncodeXLinkNamespace(t *testing.T) {
+	m := &MPD{Period: []Period{{XLinkHref: stringPtr("https://example.com/period.xml")}}}
+	b, err := m.Encode()
+	require.NoError(t, err)
+	require.Contains(t, string(b), `xmlns:xlink="http://www.w3.org/1999/xlink"`)
+	require.Contains(t, string(b), `<Period xlink:href="https://example.com/period.xml"/>`)
+	require.Nil(t, m.XLink)
+
+	decoded := new(MPD)
+	require.NoError(t, decoded.Decode(b))
+	require.Equal(t, XLinkNamespace, *decoded.XLink)
+	require.Equal(t, "https://example.com/period.xml", *decoded.Period[0].XLinkHref)
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalDependentRepresentations(c *C) {
+	testUnmarshalMarshal(c, "fixture_dependent_representations.mpd")
+}
+
+func TestStringVector(t *testing.T) {
+	m := decodeFixture(t, "fixture_dependent_representations.mpd")
+	r := m.Period[0].AdaptationSets[2].Representations[0]
+	require.Equal(t, StringVector{"bl", "el"}, r.AssociationID)
+	require.Equal(t, StringVector{"cdsc", "cdsc"}, r.AssociationType)
+	require.Nil(t, r.DependencyID)
+	require.Equal(t, StringVector{"bl"}, m.Period[0].AdaptationSets[0].Representations[1].DependencyID)
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalBaseURLAttributes(c *C) {
+	testUnmarshalMarshal(c, "fixture_base_url_attributes.mpd")
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalLowLatency(c *C) {
+	testUnmarshalMarshal(c, "fixture_low_latency.mpd")
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalInitializationSet(c *C) {
+	testUnmarshalMarshal(c, "fixture_initialization_set.mpd")
+}
+
+func TestInitializationSet(t *testing.T) {
+	m := decodeFixture(t, "fixture_initialization_set.mpd")
+	require.Equal(t, UIntVector{2}, m.Period[0].AdaptationSets[1].InitializationSetRef)
+	ids, err := m.InitializationPresentation[0].IDs()
+	require.NoError(t, err)
+	require.Equal(t, UIntVector{1, 2}, ids)
+
+	m.Period[0].AdaptationSets[1].InitializationSetRef = UIntVector{2, 3}
+	require.Equal(t, []Finding{
+		{SeverityError, "MPD/Period[0]/AdaptationSet[1]", "initializationSetRef 3 refers to unknown InitializationSet"},
+	}, m.Validate())
+
+	require.Error(t, new(MPD).Decode([]byte(`<MPD><Period><AdaptationSet initializationSetRef="1 x"/></Period></MPD>`)))
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalPreselection(c *C) {
+	testUnmarshalMarshal(c, "fixture_preselection.mpd")
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalExtensions(c *C) {
+	testUnmarshalMarshal(c, "fixture_extensions.mpd")
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalPlayReady(c *C) {
+	testUnmarshalMarshal(c, "fixture_playready.mpd")
+}
+
+func TestEncodeMSPRNamespace(t *testing.T) {
+	m := &MPD{Period: []Period{{AdaptationSets: []*AdaptationSet{{ContentProtections: []DRMDescriptor{{
+		SchemeIDURI: stringPtr("urn:uuid:9a04f079-9840-4286-ab92-e65be0885f95"),
+		Pro:         &Pro{Value: stringPtr("BgIAAA==")},
+	}}}}}}}
+	b, err := m.Encode()
+	require.NoError(t, err)
+	require.Contains(t, string(b), `<mspr:pro xmlns:mspr="urn:microsoft:playready">BgIAAA==</mspr:pro>`)
+	require.Nil(t, m.Period[0].AdaptationSets[0].ContentProtections[0].Pro.MSPR)
+
+	// namespace declared on MPD element is not repeated
+	m.DeclareNamespace("mspr", MSPRNamespace)
+	b, err = m.Encode()
+	require.NoError(t, err)
+	require.Contains(t, string(b), `<mspr:pro>BgIAAA==</mspr:pro>`)
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalClearKey(c *C) {
+	testUnmarshalMarshal(c, "fixture_clearkey.mpd")
+}
+
+func TestEncodeLaurlNamespaces(t *testing.T) {
+	m := &MPD{Period: []Period{{AdaptationSets: []*AdaptationSet{{ContentProtections: []DRMDescriptor{
+		{Laurl: &Laurl{Value: stringPtr("https://example.com/license")}},
+		{LegacyLaurl: &LegacyLaurl{Value: stringPtr("https://example.com/license")}},
+		{AuthzURL: &DashIFValue{Value: stringPtr("https://example.com/token")}},
+	}}}}}}
+	b, err := m.Encode()
+	require.NoError(t, err)
+	require.Contains(t, string(b), `<dashif:laurl xmlns:dashif="https://dashif.org/CPS">https://example.com/license</dashif:laurl>`)
+	require.Contains(t, string(b), `<clearkey:Laurl xmlns:clearkey="http://dashif.org/guidelines/clearKey">https://example.com/license</clearkey:Laurl>`)
+	require.Contains(t, string(b), `<dashif:authzurl xmlns:dashif="https://dashif.org/CPS">https://example.com/token</dashif:authzurl>`)
+
+	decoded := new(MPD)
+	require.NoError(t, decoded.Decode(b))
+	cps := decoded.Period[0].AdaptationSets[0].ContentProtections
+	require.Equal(t, "https://example.com/license", *cps[0].Laurl.Value)
+	require.Nil(t, cps[0].LegacyLaurl)
+	require.Equal(t, "https://example.com/license", *cps[1].LegacyLaurl.Value)
+	require.Equal(t, "https://example.com/token", *cps[2].AuthzURL.Value)
+}
+
+func TestEncodeContentProtectionNonDefaultPrefixes(t *testing.T) {
+	m := &MPD{Period: []Period{{AdaptationSets: []*AdaptationSet{{ContentProtections: []DRMDescriptor{
+		{
+			CencDefaultKID: stringPtr("10000000-1000-1000-1000-100000000001"),
+			Pssh:           &Pssh{Value: stringPtr("cHNzaA==")},
+			Pro:            &Pro{Value: stringPtr("BgIAAA==")},
+		},
+		{Laurl: &Laurl{Value: stringPtr("https://example.com/license")}},
+		{LegacyLaurl: &LegacyLaurl{Value: stringPtr("https://example.com/license")}},
+		{AuthzURL: &DashIFValue{Value: stringPtr("https://example.com/token")}},
+		{MarlinContentIDs: &MarlinContentIDs{ContentIDs: []string{"urn:marlin:kid:1"}}},
+	}}}}}}
+	m.DeclareNamespace("dif", DashIFCPSNamespace)
+	m.DeclareNamespace("c", CENCNamespace)
+	m.DeclareNamespace("pr", MSPRNamespace)
+	m.DeclareNamespace("ck", ClearKeyNamespace)
+	m.DeclareNamespace("marlin", MASNamespace)
+	b, err := m.Encode()
+	require.NoError(t, err)
+
+	// every prefixed name is bound to its namespace
+	spaces := map[string]string{
+		"default_KID":      CENCNamespace,
+		"pssh":             CENCNamespace,
+		"pro":              MSPRNamespace,
+		"laurl":            DashIFCPSNamespace,
+		"Laurl":            ClearKeyNamespace,
+		"authzurl":         DashIFCPSNamespace,
+		"MarlinContentIds": MASNamespace,
+		"MarlinContentId":  MASNamespace,
+	}
+	found := make(map[string]bool)
+	dec := xml.NewDecoder(bytes.NewReader(b))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		names := []xml.Name{se.Name}
+		for _, a := range se.Attr {
+			names = append(names, a.Name)
+		}
+		for _, name := range names {
+			if space, ok := spaces[name.Local]; ok {
+				require.Equal(t, space, name.Space, name.Local)
+				found[name.Local] = true
+			}
+		}
+	}
+	require.Len(t, found, len(spaces))
+
+	decoded := new(MPD)
+	require.NoError(t, decoded.Decode(b))
+	cps := decoded.Period[0].AdaptationSets[0].ContentProtections
+	require.Equal(t, "10000000-1000-1000-1000-100000000001", *cps[0].CencDefaultKID)
+	require.Equal(t, "BgIAAA==", *cps[0].Pro.Value)
+	require.Equal(t, "https://example.com/license", *cps[1].Laurl.Value)
+	require.Equal(t, "https://example.com/license", *cps[2].LegacyLaurl.Value)
+	require.Equal(t, "https://example.com/token", *cps[3].AuthzURL.Value)
+	require.Equal(t, []string{"urn:marlin:kid:1"}, cps[4].MarlinContentIDs.ContentIDs)
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalMarlin(c *C) {
+	testUnmarshalMarshal(c, "fixture_marlin.mpd")
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalContentProtectionRef(c *C) {
+	testUnmarshalMarshal(c, "fixture_content_protection_ref.mpd")
+}
+
+func (s *MPDSuite) TestUnmarshalMarshalSubset(c *C) {
+	testUnmarshalMarshal(c, "fixture_subset.mpd")
+}
+
+func TestEncodeChildrenOrder(t *testing.T) {
+	m := &MPD{
+		Metrics:            []Metrics{{Metrics: "BufferLevel"}},
+		Period:             []Period{{}},
+		Location:           []string{"http://example.com/manifest.mpd"},
+		BaseURL:            []BaseURL{{Value: "http://cdn1.example.com/"}},
+		ProgramInformation: []ProgramInformation{{}},
+	}
+	b, err := m.Encode()
+	require.NoError(t, err)
+
+	var prev int
+	for _, name := range []string{"<ProgramInformation", "<BaseURL", "<Location", "<Period", "<Metrics"} {
+		i := strings.Index(string(b), name)
+		require.True(t, i > prev, "%s is out of order:\n%s", name, b)
+		prev = i
+	}
+}
+
 func TestMPDEqual(t *testing.T) {
 	a := &MPD{}
 	b := &mpdMarshal{}
-	require.Equal(t, 16, reflect.ValueOf(a).Elem().NumField(),
-		"model was updated, need to update this test and function modifyMPD")
+	require.Equal(t, 33, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and functions modifyMPD, cloneMPD")
 	require.Equal(t, reflect.ValueOf(a).Elem().NumField(), reflect.ValueOf(b).Elem().NumField(),
 		"MPD element count not equal mpdMarshal")
 }
 
+func TestProgramInformationEqual(t *testing.T) {
+	a := &ProgramInformation{}
+	require.Equal(t, 5, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and function copyProgramInformation")
+}
+
+func TestPatchLocationEqual(t *testing.T) {
+	a := &PatchLocation{}
+	require.Equal(t, 2, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and function copyPatchLocation")
+}
+
+func TestMetricsEqual(t *testing.T) {
+	a := &Metrics{}
+	b := &metricsMarshal{}
+	require.Equal(t, 3, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and function modifyMetrics")
+	require.Equal(t, reflect.ValueOf(a).Elem().NumField(), reflect.ValueOf(b).Elem().NumField(),
+		"Metrics element count not equal metricsMarshal")
+}
+
+func TestContentSteeringEqual(t *testing.T) {
+	require.Equal(t, 5, reflect.ValueOf(&ContentSteering{}).Elem().NumField(),
+		"model was updated, need to update this test and function copyContentSteering")
+}
+
+func TestSubsetEqual(t *testing.T) {
+	require.Equal(t, 2, reflect.ValueOf(&Subset{}).Elem().NumField(),
+		"model was updated, need to update this test and function copySubsets")
+}
+
+func TestPreselectionEqual(t *testing.T) {
+	require.Equal(t, 13, reflect.ValueOf(&Preselection{}).Elem().NumField(),
+		"model was updated, need to update this test and function copyPreselections")
+}
+
+func TestInitializationSetEqual(t *testing.T) {
+	a := &InitializationSet{}
+	b := &initializationSetMarshal{}
+	require.Equal(t, 19, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and function modifyInitializationSets")
+	require.Equal(t, reflect.ValueOf(a).Elem().NumField(), reflect.ValueOf(b).Elem().NumField(),
+		"InitializationSet element count not equal initializationSetMarshal")
+	require.Equal(t, 4, reflect.ValueOf(&UIntVWithID{}).Elem().NumField(),
+		"model was updated, need to update this test and function copyUIntVWithIDs")
+}
+
+func TestSwitchingEqual(t *testing.T) {
+	require.Equal(t, 2, reflect.ValueOf(&Switching{}).Elem().NumField(),
+		"model was updated, need to update this test and function copySwitchings")
+}
+
+func TestRandomAccessEqual(t *testing.T) {
+	require.Equal(t, 4, reflect.ValueOf(&RandomAccess{}).Elem().NumField(),
+		"model was updated, need to update this test and function copyRandomAccesses")
+}
+
+func TestResyncEqual(t *testing.T) {
+	require.Equal(t, 5, reflect.ValueOf(&Resync{}).Elem().NumField(),
+		"model was updated, need to update this test and function copyResyncs")
+}
+
+func TestServiceDescriptionEqual(t *testing.T) {
+	require.Equal(t, 6, reflect.ValueOf(&ServiceDescription{}).Elem().NumField(),
+		"model was updated, need to update this test and function copyServiceDescriptions")
+	require.Equal(t, 4, reflect.ValueOf(&Latency{}).Elem().NumField(),
+		"model was updated, need to update this test and function copyServiceDescriptions")
+	require.Equal(t, 2, reflect.ValueOf(&PlaybackRate{}).Elem().NumField(),
+		"model was updated, need to update this test and function copyServiceDescriptions")
+	require.Equal(t, 6, reflect.ValueOf(&OperatingQuality{}).Elem().NumField(),
+		"model was updated, need to update this test and function copyServiceDescriptions")
+	require.Equal(t, 4, reflect.ValueOf(&OperatingBandwidth{}).Elem().NumField(),
+		"model was updated, need to update this test and function copyServiceDescriptions")
+}
+
+func TestBaseURLEqual(t *testing.T) {
+	a := &BaseURL{}
+	b := &baseURLMarshal{}
+	require.Equal(t, 7, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and function copyBaseURL")
+	require.Equal(t, reflect.ValueOf(a).Elem().NumField(), reflect.ValueOf(b).Elem().NumField(),
+		"BaseURL element count not equal baseURLMarshal")
+}
+
+func TestReportingEqual(t *testing.T) {
+	a := &Reporting{}
+	b := &reportingMarshal{}
+	require.Equal(t, 5, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and function modifyMetrics")
+	require.Equal(t, reflect.ValueOf(a).Elem().NumField(), reflect.ValueOf(b).Elem().NumField(),
+		"Reporting element count not equal reportingMarshal")
+}
+
+func TestMetricsRangeEqual(t *testing.T) {
+	a := &MetricsRange{}
+	require.Equal(t, 2, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and function modifyMetrics")
+}
+
 func TestPeriodEqual(t *testing.T) {
 	a := &Period{}
 	b := &periodMarshal{}
-	require.Equal(t, 4, reflect.ValueOf(a).Elem().NumField(),
-		"model was updated, need to update this test and function modifyPeriod")
+	require.Equal(t, 16, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and functions modifyPeriod, clonePeriod")
 	require.Equal(t, reflect.ValueOf(a).Elem().NumField(), reflect.ValueOf(b).Elem().NumField(),
 		"Period element count not equal periodMarshal")
 }
@@ -82,37 +471,79 @@ func TestPeriodEqual(t *testing.T) {
 func TestAdaptationSetEqual(t *testing.T) {
 	a := &AdaptationSet{}
 	b := &adaptationSetMarshal{}
-	require.Equal(t, 10, reflect.ValueOf(a).Elem().NumField(),
-		"model was updated, need to update this test and function modifyAdaptationSets")
+	require.Equal(t, 50, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and functions modifyAdaptationSets, cloneAdaptationSet")
 	require.Equal(t, reflect.ValueOf(a).Elem().NumField(), reflect.ValueOf(b).Elem().NumField(),
 		"AdaptationSet element count not equal adaptationSetMarshal")
 }
 
+func TestContentComponentEqual(t *testing.T) {
+	a := &ContentComponent{}
+	require.Equal(t, 8, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and function copyContentComponents")
+}
+
 func TestRepresentationEqual(t *testing.T) {
 	a := &Representation{}
 	b := &representationMarshal{}
-	require.Equal(t, 11, reflect.ValueOf(a).Elem().NumField(),
-		"model was updated, need to update this test and function modifyRepresentations")
+	require.Equal(t, 36, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and functions modifyRepresentations, cloneRepresentation")
 	require.Equal(t, reflect.ValueOf(a).Elem().NumField(), reflect.ValueOf(b).Elem().NumField(),
 		"Representation element count not equal Representation")
 }
 
 func TestSegmentTemplateEqual(t *testing.T) {
 	a := &SegmentTemplate{}
-	require.Equal(t, 6, reflect.ValueOf(a).Elem().NumField(),
-		"model was updated, need to update this test and function copySegmentTemplate")
+	b := &segmentTemplateMarshal{}
+	require.Equal(t, 14, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and function modifySegmentTemplate")
+	require.Equal(t, reflect.ValueOf(a).Elem().NumField(), reflect.ValueOf(b).Elem().NumField(),
+		"SegmentTemplate element count not equal segmentTemplateMarshal")
+}
+
+func TestSegmentListEqual(t *testing.T) {
+	a := &SegmentList{}
+	b := &segmentListMarshal{}
+	require.Equal(t, 10, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and function modifySegmentList")
+	require.Equal(t, reflect.ValueOf(a).Elem().NumField(), reflect.ValueOf(b).Elem().NumField(),
+		"SegmentList element count not equal segmentListMarshal")
+}
+
+func TestSegmentBaseEqual(t *testing.T) {
+	a := &SegmentBase{}
+	require.Equal(t, 8, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and function copySegmentBase")
+}
+
+func TestURLTypeEqual(t *testing.T) {
+	a := &URLType{}
+	require.Equal(t, 2, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and function copyURLType")
+}
+
+func TestSegmentListURLEqual(t *testing.T) {
+	a := &SegmentListURL{}
+	require.Equal(t, 4, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and function modifySegmentList")
 }
 
 func TestSegmentTimelineSEqual(t *testing.T) {
 	a := &SegmentTimelineS{}
-	require.Equal(t, 3, reflect.ValueOf(a).Elem().NumField(),
+	require.Equal(t, 4, reflect.ValueOf(a).Elem().NumField(),
 		"model was updated, need to update this test and function copySegmentTimelineS")
 }
 
+func TestPlainDescriptorEqual(t *testing.T) {
+	a := &Descriptor{}
+	require.Equal(t, 3, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and function copyDescriptors")
+}
+
 func TestDescriptorEqual(t *testing.T) {
 	a := &DRMDescriptor{}
 	b := &drmDescriptorMarshal{}
-	require.Equal(t, 5, reflect.ValueOf(a).Elem().NumField(),
+	require.Equal(t, 16, reflect.ValueOf(a).Elem().NumField(),
 		"model was updated, need to update this test and function modifyContentProtections")
 	require.Equal(t, reflect.ValueOf(a).Elem().NumField(), reflect.ValueOf(b).Elem().NumField(),
 		"Descriptor element count not equal descriptorMarshal")
@@ -126,3 +557,200 @@ func TestPsshEqual(t *testing.T) {
 	require.Equal(t, reflect.ValueOf(a).Elem().NumField(), reflect.ValueOf(b).Elem().NumField(),
 		"Pssh element count not equal psshMarshal")
 }
+
+func TestProEqual(t *testing.T) {
+	a := &Pro{}
+	b := &proMarshal{}
+	require.Equal(t, 2, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and function modifyPro")
+	require.Equal(t, reflect.ValueOf(a).Elem().NumField(), reflect.ValueOf(b).Elem().NumField(),
+		"Pro element count not equal proMarshal")
+}
+
+func TestLaurlEqual(t *testing.T) {
+	require.Equal(t, 3, reflect.ValueOf(&Laurl{}).Elem().NumField(),
+		"model was updated, need to update this test and function modifyContentProtections")
+	require.Equal(t, reflect.ValueOf(&Laurl{}).Elem().NumField(), reflect.ValueOf(&laurlMarshal{}).Elem().NumField(),
+		"Laurl element count not equal laurlMarshal")
+	require.Equal(t, 3, reflect.ValueOf(&LegacyLaurl{}).Elem().NumField(),
+		"model was updated, need to update this test and function modifyContentProtections")
+	require.Equal(t, reflect.ValueOf(&LegacyLaurl{}).Elem().NumField(), reflect.ValueOf(&legacyLaurlMarshal{}).Elem().NumField(),
+		"LegacyLaurl element count not equal legacyLaurlMarshal")
+}
+
+func TestDashIFValueEqual(t *testing.T) {
+	a := &DashIFValue{}
+	b := &dashIFValueMarshal{}
+	require.Equal(t, 2, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and function modifyDashIFValue")
+	require.Equal(t, reflect.ValueOf(a).Elem().NumField(), reflect.ValueOf(b).Elem().NumField(),
+		"DashIFValue element count not equal dashIFValueMarshal")
+}
+
+func TestMarlinContentIDsEqual(t *testing.T) {
+	a := &MarlinContentIDs{}
+	b := &marlinContentIDsMarshal{}
+	require.Equal(t, 1, reflect.ValueOf(a).Elem().NumField(),
+		"model was updated, need to update this test and function modifyContentProtections")
+	require.Equal(t, reflect.ValueOf(a).Elem().NumField(), reflect.ValueOf(b).Elem().NumField(),
+		"MarlinContentIDs element count not equal marlinContentIDsMarshal")
+}
+
+func TestFrameRate(t *testing.T) {
+	for s, expected := range map[FrameRate]float64{"25": 25, "25/1": 25, "30000/1001": 30000.0 / 1001} {
+		fr, err := s.Float()
+		require.NoError(t, err)
+		require.Equal(t, expected, fr)
+	}
+	for _, s := range []FrameRate{"", "25.5", "1/0", "1/2/3", "a/b"} {
+		_, err := s.Float()
+		require.Error(t, err, s)
+	}
+
+	num, den, err := FrameRate("30000/1001").Ratio()
+	require.NoError(t, err)
+	require.Equal(t, []uint64{30000, 1001}, []uint64{num, den})
+	require.Equal(t, FrameRate("25"), NewFrameRate(25, 1))
+	require.Equal(t, FrameRate("30000/1001"), NewFrameRate(30000, 1001))
+
+	m := decodeFixture(t, "fixture_elemental_delta_vod_multi_drm.mpd")
+	require.Equal(t, FrameRate("25/1"), *m.Period[0].AdaptationSets[0].Representations[0].FrameRate)
+	b, err := m.Encode()
+	require.NoError(t, err)
+	require.Contains(t, string(b), `frameRate="25/1"`)
+}
+
+func TestRatio(t *testing.T) {
+	w, h, err := Ratio("16:9").Values()
+	require.NoError(t, err)
+	require.Equal(t, []uint64{16, 9}, []uint64{w, h})
+	f, err := NewRatio(4, 3).Float()
+	require.NoError(t, err)
+	require.Equal(t, 4.0/3, f)
+	for _, r := range []Ratio{"", "16", "16:9:1", "16/9", "a:b", "-1:1"} {
+		_, _, err := r.Values()
+		require.EqualError(t, err, fmt.Sprintf("invalid ratio %q", r))
+	}
+	_, err = Ratio("1:0").Float()
+	require.Error(t, err)
+
+	m := decodeFixture(t, "fixture_elemental_delta_vod_multi_drm.mpd")
+	r := &m.Period[0].AdaptationSets[0].Representations[0]
+	require.Equal(t, Ratio("1:1"), *r.SAR)
+	sar := Ratio("1")
+	r.SAR = &sar
+	require.Equal(t, []Finding{
+		{SeverityError, "MPD/Period[0]/AdaptationSet[0]/Representation[0]", `invalid ratio "1"`},
+	}, m.Validate())
+}
+
+func TestAudioSamplingRate(t *testing.T) {
+	min, max, err := NewAudioSamplingRate(48000).Values()
+	require.NoError(t, err)
+	require.Equal(t, []uint64{48000, 48000}, []uint64{min, max})
+	min, max, err = AudioSamplingRate(" 22050  44100 ").Values()
+	require.NoError(t, err)
+	require.Equal(t, []uint64{22050, 44100}, []uint64{min, max})
+	require.Equal(t, AudioSamplingRate("22050 44100"), NewAudioSamplingRateRange(22050, 44100))
+	for _, a := range []AudioSamplingRate{"", "48kHz", "1 2 3", "48000 -1", "48000 44100"} {
+		_, _, err := a.Values()
+		require.EqualError(t, err, fmt.Sprintf("invalid audioSamplingRate %q", a))
+	}
+
+	m := decodeFixture(t, "fixture_preselection.mpd")
+	require.Equal(t, AudioSamplingRate("48000"), *m.Period[0].Preselections[0].AudioSamplingRate)
+	r := &m.Period[0].AdaptationSets[1].Representations[0]
+	require.Equal(t, AudioSamplingRate("48000"), *r.AudioSamplingRate)
+	rate := AudioSamplingRate("48 kHz")
+	r.AudioSamplingRate = &rate
+	require.Equal(t, []Finding{
+		{SeverityError, "MPD/Period[0]/AdaptationSet[1]/Representation[0]", `invalid audioSamplingRate "48 kHz"`},
+	}, m.Validate())
+}
+
+func TestConditionalUint(t *testing.T) {
+	c := CondUint(2)
+	u, ok := c.Uint()
+	require.True(t, ok)
+	require.Equal(t, uint64(2), u)
+	_, ok = c.Bool()
+	require.False(t, ok)
+	require.True(t, c.IsTrue())
+
+	c = CondBool(false)
+	b, ok := c.Bool()
+	require.True(t, ok)
+	require.False(t, b)
+	_, ok = c.Uint()
+	require.False(t, ok)
+	require.False(t, c.IsTrue())
+	require.True(t, CondBool(true).IsTrue())
+	require.False(t, ConditionalUint{}.IsTrue())
+
+	attr, err := CondBool(true).MarshalXMLAttr(xml.Name{Local: "segmentAlignment"})
+	require.NoError(t, err)
+	require.Equal(t, "true", attr.Value)
+	attr, err = CondUint(2).MarshalXMLAttr(xml.Name{Local: "segmentAlignment"})
+	require.NoError(t, err)
+	require.Equal(t, "2", attr.Value)
+}
+
+func TestPresentationType(t *testing.T) {
+	m := decodeFixture(t, "fixture_elemental_delta_live.mpd")
+	require.Equal(t, PresentationTypeDynamic, *m.Type)
+	require.True(t, m.IsDynamic())
+	require.False(t, m.IsStatic())
+
+	m = &MPD{}
+	require.True(t, m.IsStatic())
+	require.False(t, m.IsDynamic())
+	m.Type = PresentationTypeOf(PresentationTypeStatic)
+	require.True(t, m.IsStatic())
+
+	err := new(MPD).Decode([]byte(`<MPD xmlns="urn:mpeg:dash:schema:mpd:2011" type="live"></MPD>`))
+	require.EqualError(t, err, `PresentationType: can't UnmarshalXMLAttr xml.Attr{Name:xml.Name{Space:"", Local:"type"}, Value:"live"}`)
+}
+
+func TestAvailabilityTimeOffset(t *testing.T) {
+	for _, s := range []string{"INF", "1.5", "0"} {
+		var a AvailabilityTimeOffset
+		require.NoError(t, a.UnmarshalXMLAttr(xml.Attr{Value: s}))
+		attr, err := a.MarshalXMLAttr(xml.Name{Local: "availabilityTimeOffset"})
+		require.NoError(t, err)
+		require.Equal(t, s, attr.Value)
+		require.Equal(t, s == "INF", a.IsInf())
+	}
+
+	var a AvailabilityTimeOffset
+	require.Error(t, a.UnmarshalXMLAttr(xml.Attr{Value: "inf"}))
+	require.Error(t, a.UnmarshalXMLAttr(xml.Attr{Value: "+Inf"}))
+	require.Equal(t, 1500*time.Millisecond, AvailabilityTimeOffset(1.5).Duration())
+
+	st := &SegmentTemplate{}
+	b, err := xml.Marshal(st)
+	require.NoError(t, err)
+	require.NotContains(t, string(b), "availabilityTimeOffset")
+
+	inf := InfiniteAvailabilityTimeOffset
+	st.AvailabilityTimeOffset = &inf
+	b, err = xml.Marshal(st)
+	require.NoError(t, err)
+	require.Contains(t, string(b), `<SegmentTemplate availabilityTimeOffset="INF">`)
+
+	require.NoError(t, xml.Unmarshal([]byte(`<SegmentTemplate availabilityTimeOffset="7.5"/>`), st))
+	require.Equal(t, AvailabilityTimeOffset(7.5), *st.AvailabilityTimeOffset)
+}
+
+func TestUTCTiming(t *testing.T) {
+	m := decodeFixture(t, "fixture_inband_event_stream.mpd")
+	require.Len(t, m.UTCTiming, 2)
+	require.Equal(t, UTCTimingHTTPISO, *m.UTCTiming[0].SchemeIDURI)
+	require.Equal(t, UTCTimingDirect, *m.UTCTiming[1].SchemeIDURI)
+}
+
+func TestPatchLocation(t *testing.T) {
+	m := decodeFixture(t, "fixture_inband_event_stream.mpd")
+	require.Len(t, m.PatchLocation, 1)
+	require.Equal(t, 60.0, *m.PatchLocation[0].TTL)
+	require.Equal(t, "https://example.com/manifest.mpp?publishTime=2021-01-01T00:00:00Z", m.PatchLocation[0].Value)
+}
@@ -0,0 +1,149 @@
+package mpd
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// PlayReadyHeader holds fields of PlayReady Header (WRMHEADER) carried by PlayReady Object.
+type PlayReadyHeader struct {
+	// Version is WRMHEADER@version, e.g. "4.0.0.0".
+	Version string
+	KIDs    []PlayReadyKID
+	LAURL   string
+	LUIURL  string
+}
+
+// PlayReadyKID is a key ID signaled by PlayReady Header.
+type PlayReadyKID struct {
+	// ID is formatted like cenc:default_KID, PlayReady little-endian GUID is converted to UUID.
+	ID string
+	// AlgID is an encryption algorithm, e.g. "AESCTR" or "AESCBC", empty if not signaled.
+	AlgID string
+}
+
+// playReadyHeaderRecord is a type of PlayReady Object record holding WRMHEADER.
+const playReadyHeaderRecord = 1
+
+type wrmHeader struct {
+	Version string `xml:"version,attr"`
+	Data    struct {
+		ProtectInfo struct {
+			AlgID string `xml:"ALGID"`
+			// KID is used by version 4.1, KIDS by versions 4.2 and 4.3.
+			KID  []wrmKID `xml:"KID"`
+			KIDS []wrmKID `xml:"KIDS>KID"`
+		} `xml:"PROTECTINFO"`
+		// KID is used by version 4.0.
+		KID    string `xml:"KID"`
+		LAURL  string `xml:"LA_URL"`
+		LUIURL string `xml:"LUI_URL"`
+	} `xml:"DATA"`
+}
+
+type wrmKID struct {
+	AlgID string `xml:"ALGID,attr"`
+	Value string `xml:"VALUE,attr"`
+}
+
+// Header decodes base64 value of mspr:pro element and parses PlayReady Header of the PlayReady Object.
+func (p *Pro) Header() (*PlayReadyHeader, error) {
+	if p == nil || p.Value == nil {
+		return nil, fmt.Errorf("empty mspr:pro")
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(*p.Value))
+	if err != nil {
+		return nil, fmt.Errorf("mspr:pro: %s", err)
+	}
+	return ParsePlayReadyObject(b)
+}
+
+// PlayReady parses PlayReady Header of PlayReady PSSH box data.
+func (b *PSSHBox) PlayReady() (*PlayReadyHeader, error) {
+	if b.SystemID != PlayReadySystemID {
+		return nil, fmt.Errorf("pssh box of system %s is not a PlayReady one", b.SystemID)
+	}
+	return ParsePlayReadyObject(b.Data)
+}
+
+// ParsePlayReadyObject parses PlayReady Header of binary PlayReady Object.
+func ParsePlayReadyObject(b []byte) (*PlayReadyHeader, error) {
+	if len(b) < 6 {
+		return nil, fmt.Errorf("playready object too short: %d bytes", len(b))
+	}
+	if size := binary.LittleEndian.Uint32(b); size != uint32(len(b)) {
+		return nil, fmt.Errorf("playready object size %d doesn't match data size %d", size, len(b))
+	}
+	count := binary.LittleEndian.Uint16(b[4:])
+	rest := b[6:]
+	for i := uint16(0); i < count; i++ {
+		if len(rest) < 4 {
+			return nil, fmt.Errorf("playready object truncated record %d", i)
+		}
+		typ, size := binary.LittleEndian.Uint16(rest), int(binary.LittleEndian.Uint16(rest[2:]))
+		if len(rest) < 4+size {
+			return nil, fmt.Errorf("playready object truncated record %d", i)
+		}
+		if typ == playReadyHeaderRecord {
+			return ParsePlayReadyHeader(rest[4 : 4+size])
+		}
+		rest = rest[4+size:]
+	}
+	return nil, fmt.Errorf("playready object has no PlayReady Header record")
+}
+
+// ParsePlayReadyHeader parses UTF-16LE encoded WRMHEADER XML.
+func ParsePlayReadyHeader(b []byte) (*PlayReadyHeader, error) {
+	if len(b)%2 != 0 {
+		return nil, fmt.Errorf("playready header: odd UTF-16 data size %d", len(b))
+	}
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = binary.LittleEndian.Uint16(b[2*i:])
+	}
+	text := strings.TrimPrefix(string(utf16.Decode(u)), "\ufeff")
+
+	var h wrmHeader
+	d := xml.NewDecoder(strings.NewReader(text))
+	// text is already decoded, encoding in XML declaration is ignored
+	d.CharsetReader = func(_ string, r io.Reader) (io.Reader, error) { return r, nil }
+	if err := d.Decode(&h); err != nil {
+		return nil, fmt.Errorf("playready header: %s", err)
+	}
+
+	res := &PlayReadyHeader{
+		Version: h.Version,
+		LAURL:   strings.TrimSpace(h.Data.LAURL),
+		LUIURL:  strings.TrimSpace(h.Data.LUIURL),
+	}
+	var kids []wrmKID
+	if h.Data.KID != "" {
+		kids = append(kids, wrmKID{AlgID: h.Data.ProtectInfo.AlgID, Value: h.Data.KID})
+	}
+	kids = append(append(kids, h.Data.ProtectInfo.KID...), h.Data.ProtectInfo.KIDS...)
+	for _, k := range kids {
+		id, err := playReadyKID(k.Value)
+		if err != nil {
+			return nil, err
+		}
+		res.KIDs = append(res.KIDs, PlayReadyKID{ID: id, AlgID: k.AlgID})
+	}
+	return res, nil
+}
+
+// playReadyKID converts base64 encoded little-endian GUID to UUID.
+func playReadyKID(s string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(b) != 16 {
+		return "", fmt.Errorf("playready header: invalid KID %q", s)
+	}
+	b[0], b[1], b[2], b[3] = b[3], b[2], b[1], b[0]
+	b[4], b[5] = b[5], b[4]
+	b[6], b[7] = b[7], b[6]
+	return formatUUID(b), nil
+}
@@ -0,0 +1,61 @@
+package mpd
+
+import (
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/require"
+)
+
+func playReadyObject(header string) []byte {
+	var record []byte
+	for _, u := range utf16.Encode([]rune(header)) {
+		record = append(record, byte(u), byte(u>>8))
+	}
+	b := make([]byte, 10, 10+len(record))
+	binary.LittleEndian.PutUint32(b, uint32(10+len(record)))
+	binary.LittleEndian.PutUint16(b[4:], 1)
+	binary.LittleEndian.PutUint16(b[6:], playReadyHeaderRecord)
+	binary.LittleEndian.PutUint16(b[8:], uint16(len(record)))
+	return append(b, record...)
+}
+
+func TestPlayReadyHeader(t *testing.T) {
+	m := decodeFixture(t, "fixture_elemental_delta_vod_multi_drm.mpd")
+	box, err := m.Period[0].AdaptationSets[0].Representations[0].ContentProtections[2].Pssh.Box()
+	require.NoError(t, err)
+	h, err := box.PlayReady()
+	require.NoError(t, err)
+	require.Equal(t, &PlayReadyHeader{
+		Version: "4.0.0.0",
+		KIDs:    []PlayReadyKID{{ID: "3bc69271-92de-4adc-8110-f4a4bbdd759c", AlgID: "AESCTR"}},
+	}, h)
+
+	_, err = (&PSSHBox{SystemID: WidevineSystemID}).PlayReady()
+	require.EqualError(t, err, "pssh box of system edef8ba9-79d6-4ace-a3c8-27dcd51d21ed is not a PlayReady one")
+}
+
+func TestParsePlayReadyObject(t *testing.T) {
+	b := playReadyObject(`<WRMHEADER xmlns="http://schemas.microsoft.com/DRM/2007/03/PlayReadyHeader" version="4.3.0.0">` +
+		`<DATA><PROTECTINFO><KIDS><KID ALGID="AESCBC" VALUE="AAAAEAAQABAQAAAAAAAAAQ=="></KID><KID VALUE="AAAAIAAgACAgAAAAAAAAAg=="></KID></KIDS></PROTECTINFO>` +
+		`<LA_URL>https://example.com/rightsmanager.asmx</LA_URL></DATA></WRMHEADER>`)
+	h, err := ParsePlayReadyObject(b)
+	require.NoError(t, err)
+	require.Equal(t, &PlayReadyHeader{
+		Version: "4.3.0.0",
+		KIDs: []PlayReadyKID{
+			{ID: "10000000-1000-1000-1000-000000000001", AlgID: "AESCBC"},
+			{ID: "20000000-2000-2000-2000-000000000002"},
+		},
+		LAURL: "https://example.com/rightsmanager.asmx",
+	}, h)
+
+	pro := &Pro{Value: stringPtr("AAAAEAAQABAQAAAAAAAAAQ==")}
+	_, err = pro.Header()
+	require.EqualError(t, err, "playready object size 268435456 doesn't match data size 16")
+	_, err = ParsePlayReadyObject(b[:20])
+	require.EqualError(t, err, "playready object size 644 doesn't match data size 20")
+	_, err = ParsePlayReadyObject(playReadyObject(`<WRMHEADER><DATA><KID>AAAA</KID></DATA></WRMHEADER>`))
+	require.EqualError(t, err, `playready header: invalid KID "AAAA"`)
+}
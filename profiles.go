@@ -0,0 +1,62 @@
+package mpd
+
+import (
+	"strings"
+)
+
+// Common MPD profiles.
+const (
+	ProfileISOFFLive     = "urn:mpeg:dash:profile:isoff-live:2011"
+	ProfileISOFFOnDemand = "urn:mpeg:dash:profile:isoff-on-demand:2011"
+	ProfileISOFFMain     = "urn:mpeg:dash:profile:isoff-main:2011"
+	ProfileDASHIFSimple  = "http://dashif.org/guidelines/dash-if-simple"
+	ProfileDASHIFMain    = "http://dashif.org/guidelines/dash-if-main"
+	ProfileCMAF          = "urn:mpeg:dash:profile:cmaf:2019"
+)
+
+// Profiles is a value of profiles attribute: comma-separated list of profile URNs.
+// It keeps the attribute text, so it's encoded exactly as decoded unless modified with Add or Remove.
+type Profiles string
+
+// List returns profiles in order, surrounding whitespace is trimmed and empty entries are skipped.
+func (p Profiles) List() []string {
+	var res []string
+	for _, profile := range strings.Split(string(p), ",") {
+		if profile = strings.TrimSpace(profile); profile != "" {
+			res = append(res, profile)
+		}
+	}
+	return res
+}
+
+// Has reports whether profile is in the list.
+func (p Profiles) Has(profile string) bool {
+	for _, v := range p.List() {
+		if v == profile {
+			return true
+		}
+	}
+	return false
+}
+
+// Add appends profile to the list if it's not there yet.
+func (p *Profiles) Add(profile string) {
+	if p.Has(profile) {
+		return
+	}
+	*p = Profiles(strings.Join(append(p.List(), profile), ","))
+}
+
+// Remove removes all occurrences of profile from the list.
+func (p *Profiles) Remove(profile string) {
+	if !p.Has(profile) {
+		return
+	}
+	var res []string
+	for _, v := range p.List() {
+		if v != profile {
+			res = append(res, v)
+		}
+	}
+	*p = Profiles(strings.Join(res, ","))
+}
@@ -0,0 +1,26 @@
+package mpd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiles(t *testing.T) {
+	m := decodeFixture(t, "fixture_low_latency.mpd")
+	require.True(t, m.Profiles.Has(ProfileISOFFLive))
+	require.False(t, m.Profiles.Has(ProfileISOFFOnDemand))
+
+	p := Profiles(ProfileISOFFLive + ", " + ProfileDASHIFMain)
+	require.Equal(t, []string{ProfileISOFFLive, ProfileDASHIFMain}, p.List())
+	p.Add(ProfileDASHIFMain)
+	require.Equal(t, Profiles(ProfileISOFFLive+", "+ProfileDASHIFMain), p)
+	p.Add(ProfileCMAF)
+	require.Equal(t, Profiles(ProfileISOFFLive+","+ProfileDASHIFMain+","+ProfileCMAF), p)
+	p.Remove(ProfileISOFFLive)
+	require.Equal(t, Profiles(ProfileDASHIFMain+","+ProfileCMAF), p)
+	p.Remove(ProfileDASHIFMain)
+	p.Remove(ProfileCMAF)
+	require.Equal(t, Profiles(""), p)
+	require.Empty(t, p.List())
+}
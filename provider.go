@@ -0,0 +1,136 @@
+package mpd
+
+import (
+	"fmt"
+	"time"
+
+	copyobj "github.com/mc2soft/mpd/utils"
+)
+
+// TimeWindow is a range of media time relative to availabilityStartTime, End is exclusive.
+type TimeWindow struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// SegmentProvider supplies segments for live MPD generation, it may be backed by a database,
+// an object store or an in-memory recorder.
+type SegmentProvider interface {
+	// ListSegments returns track segments starting within window in ascending time order,
+	// segment times are in track timescale units.
+	ListSegments(trackID string, window TimeWindow) ([]Segment, error)
+	// InitSegment returns initialization segment URL of the track.
+	InitSegment(trackID string) (string, error)
+}
+
+// LiveTrack describes a track of generated live MPD, it becomes Representation with the same id.
+type LiveTrack struct {
+	ID        string
+	MimeType  string
+	Codecs    string
+	Lang      *string
+	Bandwidth uint64
+	Width     *uint64
+	Height    *uint64
+	Timescale uint64
+	// Media is a SegmentTemplate@media, e.g. "$RepresentationID$/$Number$.m4s".
+	Media string
+}
+
+// LiveOptions configures NewLiveMPD.
+type LiveOptions struct {
+	ID                    *string
+	AvailabilityStartTime time.Time
+	// AvailabilityEndTime is announced if not zero.
+	AvailabilityEndTime  time.Time
+	TimeShiftBufferDepth time.Duration
+	MinimumUpdatePeriod  time.Duration
+	MinBufferTime        time.Duration
+	// Tracks with equal mimeType and lang are put into the same AdaptationSet.
+	Tracks []LiveTrack
+}
+
+// NewLiveMPD generates dynamic MPD with segments of the last TimeShiftBufferDepth before now
+// listed by provider.
+func NewLiveMPD(provider SegmentProvider, opts LiveOptions, now time.Time) (*MPD, error) {
+	if now.Before(opts.AvailabilityStartTime) {
+		return nil, fmt.Errorf("now %s is before availabilityStartTime %s", now, opts.AvailabilityStartTime)
+	}
+	window := TimeWindow{End: now.Sub(opts.AvailabilityStartTime)}
+	if window.End > opts.TimeShiftBufferDepth {
+		window.Start = window.End - opts.TimeShiftBufferDepth
+	}
+
+	period := Period{Start: stringPtrOf("PT0S"), ID: stringPtrOf("0")}
+	for _, track := range opts.Tracks {
+		segments, err := provider.ListSegments(track.ID, window)
+		if err != nil {
+			return nil, fmt.Errorf("track %s: %s", track.ID, err)
+		}
+		init, err := provider.InitSegment(track.ID)
+		if err != nil {
+			return nil, fmt.Errorf("track %s: %s", track.ID, err)
+		}
+
+		timescale := track.Timescale
+		st := &SegmentTemplate{
+			Timescale:      &timescale,
+			Media:          stringPtrOf(track.Media),
+			Initialization: &init,
+		}
+		if len(segments) > 0 {
+			startNumber := segments[0].Number
+			st.StartNumber = &startNumber
+			st.SegmentTimelineS = compactTimeline(segments, startNumber)
+		}
+
+		bandwidth := track.Bandwidth
+		r := Representation{
+			ID:              stringPtrOf(track.ID),
+			Width:           copyobj.UInt64(track.Width),
+			Height:          copyobj.UInt64(track.Height),
+			Bandwidth:       &bandwidth,
+			Codecs:          stringPtrOf(track.Codecs),
+			SegmentTemplate: st,
+		}
+		as := liveAdaptationSet(&period, track)
+		as.Representations = append(as.Representations, r)
+	}
+
+	m := &MPD{
+		XMLNS:                 stringPtrOf(DASHNamespace),
+		ID:                    opts.ID,
+		Type:                  PresentationTypeOf(PresentationTypeDynamic),
+		AvailabilityStartTime: NewDateTime(opts.AvailabilityStartTime.UTC()),
+		PublishTime:           NewDateTime(now.UTC()),
+		MinimumUpdatePeriod:   Duration(opts.MinimumUpdatePeriod).Attr(),
+		MinBufferTime:         Duration(opts.MinBufferTime).Attr(),
+		TimeShiftBufferDepth:  Duration(opts.TimeShiftBufferDepth).Attr(),
+		Profiles:              ProfileISOFFLive,
+		Period:                []Period{period},
+	}
+	if !opts.AvailabilityEndTime.IsZero() {
+		m.AvailabilityEndTime = NewDateTime(opts.AvailabilityEndTime.UTC())
+	}
+	return m, nil
+}
+
+// liveAdaptationSet returns AdaptationSet of period for track, adding a new one if needed.
+func liveAdaptationSet(p *Period, track LiveTrack) *AdaptationSet {
+	for _, as := range p.AdaptationSets {
+		if as.MimeType == track.MimeType && stringValue(as.Lang) == stringValue(track.Lang) {
+			return as
+		}
+	}
+	as := &AdaptationSet{
+		MimeType:         track.MimeType,
+		SegmentAlignment: CondBool(true),
+		Lang:             copyobj.String(track.Lang),
+	}
+	p.AdaptationSets = append(p.AdaptationSets, as)
+	return as
+}
+
+func stringPtrOf(s string) *string {
+	return &s
+}
@@ -0,0 +1,93 @@
+package mpd
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is an in-memory SegmentProvider with fixed duration segments starting at availabilityStartTime.
+type recorder struct {
+	segmentDuration time.Duration
+	timescale       uint64
+	windows         []TimeWindow
+}
+
+func (r *recorder) ListSegments(trackID string, window TimeWindow) ([]Segment, error) {
+	if trackID == "broken" {
+		return nil, fmt.Errorf("no such track")
+	}
+	r.windows = append(r.windows, window)
+	var res []Segment
+	for n := (window.Start + r.segmentDuration - 1) / r.segmentDuration; n*r.segmentDuration < window.End; n++ {
+		// only complete segments are listed
+		if (n+1)*r.segmentDuration > window.End {
+			break
+		}
+		res = append(res, Segment{
+			Number:   uint64(n) + 1,
+			Time:     durationToTicks(n*r.segmentDuration, r.timescale),
+			Duration: durationToTicks(r.segmentDuration, r.timescale),
+		})
+	}
+	return res, nil
+}
+
+func (r *recorder) InitSegment(trackID string) (string, error) {
+	return trackID + "/init.mp4", nil
+}
+
+func TestNewLiveMPD(t *testing.T) {
+	ast := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := ast.Add(time.Minute + 500*time.Millisecond)
+	provider := &recorder{segmentDuration: 2 * time.Second, timescale: 90000}
+	opts := LiveOptions{
+		ID:                    stringPtr("live"),
+		AvailabilityStartTime: ast,
+		TimeShiftBufferDepth:  10 * time.Second,
+		MinimumUpdatePeriod:   2 * time.Second,
+		MinBufferTime:         4 * time.Second,
+		Tracks: []LiveTrack{
+			{ID: "v1", MimeType: "video/mp4", Codecs: "avc1.4d401e", Bandwidth: 500000, Width: uint64Ptr(640), Height: uint64Ptr(360), Timescale: 90000, Media: "$RepresentationID$/$Number$.m4s"},
+			{ID: "v2", MimeType: "video/mp4", Codecs: "avc1.64001f", Bandwidth: 2000000, Width: uint64Ptr(1280), Height: uint64Ptr(720), Timescale: 90000, Media: "$RepresentationID$/$Number$.m4s"},
+			{ID: "a1", MimeType: "audio/mp4", Codecs: "mp4a.40.2", Lang: stringPtr("en"), Bandwidth: 128000, Timescale: 90000, Media: "$RepresentationID$/$Number$.m4s"},
+		},
+	}
+
+	m, err := NewLiveMPD(provider, opts, now)
+	require.NoError(t, err)
+	require.Equal(t, TimeWindow{Start: 50500 * time.Millisecond, End: 60500 * time.Millisecond}, provider.windows[0])
+
+	require.Equal(t, "2021-01-01T00:00:00Z", m.AvailabilityStartTime.String())
+	require.Equal(t, "2021-01-01T00:01:00.5Z", m.PublishTime.String())
+	require.Equal(t, "PT10S", *m.TimeShiftBufferDepth)
+	require.Nil(t, m.AvailabilityEndTime)
+	require.Len(t, m.Period[0].AdaptationSets, 2)
+	require.Len(t, m.Period[0].AdaptationSets[0].Representations, 2)
+	require.Equal(t, "en", *m.Period[0].AdaptationSets[1].Lang)
+
+	st := m.Period[0].AdaptationSets[0].Representations[1].SegmentTemplate
+	require.Equal(t, "v2/init.mp4", *st.Initialization)
+	require.Equal(t, uint64(27), *st.StartNumber)
+	require.Equal(t, []SegmentTimelineS{{T: uint64Ptr(26 * 180000), D: 180000, R: int64Ptr(3)}}, st.SegmentTimelineS)
+
+	require.Empty(t, m.Validate())
+	b, err := m.Encode()
+	require.NoError(t, err)
+	decoded := new(MPD)
+	require.NoError(t, decoded.Decode(b))
+	require.True(t, Equal(m, decoded, CompareOptions{}))
+
+	opts.AvailabilityEndTime = ast.Add(time.Hour)
+	m, err = NewLiveMPD(provider, opts, now)
+	require.NoError(t, err)
+	require.Equal(t, "2021-01-01T01:00:00Z", m.AvailabilityEndTime.String())
+
+	opts.Tracks = append(opts.Tracks, LiveTrack{ID: "broken"})
+	_, err = NewLiveMPD(provider, opts, now)
+	require.EqualError(t, err, "track broken: no such track")
+	_, err = NewLiveMPD(provider, opts, ast.Add(-time.Second))
+	require.Error(t, err)
+}
@@ -0,0 +1,78 @@
+package mpd
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+)
+
+// PSSHBox holds fields of a parsed ISO BMFF Protection System Specific Header Box (pssh).
+type PSSHBox struct {
+	Version uint8
+	Flags   uint32
+	// SystemID is formatted like MPD UUIDs, e.g. WidevineSystemID.
+	SystemID string
+	// KIDs are set by version 1 boxes only, formatted like cenc:default_KID.
+	KIDs []string
+	Data []byte
+}
+
+// Box decodes base64 value of cenc:pssh element and parses PSSH box.
+func (p *Pssh) Box() (*PSSHBox, error) {
+	if p == nil || p.Value == nil {
+		return nil, fmt.Errorf("empty pssh")
+	}
+	b, err := base64.StdEncoding.DecodeString(*p.Value)
+	if err != nil {
+		return nil, fmt.Errorf("pssh: %s", err)
+	}
+	return ParsePSSHBox(b)
+}
+
+// ParsePSSHBox parses binary PSSH box, b must hold exactly one box.
+func ParsePSSHBox(b []byte) (*PSSHBox, error) {
+	if len(b) < 32 {
+		return nil, fmt.Errorf("pssh box too short: %d bytes", len(b))
+	}
+	if size := binary.BigEndian.Uint32(b); size != uint32(len(b)) {
+		return nil, fmt.Errorf("pssh box size %d doesn't match data size %d", size, len(b))
+	}
+	if typ := string(b[4:8]); typ != "pssh" {
+		return nil, fmt.Errorf("unexpected box type %q", typ)
+	}
+
+	res := &PSSHBox{
+		Version:  b[8],
+		Flags:    binary.BigEndian.Uint32(b[8:12]) & 0xFFFFFF,
+		SystemID: formatUUID(b[12:28]),
+	}
+	rest := b[28:]
+	if res.Version > 1 {
+		return nil, fmt.Errorf("unsupported pssh box version %d", res.Version)
+	}
+	if res.Version == 1 {
+		count := binary.BigEndian.Uint32(rest)
+		rest = rest[4:]
+		if uint64(len(rest)) < uint64(count)*16+4 {
+			return nil, fmt.Errorf("pssh box too short for %d KIDs", count)
+		}
+		for i := uint32(0); i < count; i++ {
+			res.KIDs = append(res.KIDs, formatUUID(rest[:16]))
+			rest = rest[16:]
+		}
+	}
+	size := binary.BigEndian.Uint32(rest)
+	rest = rest[4:]
+	if size != uint32(len(rest)) {
+		return nil, fmt.Errorf("pssh data size %d doesn't match remaining %d bytes", size, len(rest))
+	}
+	res.Data = rest
+	return res, nil
+}
+
+// formatUUID formats 16 bytes as lowercase UUID with dashes.
+func formatUUID(b []byte) string {
+	s := hex.EncodeToString(b)
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
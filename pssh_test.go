@@ -0,0 +1,50 @@
+package mpd
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPsshBox(t *testing.T) {
+	m := decodeFixture(t, "fixture_elemental_delta_vod_multi_drm.mpd")
+	cps := m.Period[0].AdaptationSets[0].Representations[0].ContentProtections
+
+	box, err := cps[1].Pssh.Box()
+	require.NoError(t, err)
+	require.Equal(t, &PSSHBox{SystemID: WidevineSystemID, Data: []byte{}}, box)
+
+	box, err = cps[2].Pssh.Box()
+	require.NoError(t, err)
+	require.Equal(t, PlayReadySystemID, box.SystemID)
+	require.Len(t, box.Data, 518)
+
+	_, err = (&Pssh{Value: stringPtr("!")}).Box()
+	require.Error(t, err)
+	_, err = (*Pssh)(nil).Box()
+	require.EqualError(t, err, "empty pssh")
+}
+
+func TestParsePSSHBox(t *testing.T) {
+	b, err := hex.DecodeString("00000048" + "70737368" + "01000000" + "1077efecc0b24d02ace33c1e52e2fb4b" +
+		"00000002" + "10000000100010001000100000000001" + "20000000200020002000200000000002" +
+		"00000004" + "01020304")
+	require.NoError(t, err)
+	box, err := ParsePSSHBox(b)
+	require.NoError(t, err)
+	require.Equal(t, &PSSHBox{
+		Version:  1,
+		SystemID: "1077efec-c0b2-4d02-ace3-3c1e52e2fb4b",
+		KIDs:     []string{"10000000-1000-1000-1000-100000000001", "20000000-2000-2000-2000-200000000002"},
+		Data:     []byte{1, 2, 3, 4},
+	}, box)
+
+	_, err = ParsePSSHBox(b[:40])
+	require.EqualError(t, err, "pssh box size 72 doesn't match data size 40")
+	b[0x1f] = 5
+	_, err = ParsePSSHBox(b)
+	require.EqualError(t, err, "pssh box too short for 5 KIDs")
+	_, err = ParsePSSHBox(b[:8])
+	require.EqualError(t, err, "pssh box too short: 8 bytes")
+}
@@ -0,0 +1,117 @@
+package mpd
+
+import (
+	"strconv"
+	"strings"
+)
+
+// IDMapper returns new id for the given one.
+type IDMapper func(id string) string
+
+// MapIDs returns IDMapper which renames ids according to mapping, ids missing in mapping are kept as is.
+func MapIDs(mapping map[string]string) IDMapper {
+	return func(id string) string {
+		if newID, ok := mapping[id]; ok {
+			return newID
+		}
+		return id
+	}
+}
+
+// TemplateIDs returns IDMapper which renames ids according to template,
+// "$ID$" identifier in template is replaced with the original id (e.g. "cdn2-$ID$").
+func TemplateIDs(template string) IDMapper {
+	return func(id string) string {
+		return strings.ReplaceAll(template, "$ID$", id)
+	}
+}
+
+// RemapIDs renames Representation and AdaptationSet ids, nil mapper leaves corresponding ids untouched.
+// Media, index, initialization and bitstreamSwitching templates referencing $RepresentationID$ are rewritten to keep
+// the original segment URLs; templates inherited from AdaptationSet or Period are copied to renamed Representation
+// SegmentTemplate (which is created if needed) before rewriting. dependencyId and associationId references are renamed too,
+// as well as Preselection components and Subset@contains referring to AdaptationSets
+// (the latter only when AdaptationSets are renamed to numeric ids, as required by SubsetType).
+func (m *MPD) RemapIDs(representations, adaptationSets IDMapper) {
+	for pi := range m.Period {
+		p := &m.Period[pi]
+		renamed := map[string]string{}
+		for _, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			if adaptationSets != nil && as.ID != nil {
+				id := adaptationSets(*as.ID)
+				renamed[*as.ID] = id
+				as.ID = &id
+			}
+			if representations == nil {
+				continue
+			}
+
+			for i := range as.Representations {
+				r := &as.Representations[i]
+				remapVector(r.DependencyID, representations)
+				remapVector(r.AssociationID, representations)
+				if r.ID == nil {
+					continue
+				}
+				oldID := *r.ID
+				id := representations(oldID)
+				if id == oldID {
+					continue
+				}
+				effective := p.EffectiveSegmentTemplate(as, r)
+				r.ID = &id
+				if effective == nil {
+					continue
+				}
+				for _, attr := range []func(st *SegmentTemplate) **string{
+					func(st *SegmentTemplate) **string { return &st.Media },
+					func(st *SegmentTemplate) **string { return &st.Index },
+					func(st *SegmentTemplate) **string { return &st.Initialization },
+					func(st *SegmentTemplate) **string { return &st.BitstreamSwitching },
+				} {
+					template := *attr(effective)
+					if template == nil || !strings.Contains(*template, "$RepresentationID$") {
+						continue
+					}
+					if r.SegmentTemplate == nil {
+						r.SegmentTemplate = &SegmentTemplate{}
+					}
+					*attr(r.SegmentTemplate) = replaceRepresentationID(template, oldID)
+				}
+			}
+		}
+		for _, ps := range p.Preselections {
+			for i, id := range ps.PreselectionComponents {
+				if newID, ok := renamed[id]; ok {
+					ps.PreselectionComponents[i] = newID
+				}
+			}
+		}
+		for _, ss := range p.Subsets {
+			for i, id := range ss.Contains {
+				if newID, err := strconv.ParseUint(renamed[strconv.FormatUint(id, 10)], 10, 64); err == nil {
+					ss.Contains[i] = newID
+				}
+			}
+		}
+	}
+}
+
+// remapVector renames ids referenced by v in place.
+func remapVector(v StringVector, mapper IDMapper) {
+	for i, id := range v {
+		v[i] = mapper(id)
+	}
+}
+
+// replaceRepresentationID substitutes $RepresentationID$ identifier in template with id.
+func replaceRepresentationID(template *string, id string) *string {
+	if template == nil {
+		return nil
+	}
+	res := strings.ReplaceAll(*template, "$RepresentationID$", strings.ReplaceAll(id, "$", "$$"))
+	return &res
+}
@@ -0,0 +1,79 @@
+package mpd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemapIDs(t *testing.T) {
+	m := decodeFixture(t, "fixture_flussonic_live.mpd")
+	m.Period[0].AdaptationSets[0].ID = stringPtr("1")
+	m.RemapIDs(MapIDs(map[string]string{"tracks-v1": "v1", "tracks-a1": "a$1"}), TemplateIDs("cdn2-$ID$"))
+
+	as := m.Period[0].AdaptationSets
+	require.Equal(t, "cdn2-1", *as[0].ID)
+	require.Nil(t, as[1].ID)
+
+	r := as[0].Representations[0]
+	require.Equal(t, "v1", *r.ID)
+	require.Equal(t, "tracks-v1/seg-1631853774-$Number$.m4v?t=$Time$", *r.SegmentTemplate.Media)
+	require.Equal(t, "tracks-v1/init.m4v", *r.SegmentTemplate.Initialization)
+
+	r = as[0].Representations[1]
+	require.Equal(t, "tracks-v2", *r.ID)
+	require.Equal(t, "$RepresentationID$/init.m4v", *r.SegmentTemplate.Initialization)
+
+	r = as[1].Representations[0]
+	require.Equal(t, "a$1", *r.ID)
+	require.Equal(t, "tracks-a1/init.m4v", *r.SegmentTemplate.Initialization)
+
+	m = decodeFixture(t, "fixture_dependent_representations.mpd")
+	m.RemapIDs(TemplateIDs("cdn2-$ID$"), nil)
+	as = m.Period[0].AdaptationSets
+	require.Equal(t, StringVector{"cdn2-bl"}, as[0].Representations[1].DependencyID)
+	require.Equal(t, StringVector{"cdn2-bl", "cdn2-el"}, as[2].Representations[0].AssociationID)
+	require.Equal(t, StringVector{"cdsc", "cdsc"}, as[2].Representations[0].AssociationType)
+
+	m = decodeFixture(t, "fixture_preselection.mpd")
+	m.RemapIDs(nil, MapIDs(map[string]string{"2": "commentary"}))
+	require.Equal(t, StringVector{"1"}, m.Period[0].Preselections[0].PreselectionComponents)
+	require.Equal(t, StringVector{"1", "commentary"}, m.Period[0].Preselections[1].PreselectionComponents)
+
+	m = decodeFixture(t, "fixture_subset.mpd")
+	m.RemapIDs(nil, MapIDs(map[string]string{"1": "10", "4": "surround"}))
+	require.Equal(t, UIntVector{10, 3}, m.Period[0].Subsets[0].Contains)
+	require.Equal(t, UIntVector{2, 4}, m.Period[0].Subsets[1].Contains)
+	require.Equal(t, "hdr", *m.Period[0].Subsets[1].ID)
+
+	m = decodeFixture(t, "fixture_segment_template_duration.mpd")
+	m.RemapIDs(TemplateIDs("cdn2-$ID$"), nil)
+	st := m.Period[0].AdaptationSets[0].Representations[0].SegmentTemplate
+	require.Equal(t, "v1/$Number$.m4s", *st.Media)
+	require.Equal(t, "v1/$Number$.sidx", *st.Index)
+	require.Equal(t, "v1/init.mp4", *st.Initialization)
+	require.Equal(t, "v1/switch.mp4", *st.BitstreamSwitching)
+}
+
+func TestRemapIDsInheritedTemplate(t *testing.T) {
+	m := decodeFixture(t, "fixture_period_template.mpd")
+	before, err := m.SegmentURLs("")
+	require.NoError(t, err)
+
+	m.RemapIDs(MapIDs(map[string]string{"v1": "video"}), nil)
+	p := m.Period[0]
+	require.Equal(t, "$RepresentationID$/$Number$.m4s", *p.SegmentTemplate.Media)
+	require.Nil(t, p.AdaptationSets[0].SegmentTemplate.Media)
+	require.Equal(t, &SegmentTemplate{
+		Media:          stringPtr("v1/$Number$.m4s"),
+		Initialization: stringPtr("v1/init.mp4"),
+	}, p.AdaptationSets[0].Representations[0].SegmentTemplate)
+	require.Equal(t, &SegmentTemplate{StartNumber: uint64Ptr(0)}, p.AdaptationSets[1].Representations[0].SegmentTemplate)
+
+	after, err := m.SegmentURLs("")
+	require.NoError(t, err)
+	require.Len(t, after, len(before))
+	for i := range before {
+		require.Equal(t, before[i].URL, after[i].URL)
+	}
+}
@@ -0,0 +1,207 @@
+package mpd
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// reportTemplate renders self-contained HTML page, it must not reference any external resources.
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>MPD report{{with .ID}} {{.}}{{end}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+th { background: #f0f0f0; }
+.error { color: #b00000; }
+.warning { color: #b07000; }
+</style>
+</head>
+<body>
+<h1>MPD report{{with .ID}} {{.}}{{end}}</h1>
+<table>
+{{range .Attributes}}<tr><th>{{.Name}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>
+
+<h2>Validation</h2>
+{{if .Findings}}<table>
+<tr><th>Severity</th><th>Path</th><th>Message</th></tr>
+{{range .Findings}}<tr class="{{.Severity}}"><td>{{.Severity}}</td><td>{{.Path}}</td><td>{{.Message}}</td></tr>
+{{end}}</table>
+{{else}}<p>No problems found.</p>
+{{end}}
+<h2>DRM</h2>
+{{if .DRM}}<table>
+<tr><th>schemeIdUri</th><th>value</th><th>default_KID</th><th>pssh</th><th>Count</th></tr>
+{{range .DRM}}<tr><td>{{.SchemeIDURI}}</td><td>{{.Value}}</td><td>{{.DefaultKID}}</td><td>{{if .Pssh}}yes{{else}}no{{end}}</td><td>{{.Count}}</td></tr>
+{{end}}</table>
+{{else}}<p>No ContentProtection elements.</p>
+{{end}}
+<h2>Ladder</h2>
+{{range .Periods}}<h3>Period {{.Title}}</h3>
+{{range .AdaptationSets}}<h4>AdaptationSet {{.Title}}</h4>
+<table>
+<tr><th>id</th><th>bandwidth</th><th>resolution</th><th>frameRate</th><th>codecs</th><th>segments</th></tr>
+{{range .Representations}}<tr><td>{{.ID}}</td><td>{{.Bandwidth}}</td><td>{{.Resolution}}</td><td>{{.FrameRate}}</td><td>{{.Codecs}}</td><td>{{.Segments}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}
+<h2>Timeline</h2>
+<p>Segment start and duration in seconds relative to the Period start.</p>
+<script type="application/json" id="timeline-data">{{.Timeline}}</script>
+</body>
+</html>
+`))
+
+type reportData struct {
+	ID         string
+	Attributes []reportAttribute
+	Findings   []Finding
+	DRM        []*reportDRM
+	Periods    []reportPeriod
+	Timeline   []reportTimeline
+}
+
+type reportAttribute struct {
+	Name  string
+	Value string
+}
+
+type reportDRM struct {
+	SchemeIDURI string
+	Value       string
+	DefaultKID  string
+	Pssh        bool
+	Count       int
+}
+
+type reportPeriod struct {
+	Title          string
+	AdaptationSets []reportAdaptationSet
+}
+
+type reportAdaptationSet struct {
+	Title           string
+	Representations []reportRepresentation
+}
+
+type reportRepresentation struct {
+	ID         string
+	Bandwidth  string
+	Resolution string
+	FrameRate  string
+	Codecs     string
+	Segments   int
+}
+
+type reportTimeline struct {
+	Period         string       `json:"period"`
+	Representation string       `json:"representation"`
+	Segments       [][2]float64 `json:"segments"`
+}
+
+// HTMLReport renders self-contained HTML page with manifest summary:
+// bitrate ladder tables, timeline chart data, DRM summary and Validate findings.
+func (m *MPD) HTMLReport() ([]byte, error) {
+	data := &reportData{
+		ID:       stringValue(m.ID),
+		Findings: m.Validate(),
+	}
+	for _, a := range []reportAttribute{
+		{"type", stringValue((*string)(m.Type))},
+		{"profiles", string(m.Profiles)},
+		{"availabilityStartTime", dateTimeValue(m.AvailabilityStartTime)},
+		{"availabilityEndTime", dateTimeValue(m.AvailabilityEndTime)},
+		{"publishTime", dateTimeValue(m.PublishTime)},
+		{"mediaPresentationDuration", stringValue(m.MediaPresentationDuration)},
+		{"minimumUpdatePeriod", stringValue(m.MinimumUpdatePeriod)},
+		{"minBufferTime", stringValue(m.MinBufferTime)},
+		{"suggestedPresentationDelay", stringValue(m.SuggestedPresentationDelay)},
+		{"timeShiftBufferDepth", stringValue(m.TimeShiftBufferDepth)},
+	} {
+		if a.Value != "" {
+			data.Attributes = append(data.Attributes, a)
+		}
+	}
+
+	drm := make(map[string]*reportDRM)
+	addDRM := func(ds []DRMDescriptor) {
+		for _, d := range ds {
+			rd := &reportDRM{
+				SchemeIDURI: stringValue(d.SchemeIDURI),
+				Value:       stringValue(d.Value),
+				DefaultKID:  stringValue(d.CencDefaultKID),
+				Pssh:        d.Pssh != nil,
+			}
+			key := fmt.Sprintf("%s\x00%s\x00%s\x00%t", rd.SchemeIDURI, rd.Value, rd.DefaultKID, rd.Pssh)
+			if drm[key] == nil {
+				drm[key] = rd
+				data.DRM = append(data.DRM, rd)
+			}
+			drm[key].Count++
+		}
+	}
+
+	for pi, p := range m.Period {
+		period := reportPeriod{Title: periodName(pi, p.ID)}
+		periodDuration, _, _ := m.PeriodDuration(pi)
+		for ai, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			addDRM(as.ContentProtections)
+			title := []string{fmt.Sprint(ai)}
+			for _, s := range []string{as.MimeType, stringValue(as.Lang)} {
+				if s != "" {
+					title = append(title, s)
+				}
+			}
+			adaptationSet := reportAdaptationSet{Title: strings.Join(title, " ")}
+
+			for ri := range as.Representations {
+				r := &as.Representations[ri]
+				addDRM(r.ContentProtections)
+				rep := reportRepresentation{
+					ID:        stringValue(r.ID),
+					FrameRate: stringValue((*string)(r.FrameRate)),
+					Codecs:    stringValue(r.Codecs),
+				}
+				if rep.Codecs == "" {
+					rep.Codecs = stringValue(as.Codecs)
+				}
+				if r.Bandwidth != nil {
+					rep.Bandwidth = fmt.Sprint(*r.Bandwidth)
+				}
+				if r.Width != nil && r.Height != nil {
+					rep.Resolution = fmt.Sprintf("%dx%d", *r.Width, *r.Height)
+				}
+
+				if st := p.EffectiveSegmentTemplate(as, r); st != nil {
+					timeline := reportTimeline{Period: period.Title, Representation: rep.ID, Segments: [][2]float64{}}
+					timescale := st.EffectiveTimescale()
+					pto := st.EffectivePresentationTimeOffset()
+					for _, s := range st.SegmentsWithin(periodDuration) {
+						start := (float64(s.Time) - float64(pto)) / float64(timescale)
+						timeline.Segments = append(timeline.Segments, [2]float64{start, float64(s.Duration) / float64(timescale)})
+					}
+					rep.Segments = len(timeline.Segments)
+					data.Timeline = append(data.Timeline, timeline)
+				}
+
+				adaptationSet.Representations = append(adaptationSet.Representations, rep)
+			}
+			period.AdaptationSets = append(period.AdaptationSets, adaptationSet)
+		}
+		data.Periods = append(data.Periods, period)
+	}
+
+	b := new(bytes.Buffer)
+	if err := reportTemplate.Execute(b, data); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,29 @@
+package mpd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTMLReport(t *testing.T) {
+	m := decodeFixture(t, "fixture_elemental_delta_vod_multi_drm.mpd")
+	b, err := m.HTMLReport()
+	require.NoError(t, err)
+
+	report := string(b)
+	require.Contains(t, report, "<title>MPD report 1135124</title>")
+	require.Contains(t, report, "<p>No problems found.</p>")
+	require.Contains(t, report, "<tr><td>urn:uuid:9a04f079-9840-4286-ab92-e65be0885f95</td><td>MSPR 2.0</td><td></td><td>yes</td>")
+	require.Contains(t, report, "<tr><td>1</td><td>400000</td><td>640x360</td><td>25/1</td><td>avc1.4D401E</td><td>18</td></tr>")
+	require.Contains(t, report, `{"period":"1","representation":"1","segments":[[0,8],[8,8],`)
+	require.NotContains(t, report, "http://")
+	require.NotContains(t, report, "https://")
+}
+
+func TestHTMLReportFindings(t *testing.T) {
+	m := &MPD{Period: []Period{{}}}
+	b, err := m.HTMLReport()
+	require.NoError(t, err)
+	require.Contains(t, string(b), `<tr class="error"><td>error</td><td>MPD</td><td>missing profiles attribute</td></tr>`)
+}
@@ -0,0 +1,234 @@
+package mpd
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	copyobj "github.com/mc2soft/mpd/utils"
+)
+
+// RebaseTimeline rebases MPD of a backup encoder onto the timeline of primary MPD, so clients survive
+// origin switch without a timestamp jump. availabilityStartTime, Period@start and Period@id are taken from primary
+// (Periods are matched by index), presentationTimeOffset of every SegmentTemplate, SegmentList, SegmentBase and
+// EventStream is adjusted to keep segments' and events' wall-clock time; startNumber of SegmentTemplate with
+// @duration and without SegmentTimeline is adjusted too, which fails if the shift is not a whole number of segments.
+// SegmentList with @duration and without SegmentTimeline can't be shifted. Inherited attributes are adjusted at
+// the level where they're defined, which fails if Representations sharing them need different adjustments
+// (their timescales differ). Media times ($Time$, S@t) are untouched, so segment URLs remain valid.
+// MPD is not modified if error is returned.
+func (m *MPD) RebaseTimeline(primary *MPD) error {
+	if m.AvailabilityStartTime == nil || primary.AvailabilityStartTime == nil {
+		return fmt.Errorf("both MPDs must have availabilityStartTime")
+	}
+	ast := m.AvailabilityStartTime.Time
+	primaryAST := primary.AvailabilityStartTime.Time
+
+	// new values of presentationTimeOffset and startNumber attributes
+	changes := map[**uint64]uint64{}
+	change := func(attr **uint64, v uint64) bool {
+		if prev, ok := changes[attr]; ok && prev != v {
+			return false
+		}
+		changes[attr] = v
+		return true
+	}
+	for pi := range m.Period {
+		p := &m.Period[pi]
+		start, err := m.PeriodStart(pi)
+		if err != nil {
+			return err
+		}
+		newStart := start
+		if pi < len(primary.Period) {
+			if newStart, err = primary.PeriodStart(pi); err != nil {
+				return err
+			}
+		}
+		// presentation times should be increased by delta
+		delta := ast.Sub(primaryAST) + start - newStart
+
+		for i := range p.EventStreams {
+			es := &p.EventStreams[i]
+			timescale := uint64(1)
+			if es.Timescale != nil {
+				timescale = *es.Timescale
+			}
+			pto, ok := shiftTicks(es.PresentationTimeOffset, delta, timescale)
+			if !ok {
+				return fmt.Errorf("Period %d EventStream %q: presentationTimeOffset %d can't be shifted by %s",
+					pi, stringValue(es.SchemeIDURI), uint64Value(es.PresentationTimeOffset), -delta)
+			}
+			change(&es.PresentationTimeOffset, pto)
+		}
+
+		for _, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			for ri := range as.Representations {
+				r := &as.Representations[ri]
+				path := fmt.Sprintf("Period %d Representation %q", pi, stringValue(r.ID))
+				inconsistent := fmt.Errorf("%s: inherited presentationTimeOffset can't be shifted "+
+					"consistently for all Representations", path)
+
+				if st := p.EffectiveSegmentTemplate(as, r); st != nil {
+					timescale := st.EffectiveTimescale()
+					pto, ok := shiftTicks(st.PresentationTimeOffset, delta, timescale)
+					if !ok {
+						return fmt.Errorf("%s: presentationTimeOffset %d can't be shifted by %s",
+							path, st.EffectivePresentationTimeOffset(), -delta)
+					}
+					if !change(&templateOwner(p, as, r, presentationTimeOffsetSet).PresentationTimeOffset, pto) {
+						return inconsistent
+					}
+					if st.Duration == nil || *st.Duration == 0 || len(st.SegmentTimelineS) > 0 {
+						continue
+					}
+
+					// segments are numbered from the Period start, so the number of the first one is shifted
+					ticks := int64(math.Round(delta.Seconds() * float64(timescale)))
+					if ticks%int64(*st.Duration) != 0 {
+						return fmt.Errorf("%s: shift %s is not a whole number of segments of duration %d/%d",
+							path, -delta, *st.Duration, timescale)
+					}
+					number := int64(st.EffectiveStartNumber()) - ticks/int64(*st.Duration)
+					if number < 0 {
+						return fmt.Errorf("%s: startNumber %d can't be shifted by %s", path, st.EffectiveStartNumber(), -delta)
+					}
+					if !change(&templateOwner(p, as, r, startNumberSet).StartNumber, uint64(number)) {
+						return fmt.Errorf("%s: inherited startNumber can't be shifted consistently for all Representations",
+							path)
+					}
+					continue
+				}
+
+				if sl := p.EffectiveSegmentList(as, r); sl != nil {
+					if sl.Duration != nil && len(sl.SegmentTimelineS) == 0 && delta != 0 {
+						return fmt.Errorf("%s: SegmentList without SegmentTimeline can't be shifted by %s", path, -delta)
+					}
+					pto, ok := shiftTicks(sl.PresentationTimeOffset, delta, sl.EffectiveTimescale())
+					if !ok {
+						return fmt.Errorf("%s: presentationTimeOffset %d can't be shifted by %s",
+							path, sl.EffectivePresentationTimeOffset(), -delta)
+					}
+					if !change(segmentListPTO(p, as, r), pto) {
+						return inconsistent
+					}
+					continue
+				}
+
+				if sb := p.EffectiveSegmentBase(as, r); sb != nil {
+					pto, ok := shiftTicks(sb.PresentationTimeOffset, delta, sb.EffectiveTimescale())
+					if !ok {
+						return fmt.Errorf("%s: presentationTimeOffset %d can't be shifted by %s",
+							path, sb.EffectivePresentationTimeOffset(), -delta)
+					}
+					if !change(segmentBasePTO(p, as, r), pto) {
+						return inconsistent
+					}
+				}
+			}
+		}
+	}
+
+	for attr, v := range changes {
+		if *attr == nil && v == 0 {
+			continue
+		}
+		v := v
+		*attr = &v
+	}
+	m.AvailabilityStartTime = copyDateTime(primary.AvailabilityStartTime)
+	for pi := range m.Period {
+		if pi >= len(primary.Period) {
+			break
+		}
+		pp := primary.Period[pi]
+		if pp.ID != nil {
+			m.Period[pi].ID = copyobj.String(pp.ID)
+		}
+		if pp.Start != nil {
+			m.Period[pi].Start = copyobj.String(pp.Start)
+		} else if m.Period[pi].Start != nil {
+			// primary's start is derived from previous Periods, make it explicit
+			start, _ := primary.PeriodStart(pi)
+			m.Period[pi].Start = Duration(start).Attr()
+		}
+	}
+	return nil
+}
+
+// shiftTicks returns presentationTimeOffset pto (0 if nil) decreased by delta in timescale units,
+// ok is false if the result is out of range.
+func shiftTicks(pto *uint64, delta time.Duration, timescale uint64) (uint64, bool) {
+	res := float64(uint64Value(pto)) - math.Round(delta.Seconds()*float64(timescale))
+	if res < 0 || res > math.MaxUint64 {
+		return 0, false
+	}
+	return uint64(res), true
+}
+
+func uint64Value(v *uint64) uint64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+func presentationTimeOffsetSet(st *SegmentTemplate) bool { return st.PresentationTimeOffset != nil }
+
+func startNumberSet(st *SegmentTemplate) bool { return st.StartNumber != nil }
+
+// templateOwner returns the SegmentTemplate defining the attribute of Representation r reported by set,
+// or the deepest SegmentTemplate if it's not set at any level.
+func templateOwner(p *Period, as *AdaptationSet, r *Representation, set func(*SegmentTemplate) bool) *SegmentTemplate {
+	var deepest *SegmentTemplate
+	for _, st := range []*SegmentTemplate{r.SegmentTemplate, as.SegmentTemplate, p.SegmentTemplate} {
+		if st == nil {
+			continue
+		}
+		if set(st) {
+			return st
+		}
+		if deepest == nil {
+			deepest = st
+		}
+	}
+	return deepest
+}
+
+// segmentListPTO returns presentationTimeOffset attribute of SegmentList defining it for Representation r,
+// or of the deepest SegmentList if it's not set at any level.
+func segmentListPTO(p *Period, as *AdaptationSet, r *Representation) **uint64 {
+	var deepest **uint64
+	for _, sl := range []*SegmentList{r.SegmentList, as.SegmentList, p.SegmentList} {
+		if sl == nil {
+			continue
+		}
+		if sl.PresentationTimeOffset != nil {
+			return &sl.PresentationTimeOffset
+		}
+		if deepest == nil {
+			deepest = &sl.PresentationTimeOffset
+		}
+	}
+	return deepest
+}
+
+// segmentBasePTO is like segmentListPTO, but for SegmentBase.
+func segmentBasePTO(p *Period, as *AdaptationSet, r *Representation) **uint64 {
+	var deepest **uint64
+	for _, sb := range []*SegmentBase{r.SegmentBase, as.SegmentBase, p.SegmentBase} {
+		if sb == nil {
+			continue
+		}
+		if sb.PresentationTimeOffset != nil {
+			return &sb.PresentationTimeOffset
+		}
+		if deepest == nil {
+			deepest = &sb.PresentationTimeOffset
+		}
+	}
+	return deepest
+}
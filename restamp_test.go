@@ -0,0 +1,132 @@
+package mpd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebaseTimeline(t *testing.T) {
+	primary := decodeFixture(t, "fixture_flussonic_live.mpd")
+	backup := decodeFixture(t, "fixture_flussonic_live.mpd")
+	backup.AvailabilityStartTime = dateTimePtr("2021-09-17T04:42:44Z")
+	backup.Period[0].ID = stringPtr("backup")
+	pto := uint64(20000)
+	for _, as := range backup.Period[0].AdaptationSets {
+		for _, r := range as.Representations {
+			r.SegmentTemplate.PresentationTimeOffset = &pto
+		}
+	}
+
+	before, err := backup.Timelines()
+	require.NoError(t, err)
+
+	require.NoError(t, backup.RebaseTimeline(primary))
+	require.Equal(t, "2021-09-17T04:42:54Z", backup.AvailabilityStartTime.String())
+	require.Equal(t, "1631853774", *backup.Period[0].ID)
+	st := backup.Period[0].AdaptationSets[0].Representations[0].SegmentTemplate
+	require.Equal(t, uint64(30000), *st.PresentationTimeOffset)
+	require.Equal(t, uint64(380620753), *st.SegmentTimelineS[0].T)
+
+	after, err := backup.Timelines()
+	require.NoError(t, err)
+	require.Equal(t, before[0].Segments[0].AvailabilityStart, after[0].Segments[0].AvailabilityStart)
+	require.Equal(t, before[0].Segments[0].Start-10, after[0].Segments[0].Start)
+
+	// presentationTimeOffset can't be negative
+	backup.AvailabilityStartTime = dateTimePtr("2021-09-17T04:43:54Z")
+	err = backup.RebaseTimeline(primary)
+	require.EqualError(t, err, `Period 0 Representation "tracks-v1": presentationTimeOffset 30000 can't be shifted by -1m0s`)
+	require.Equal(t, uint64(30000), *st.PresentationTimeOffset)
+	require.Equal(t, "2021-09-17T04:43:54Z", backup.AvailabilityStartTime.String())
+}
+
+func TestRebaseTimelineInheritedTemplate(t *testing.T) {
+	primary := decodeFixture(t, "fixture_adaptation_set_template.mpd")
+	primary.AvailabilityStartTime = dateTimePtr("2021-01-01T00:00:10Z")
+	backup := decodeFixture(t, "fixture_adaptation_set_template.mpd")
+	backup.AvailabilityStartTime = dateTimePtr("2021-01-01T00:00:00Z")
+	before, err := backup.Timelines()
+	require.NoError(t, err)
+
+	require.NoError(t, backup.RebaseTimeline(primary))
+	as := backup.Period[0].AdaptationSets[0]
+	require.Equal(t, uint64(900000), *as.SegmentTemplate.PresentationTimeOffset)
+	require.Nil(t, as.Representations[0].SegmentTemplate)
+	require.Nil(t, as.Representations[1].SegmentTemplate)
+	after, err := backup.Timelines()
+	require.NoError(t, err)
+	for i := range before {
+		require.Equal(t, before[i].Segments[0].Start-10, after[i].Segments[0].Start)
+	}
+
+	// Representations sharing presentationTimeOffset need different shifts
+	backup = decodeFixture(t, "fixture_adaptation_set_template.mpd")
+	backup.AvailabilityStartTime = dateTimePtr("2021-01-01T00:00:00Z")
+	backup.Period[0].AdaptationSets[0].SegmentTemplate.PresentationTimeOffset = uint64Ptr(0)
+	backup.Period[0].AdaptationSets[0].Representations[1].SegmentTemplate = &SegmentTemplate{Timescale: uint64Ptr(1000)}
+	err = backup.RebaseTimeline(primary)
+	require.EqualError(t, err, `Period 0 Representation "1080p": inherited presentationTimeOffset can't be shifted consistently for all Representations`)
+	require.Equal(t, uint64(0), *backup.Period[0].AdaptationSets[0].SegmentTemplate.PresentationTimeOffset)
+	require.Nil(t, backup.Period[0].AdaptationSets[0].Representations[1].SegmentTemplate.PresentationTimeOffset)
+
+	// own template without presentationTimeOffset gets its own
+	backup.Period[0].AdaptationSets[0].SegmentTemplate.PresentationTimeOffset = nil
+	require.NoError(t, backup.RebaseTimeline(primary))
+	require.Equal(t, uint64(900000), *backup.Period[0].AdaptationSets[0].SegmentTemplate.PresentationTimeOffset)
+	require.Equal(t, uint64(10000), *backup.Period[0].AdaptationSets[0].Representations[1].SegmentTemplate.PresentationTimeOffset)
+}
+
+func TestRebaseTimelineAddressingModes(t *testing.T) {
+	primary := decodeFixture(t, "fixture_segment_template_duration.mpd")
+	primary.AvailabilityStartTime = dateTimePtr("2021-01-01T00:00:10Z")
+	newBackup := func() *MPD {
+		backup := decodeFixture(t, "fixture_segment_template_duration.mpd")
+		p := &backup.Period[0]
+		p.EventStreams = []EventStream{{
+			SchemeIDURI: stringPtr(SCTE35SchemeXMLBin),
+			Timescale:   uint64Ptr(1000),
+			Events:      []Event{{PresentationTime: uint64Ptr(100)}},
+		}}
+		p.AdaptationSets = append(p.AdaptationSets, &AdaptationSet{
+			MimeType:    "audio/mp4",
+			SegmentBase: &SegmentBase{Timescale: uint64Ptr(48000), IndexRange: stringPtr("0-100")},
+			Representations: []Representation{
+				{ID: stringPtr("a1"), BaseURL: []BaseURL{{Value: "a1.mp4"}}},
+				{ID: stringPtr("a2"), BaseURL: []BaseURL{{Value: "a2.mp4"}}},
+			},
+		})
+		return backup
+	}
+
+	backup := newBackup()
+	require.NoError(t, backup.RebaseTimeline(primary))
+	p := backup.Period[0]
+	st := p.AdaptationSets[0].Representations[0].SegmentTemplate
+	// segments are 2s long, the one available 10s after availabilityStartTime keeps its number
+	require.Equal(t, uint64(10000), *st.PresentationTimeOffset)
+	require.Equal(t, uint64(6), *st.StartNumber)
+	require.Equal(t, uint64(10000), *p.EventStreams[0].PresentationTimeOffset)
+	require.Equal(t, uint64(100), *p.EventStreams[0].Events[0].PresentationTime)
+	require.Equal(t, uint64(480000), *p.AdaptationSets[1].SegmentBase.PresentationTimeOffset)
+
+	// shift is not a whole number of segments
+	primary.AvailabilityStartTime = dateTimePtr("2021-01-01T00:00:11Z")
+	backup = newBackup()
+	err := backup.RebaseTimeline(primary)
+	require.EqualError(t, err, `Period 0 Representation "v1": shift 11s is not a whole number of segments of duration 2000/1000`)
+	require.Nil(t, backup.Period[0].EventStreams[0].PresentationTimeOffset)
+	require.Equal(t, uint64(1), *backup.Period[0].AdaptationSets[0].Representations[0].SegmentTemplate.StartNumber)
+
+	// SegmentList segments are positioned by their index
+	primary.AvailabilityStartTime = dateTimePtr("2021-01-01T00:00:10Z")
+	backup = newBackup()
+	backup.Period[0].AdaptationSets[1].SegmentBase = nil
+	backup.Period[0].AdaptationSets[1].SegmentList = &SegmentList{
+		Timescale:   uint64Ptr(1000),
+		Duration:    uint64Ptr(2000),
+		SegmentURLs: []SegmentListURL{{Media: stringPtr("1.m4s")}},
+	}
+	err = backup.RebaseTimeline(primary)
+	require.EqualError(t, err, `Period 0 Representation "a1": SegmentList without SegmentTimeline can't be shifted by 10s`)
+}
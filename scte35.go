@@ -0,0 +1,276 @@
+package mpd
+
+import (
+	"encoding/xml"
+
+	copyobj "github.com/mc2soft/mpd/utils"
+)
+
+// SCTE35Namespace is a namespace of SCTE 35 splice information elements carried by Event (SCTE 214-1).
+const SCTE35Namespace = "http://www.scte.org/schemas/35/2016"
+
+// SCTE-35 EventStream schemes defined by SCTE 214-1.
+const (
+	SCTE35SchemeXML    = "urn:scte:scte35:2013:xml"
+	SCTE35SchemeXMLBin = "urn:scte:scte35:2014:xml+bin"
+)
+
+// SCTE35Signal represents scte35:Signal element: splice information either as XML or as binary.
+type SCTE35Signal struct {
+	SpliceInfoSection *SCTE35SpliceInfoSection `xml:"SpliceInfoSection,omitempty" json:"spliceInfoSection,omitempty"`
+	Binary            *SCTE35Binary            `xml:"Binary,omitempty" json:"binary,omitempty"`
+}
+
+type scte35SignalMarshal struct {
+	SpliceInfoSection *scte35SpliceInfoSectionMarshal `xml:"scte35:SpliceInfoSection,omitempty"`
+	Binary            *SCTE35Binary                   `xml:"scte35:Binary,omitempty"`
+}
+
+// SCTE35Binary represents scte35:Binary element: base64 encoded splice_info_section.
+type SCTE35Binary struct {
+	SignalType *string `xml:"signalType,attr" json:"signalType,omitempty"`
+	Value      string  `xml:",chardata" json:"value,omitempty"`
+}
+
+// SCTE35SpliceInfoSection represents scte35:SpliceInfoSection element.
+type SCTE35SpliceInfoSection struct {
+	SAPType                 *uint64                        `xml:"sapType,attr" json:"sapType,omitempty"`
+	PTSAdjustment           *uint64                        `xml:"ptsAdjustment,attr" json:"ptsAdjustment,omitempty"`
+	ProtocolVersion         *uint64                        `xml:"protocolVersion,attr" json:"protocolVersion,omitempty"`
+	Tier                    *uint64                        `xml:"tier,attr" json:"tier,omitempty"`
+	SpliceInsert            *SCTE35SpliceInsert            `xml:"SpliceInsert,omitempty" json:"spliceInsert,omitempty"`
+	TimeSignal              *SCTE35TimeSignal              `xml:"TimeSignal,omitempty" json:"timeSignal,omitempty"`
+	SegmentationDescriptors []SCTE35SegmentationDescriptor `xml:"SegmentationDescriptor,omitempty" json:"segmentationDescriptors,omitempty"`
+	// Extensions keeps other splice commands and descriptors.
+	Extensions []Extension `xml:",any" json:"extensions,omitempty"`
+}
+
+type scte35SpliceInfoSectionMarshal struct {
+	SAPType                 *uint64                               `xml:"sapType,attr"`
+	PTSAdjustment           *uint64                               `xml:"ptsAdjustment,attr"`
+	ProtocolVersion         *uint64                               `xml:"protocolVersion,attr"`
+	Tier                    *uint64                               `xml:"tier,attr"`
+	SpliceInsert            *scte35SpliceInsertMarshal            `xml:"scte35:SpliceInsert,omitempty"`
+	TimeSignal              *scte35TimeSignalMarshal              `xml:"scte35:TimeSignal,omitempty"`
+	SegmentationDescriptors []scte35SegmentationDescriptorMarshal `xml:"scte35:SegmentationDescriptor,omitempty"`
+	Extensions              []extensionMarshal                    `xml:",any"`
+}
+
+// SCTE35SpliceTime represents scte35:SpliceTime element.
+type SCTE35SpliceTime struct {
+	PTSTime *uint64 `xml:"ptsTime,attr" json:"ptsTime,omitempty"`
+}
+
+// SCTE35TimeSignal represents scte35:TimeSignal splice command.
+type SCTE35TimeSignal struct {
+	SpliceTime *SCTE35SpliceTime `xml:"SpliceTime,omitempty" json:"spliceTime,omitempty"`
+}
+
+type scte35TimeSignalMarshal struct {
+	SpliceTime *SCTE35SpliceTime `xml:"scte35:SpliceTime,omitempty"`
+}
+
+// SCTE35SpliceInsert represents scte35:SpliceInsert splice command.
+type SCTE35SpliceInsert struct {
+	SpliceEventID              *uint64              `xml:"spliceEventId,attr" json:"spliceEventID,omitempty"`
+	SpliceEventCancelIndicator *bool                `xml:"spliceEventCancelIndicator,attr" json:"spliceEventCancelIndicator,omitempty"`
+	OutOfNetworkIndicator      *bool                `xml:"outOfNetworkIndicator,attr" json:"outOfNetworkIndicator,omitempty"`
+	SpliceImmediateFlag        *bool                `xml:"spliceImmediateFlag,attr" json:"spliceImmediateFlag,omitempty"`
+	UniqueProgramID            *uint64              `xml:"uniqueProgramId,attr" json:"uniqueProgramID,omitempty"`
+	AvailNum                   *uint64              `xml:"availNum,attr" json:"availNum,omitempty"`
+	AvailsExpected             *uint64              `xml:"availsExpected,attr" json:"availsExpected,omitempty"`
+	Program                    *SCTE35Program       `xml:"Program,omitempty" json:"program,omitempty"`
+	BreakDuration              *SCTE35BreakDuration `xml:"BreakDuration,omitempty" json:"breakDuration,omitempty"`
+}
+
+type scte35SpliceInsertMarshal struct {
+	SpliceEventID              *uint64               `xml:"spliceEventId,attr"`
+	SpliceEventCancelIndicator *bool                 `xml:"spliceEventCancelIndicator,attr"`
+	OutOfNetworkIndicator      *bool                 `xml:"outOfNetworkIndicator,attr"`
+	SpliceImmediateFlag        *bool                 `xml:"spliceImmediateFlag,attr"`
+	UniqueProgramID            *uint64               `xml:"uniqueProgramId,attr"`
+	AvailNum                   *uint64               `xml:"availNum,attr"`
+	AvailsExpected             *uint64               `xml:"availsExpected,attr"`
+	Program                    *scte35ProgramMarshal `xml:"scte35:Program,omitempty"`
+	BreakDuration              *SCTE35BreakDuration  `xml:"scte35:BreakDuration,omitempty"`
+}
+
+// SCTE35Program represents scte35:Program element of SpliceInsert.
+type SCTE35Program struct {
+	SpliceTime *SCTE35SpliceTime `xml:"SpliceTime,omitempty" json:"spliceTime,omitempty"`
+}
+
+type scte35ProgramMarshal struct {
+	SpliceTime *SCTE35SpliceTime `xml:"scte35:SpliceTime,omitempty"`
+}
+
+// SCTE35BreakDuration represents scte35:BreakDuration element, duration is in 90 kHz ticks.
+type SCTE35BreakDuration struct {
+	AutoReturn *bool   `xml:"autoReturn,attr" json:"autoReturn,omitempty"`
+	Duration   *uint64 `xml:"duration,attr" json:"duration,omitempty"`
+}
+
+// SCTE35SegmentationDescriptor represents scte35:SegmentationDescriptor element.
+type SCTE35SegmentationDescriptor struct {
+	SegmentationEventID              *uint64                     `xml:"segmentationEventId,attr" json:"segmentationEventID,omitempty"`
+	SegmentationEventCancelIndicator *bool                       `xml:"segmentationEventCancelIndicator,attr" json:"segmentationEventCancelIndicator,omitempty"`
+	SegmentationDuration             *uint64                     `xml:"segmentationDuration,attr" json:"segmentationDuration,omitempty"`
+	SegmentationTypeID               *uint64                     `xml:"segmentationTypeId,attr" json:"segmentationTypeID,omitempty"`
+	SegmentNum                       *uint64                     `xml:"segmentNum,attr" json:"segmentNum,omitempty"`
+	SegmentsExpected                 *uint64                     `xml:"segmentsExpected,attr" json:"segmentsExpected,omitempty"`
+	SubSegmentNum                    *uint64                     `xml:"subSegmentNum,attr" json:"subSegmentNum,omitempty"`
+	SubSegmentsExpected              *uint64                     `xml:"subSegmentsExpected,attr" json:"subSegmentsExpected,omitempty"`
+	DeliveryRestrictions             *SCTE35DeliveryRestrictions `xml:"DeliveryRestrictions,omitempty" json:"deliveryRestrictions,omitempty"`
+	SegmentationUpids                []SCTE35SegmentationUpid    `xml:"SegmentationUpid,omitempty" json:"segmentationUpids,omitempty"`
+}
+
+type scte35SegmentationDescriptorMarshal struct {
+	SegmentationEventID              *uint64                     `xml:"segmentationEventId,attr"`
+	SegmentationEventCancelIndicator *bool                       `xml:"segmentationEventCancelIndicator,attr"`
+	SegmentationDuration             *uint64                     `xml:"segmentationDuration,attr"`
+	SegmentationTypeID               *uint64                     `xml:"segmentationTypeId,attr"`
+	SegmentNum                       *uint64                     `xml:"segmentNum,attr"`
+	SegmentsExpected                 *uint64                     `xml:"segmentsExpected,attr"`
+	SubSegmentNum                    *uint64                     `xml:"subSegmentNum,attr"`
+	SubSegmentsExpected              *uint64                     `xml:"subSegmentsExpected,attr"`
+	DeliveryRestrictions             *SCTE35DeliveryRestrictions `xml:"scte35:DeliveryRestrictions,omitempty"`
+	SegmentationUpids                []SCTE35SegmentationUpid    `xml:"scte35:SegmentationUpid,omitempty"`
+}
+
+// SCTE35DeliveryRestrictions represents scte35:DeliveryRestrictions element.
+type SCTE35DeliveryRestrictions struct {
+	WebDeliveryAllowedFlag *bool   `xml:"webDeliveryAllowedFlag,attr" json:"webDeliveryAllowedFlag,omitempty"`
+	NoRegionalBlackoutFlag *bool   `xml:"noRegionalBlackoutFlag,attr" json:"noRegionalBlackoutFlag,omitempty"`
+	ArchiveAllowedFlag     *bool   `xml:"archiveAllowedFlag,attr" json:"archiveAllowedFlag,omitempty"`
+	DeviceRestrictions     *uint64 `xml:"deviceRestrictions,attr" json:"deviceRestrictions,omitempty"`
+}
+
+// SCTE35SegmentationUpid represents scte35:SegmentationUpid element.
+type SCTE35SegmentationUpid struct {
+	SegmentationUpidType   *uint64 `xml:"segmentationUpidType,attr" json:"segmentationUpidType,omitempty"`
+	FormatIdentifier       *uint64 `xml:"formatIdentifier,attr" json:"formatIdentifier,omitempty"`
+	SegmentationUpidFormat *string `xml:"segmentationUpidFormat,attr" json:"segmentationUpidFormat,omitempty"`
+	Value                  string  `xml:",chardata" json:"value,omitempty"`
+}
+
+// UnmarshalXML implements xml.Unmarshaler: Payload is not kept if content is decoded as
+// Signal or SpliceInfoSection.
+func (e *Event) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type event Event
+	if err := d.DecodeElement((*event)(e), &start); err != nil {
+		return err
+	}
+	if e.Signal != nil || e.SpliceInfoSection != nil {
+		e.Payload = ""
+	}
+	return nil
+}
+
+// usesSCTE35 returns true if any Event of MPD has SCTE-35 elements.
+func (m *MPD) usesSCTE35() bool {
+	for _, p := range m.Period {
+		for _, es := range p.EventStreams {
+			for _, e := range es.Events {
+				if e.Signal != nil || e.SpliceInfoSection != nil {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func modifySCTE35Signal(s *SCTE35Signal, ns namespaces) *scte35SignalMarshal {
+	if s == nil {
+		return nil
+	}
+	res := &scte35SignalMarshal{
+		SpliceInfoSection: modifySCTE35SpliceInfoSection(s.SpliceInfoSection, ns),
+	}
+	if s.Binary != nil {
+		res.Binary = &SCTE35Binary{
+			SignalType: copyobj.String(s.Binary.SignalType),
+			Value:      s.Binary.Value,
+		}
+	}
+	return res
+}
+
+func modifySCTE35SpliceInfoSection(s *SCTE35SpliceInfoSection, ns namespaces) *scte35SpliceInfoSectionMarshal {
+	if s == nil {
+		return nil
+	}
+	res := &scte35SpliceInfoSectionMarshal{
+		SAPType:         copyobj.UInt64(s.SAPType),
+		PTSAdjustment:   copyobj.UInt64(s.PTSAdjustment),
+		ProtocolVersion: copyobj.UInt64(s.ProtocolVersion),
+		Tier:            copyobj.UInt64(s.Tier),
+		Extensions:      modifyExtensions(s.Extensions, ns),
+	}
+	if si := s.SpliceInsert; si != nil {
+		res.SpliceInsert = &scte35SpliceInsertMarshal{
+			SpliceEventID:              copyobj.UInt64(si.SpliceEventID),
+			SpliceEventCancelIndicator: copyobj.Bool(si.SpliceEventCancelIndicator),
+			OutOfNetworkIndicator:      copyobj.Bool(si.OutOfNetworkIndicator),
+			SpliceImmediateFlag:        copyobj.Bool(si.SpliceImmediateFlag),
+			UniqueProgramID:            copyobj.UInt64(si.UniqueProgramID),
+			AvailNum:                   copyobj.UInt64(si.AvailNum),
+			AvailsExpected:             copyobj.UInt64(si.AvailsExpected),
+		}
+		if si.Program != nil {
+			res.SpliceInsert.Program = &scte35ProgramMarshal{SpliceTime: copySCTE35SpliceTime(si.Program.SpliceTime)}
+		}
+		if si.BreakDuration != nil {
+			res.SpliceInsert.BreakDuration = &SCTE35BreakDuration{
+				AutoReturn: copyobj.Bool(si.BreakDuration.AutoReturn),
+				Duration:   copyobj.UInt64(si.BreakDuration.Duration),
+			}
+		}
+	}
+	if s.TimeSignal != nil {
+		res.TimeSignal = &scte35TimeSignalMarshal{SpliceTime: copySCTE35SpliceTime(s.TimeSignal.SpliceTime)}
+	}
+	if s.SegmentationDescriptors != nil {
+		res.SegmentationDescriptors = make([]scte35SegmentationDescriptorMarshal, 0, len(s.SegmentationDescriptors))
+		for _, sd := range s.SegmentationDescriptors {
+			sdm := scte35SegmentationDescriptorMarshal{
+				SegmentationEventID:              copyobj.UInt64(sd.SegmentationEventID),
+				SegmentationEventCancelIndicator: copyobj.Bool(sd.SegmentationEventCancelIndicator),
+				SegmentationDuration:             copyobj.UInt64(sd.SegmentationDuration),
+				SegmentationTypeID:               copyobj.UInt64(sd.SegmentationTypeID),
+				SegmentNum:                       copyobj.UInt64(sd.SegmentNum),
+				SegmentsExpected:                 copyobj.UInt64(sd.SegmentsExpected),
+				SubSegmentNum:                    copyobj.UInt64(sd.SubSegmentNum),
+				SubSegmentsExpected:              copyobj.UInt64(sd.SubSegmentsExpected),
+			}
+			if dr := sd.DeliveryRestrictions; dr != nil {
+				sdm.DeliveryRestrictions = &SCTE35DeliveryRestrictions{
+					WebDeliveryAllowedFlag: copyobj.Bool(dr.WebDeliveryAllowedFlag),
+					NoRegionalBlackoutFlag: copyobj.Bool(dr.NoRegionalBlackoutFlag),
+					ArchiveAllowedFlag:     copyobj.Bool(dr.ArchiveAllowedFlag),
+					DeviceRestrictions:     copyobj.UInt64(dr.DeviceRestrictions),
+				}
+			}
+			if sd.SegmentationUpids != nil {
+				sdm.SegmentationUpids = make([]SCTE35SegmentationUpid, 0, len(sd.SegmentationUpids))
+				for _, u := range sd.SegmentationUpids {
+					sdm.SegmentationUpids = append(sdm.SegmentationUpids, SCTE35SegmentationUpid{
+						SegmentationUpidType:   copyobj.UInt64(u.SegmentationUpidType),
+						FormatIdentifier:       copyobj.UInt64(u.FormatIdentifier),
+						SegmentationUpidFormat: copyobj.String(u.SegmentationUpidFormat),
+						Value:                  u.Value,
+					})
+				}
+			}
+			res.SegmentationDescriptors = append(res.SegmentationDescriptors, sdm)
+		}
+	}
+	return res
+}
+
+func copySCTE35SpliceTime(t *SCTE35SpliceTime) *SCTE35SpliceTime {
+	if t == nil {
+		return nil
+	}
+	return &SCTE35SpliceTime{PTSTime: copyobj.UInt64(t.PTSTime)}
+}
@@ -0,0 +1,51 @@
+package mpd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSCTE35Event(t *testing.T) {
+	m := decodeFixture(t, "fixture_event_stream.mpd")
+	e := m.Period[0].EventStreams[0].Events[0]
+	require.Empty(t, e.Payload)
+	require.Equal(t, "/DAlAAAAAAAAAP/wFAUAAAABf+/+AAAAAH4AKTLgAAEAAAAAAADx4UFr", e.Signal.Binary.Value)
+
+	es := m.Period[0].EventStreams[2]
+	require.Equal(t, SCTE35SchemeXML, *es.SchemeIDURI)
+	sis := es.Events[0].SpliceInfoSection
+	require.Equal(t, uint64(1207959694), *sis.SpliceInsert.SpliceEventID)
+	require.True(t, *sis.SpliceInsert.OutOfNetworkIndicator)
+	require.Equal(t, uint64(720000), *sis.SpliceInsert.Program.SpliceTime.PTSTime)
+	require.Equal(t, uint64(2700000), *sis.SpliceInsert.BreakDuration.Duration)
+	require.Equal(t, uint64(52), *sis.SegmentationDescriptors[0].SegmentationTypeID)
+	require.Equal(t, "MPU:ad-break-1", sis.SegmentationDescriptors[0].SegmentationUpids[0].Value)
+	require.Equal(t, SCTE35Namespace, sis.Extensions[0].XMLName.Space)
+	require.Equal(t, "AvailDescriptor", sis.Extensions[0].XMLName.Local)
+
+	// other content is kept as is
+	require.Equal(t, "https://example.com/callback", m.Period[0].EventStreams[1].Events[0].Payload)
+}
+
+func TestEncodeSCTE35Namespace(t *testing.T) {
+	m := &MPD{Period: []Period{{EventStreams: []EventStream{{
+		SchemeIDURI: stringPtr(SCTE35SchemeXML),
+		Events: []Event{{
+			SpliceInfoSection: &SCTE35SpliceInfoSection{
+				TimeSignal: &SCTE35TimeSignal{SpliceTime: &SCTE35SpliceTime{PTSTime: uint64Ptr(900000)}},
+			},
+			// ignored if SCTE-35 elements are set
+			Payload: "ignored",
+		}},
+	}}}}}
+	b, err := m.Encode()
+	require.NoError(t, err)
+	require.Contains(t, string(b), `xmlns:scte35="http://www.scte.org/schemas/35/2016"`)
+	require.Contains(t, string(b), `<scte35:SpliceTime ptsTime="900000"/>`)
+	require.NotContains(t, string(b), "ignored")
+
+	decoded := new(MPD)
+	require.NoError(t, decoded.Decode(b))
+	require.Equal(t, uint64(900000), *decoded.Period[0].EventStreams[0].Events[0].SpliceInfoSection.TimeSignal.SpliceTime.PTSTime)
+}
@@ -0,0 +1,402 @@
+package mpd
+
+import (
+	"fmt"
+	"time"
+
+	copyobj "github.com/mc2soft/mpd/utils"
+)
+
+// Segment describes single media segment addressed by SegmentTemplate.
+type Segment struct {
+	// Number is a value for $Number$ template identifier.
+	Number uint64
+	// Time is a value for $Time$ template identifier (in timescale units).
+	Time uint64
+	// Duration is a segment duration (in timescale units).
+	Duration uint64
+}
+
+// EffectiveTimescale returns SegmentTemplate timescale, 1 if it is not set.
+func (st *SegmentTemplate) EffectiveTimescale() uint64 {
+	if st.Timescale == nil || *st.Timescale == 0 {
+		return 1
+	}
+	return *st.Timescale
+}
+
+// EffectiveStartNumber returns SegmentTemplate startNumber, 1 if it is not set.
+func (st *SegmentTemplate) EffectiveStartNumber() uint64 {
+	if st.StartNumber == nil {
+		return 1
+	}
+	return *st.StartNumber
+}
+
+// EffectivePresentationTimeOffset returns SegmentTemplate presentationTimeOffset, 0 if it is not set.
+func (st *SegmentTemplate) EffectivePresentationTimeOffset() uint64 {
+	if st.PresentationTimeOffset == nil {
+		return 0
+	}
+	return *st.PresentationTimeOffset
+}
+
+// PresentationTimeOffsetDuration returns presentationTimeOffset as time.Duration using effective timescale.
+func (st *SegmentTemplate) PresentationTimeOffsetDuration() time.Duration {
+	return ticksToDuration(st.EffectivePresentationTimeOffset(), st.EffectiveTimescale())
+}
+
+// SetPresentationTimeOffsetDuration sets presentationTimeOffset from d using effective timescale,
+// the value is rounded to the nearest tick.
+func (st *SegmentTemplate) SetPresentationTimeOffsetDuration(d time.Duration) error {
+	if d < 0 {
+		return fmt.Errorf("negative presentationTimeOffset %s", d)
+	}
+	pto := durationToTicks(d, st.EffectiveTimescale())
+	st.PresentationTimeOffset = &pto
+	return nil
+}
+
+// Segments expands SegmentTimeline into the list of segments.
+// Negative S@r repeats segment until the next S@t; for the last S element it is treated as no repeat,
+// since the end of the Period is unknown here.
+// Templates without SegmentTimeline are expanded from @duration up to @endNumber, no segments are returned
+// if it is not set, see SegmentsWithin.
+func (st *SegmentTemplate) Segments() []Segment {
+	return st.SegmentsWithin(0)
+}
+
+// SegmentsWithin is like Segments, but periodDuration (if positive) limits the number of segments of @duration
+// templates and repeats of the last S element with negative S@r. The last segment of @duration template
+// is cut at the end of the Period.
+func (st *SegmentTemplate) SegmentsWithin(periodDuration time.Duration) []Segment {
+	if len(st.SegmentTimelineS) == 0 {
+		return st.durationSegments(periodDuration)
+	}
+
+	var res []Segment
+	number := st.EffectiveStartNumber()
+	var t uint64
+	for i, s := range st.SegmentTimelineS {
+		if s.T != nil {
+			t = *s.T
+		}
+		if s.N != nil {
+			number = *s.N
+		}
+		if s.D == 0 {
+			continue
+		}
+
+		var repeat int64
+		if s.R != nil {
+			repeat = *s.R
+		}
+		if repeat < 0 {
+			repeat = 0
+			var end uint64
+			switch {
+			case i+1 < len(st.SegmentTimelineS) && st.SegmentTimelineS[i+1].T != nil:
+				end = *st.SegmentTimelineS[i+1].T
+			case i+1 == len(st.SegmentTimelineS) && periodDuration > 0:
+				end = st.EffectivePresentationTimeOffset() + durationToTicks(periodDuration, st.EffectiveTimescale())
+			}
+			if end > t {
+				repeat = int64((end-t+s.D-1)/s.D) - 1
+			}
+		}
+
+		for j := int64(0); j <= repeat; j++ {
+			res = append(res, Segment{Number: number, Time: t, Duration: s.D})
+			number++
+			t += s.D
+		}
+	}
+	return res
+}
+
+// durationSegments expands @duration template, see SegmentsWithin.
+// $Time$ of segment is its start relative to the Period plus presentationTimeOffset.
+func (st *SegmentTemplate) durationSegments(periodDuration time.Duration) []Segment {
+	if st.Duration == nil || *st.Duration == 0 {
+		return nil
+	}
+	d := *st.Duration
+	startNumber := st.EffectiveStartNumber()
+	var count, periodTicks uint64
+	if periodDuration > 0 {
+		periodTicks = durationToTicks(periodDuration, st.EffectiveTimescale())
+		count = (periodTicks + d - 1) / d
+	}
+	if st.EndNumber != nil {
+		if *st.EndNumber < startNumber {
+			return nil
+		}
+		if n := *st.EndNumber - startNumber + 1; count == 0 || n < count {
+			count = n
+		}
+	}
+
+	pto := st.EffectivePresentationTimeOffset()
+	res := make([]Segment, 0, count)
+	for i := uint64(0); i < count; i++ {
+		s := Segment{Number: startNumber + i, Time: pto + i*d, Duration: d}
+		if periodTicks > 0 && (i+1)*d > periodTicks {
+			s.Duration = periodTicks - i*d
+		}
+		res = append(res, s)
+	}
+	return res
+}
+
+// ticksToDuration converts value in timescale units to time.Duration.
+func ticksToDuration(ticks, timescale uint64) time.Duration {
+	if timescale == 0 {
+		timescale = 1
+	}
+	sec := ticks / timescale
+	rem := ticks % timescale
+	return time.Duration(sec)*time.Second + time.Duration(rem)*time.Second/time.Duration(timescale)
+}
+
+// durationToTicks converts non-negative time.Duration to timescale units rounding to the nearest tick.
+func durationToTicks(d time.Duration, timescale uint64) uint64 {
+	if timescale == 0 {
+		timescale = 1
+	}
+	sec := uint64(d / time.Second)
+	rem := uint64(d % time.Second)
+	return sec*timescale + (rem*timescale+uint64(time.Second)/2)/uint64(time.Second)
+}
+
+// EffectiveSegmentTemplate returns SegmentTemplate of Representation r of AdaptationSet as within Period p with
+// attributes and elements inherited from AdaptationSet and Period levels, nil if there is no SegmentTemplate
+// at any level. The result is a copy, it may be modified freely.
+func (p *Period) EffectiveSegmentTemplate(as *AdaptationSet, r *Representation) *SegmentTemplate {
+	var res *SegmentTemplate
+	res = inheritSegmentTemplate(res, p.SegmentTemplate)
+	if as != nil {
+		res = inheritSegmentTemplate(res, as.SegmentTemplate)
+	}
+	if r != nil {
+		res = inheritSegmentTemplate(res, r.SegmentTemplate)
+	}
+	return res
+}
+
+// inheritSegmentTemplate returns a copy of st with unset attributes and elements taken from parent.
+func inheritSegmentTemplate(parent, st *SegmentTemplate) *SegmentTemplate {
+	if st == nil {
+		return parent
+	}
+	if parent == nil {
+		parent = &SegmentTemplate{}
+	}
+
+	res := &SegmentTemplate{
+		Timescale:                copyobj.UInt64(inheritUInt64(parent.Timescale, st.Timescale)),
+		Duration:                 copyobj.UInt64(inheritUInt64(parent.Duration, st.Duration)),
+		Media:                    copyobj.String(inheritString(parent.Media, st.Media)),
+		Index:                    copyobj.String(inheritString(parent.Index, st.Index)),
+		Initialization:           copyobj.String(inheritString(parent.Initialization, st.Initialization)),
+		BitstreamSwitching:       copyobj.String(inheritString(parent.BitstreamSwitching, st.BitstreamSwitching)),
+		StartNumber:              copyobj.UInt64(inheritUInt64(parent.StartNumber, st.StartNumber)),
+		EndNumber:                copyobj.UInt64(inheritUInt64(parent.EndNumber, st.EndNumber)),
+		PresentationTimeOffset:   copyobj.UInt64(inheritUInt64(parent.PresentationTimeOffset, st.PresentationTimeOffset)),
+		AvailabilityTimeOffset:   parent.AvailabilityTimeOffset,
+		AvailabilityTimeComplete: copyobj.Bool(inheritBool(parent.AvailabilityTimeComplete, st.AvailabilityTimeComplete)),
+		InitializationURL:        parent.InitializationURL,
+		RepresentationIndex:      parent.RepresentationIndex,
+		SegmentTimelineS:         parent.SegmentTimelineS,
+	}
+	if st.AvailabilityTimeOffset != nil {
+		res.AvailabilityTimeOffset = st.AvailabilityTimeOffset
+	}
+	if st.InitializationURL != nil {
+		res.InitializationURL = st.InitializationURL
+	}
+	if st.RepresentationIndex != nil {
+		res.RepresentationIndex = st.RepresentationIndex
+	}
+	if len(st.SegmentTimelineS) > 0 {
+		res.SegmentTimelineS = st.SegmentTimelineS
+	}
+	res.AvailabilityTimeOffset = copyAvailabilityTimeOffset(res.AvailabilityTimeOffset)
+	res.InitializationURL = copyURLType(res.InitializationURL)
+	res.RepresentationIndex = copyURLType(res.RepresentationIndex)
+	if res.SegmentTimelineS != nil {
+		res.SegmentTimelineS = copySegmentTimelineS(res.SegmentTimelineS)
+	}
+	return res
+}
+
+func inheritUInt64(parent, v *uint64) *uint64 {
+	if v != nil {
+		return v
+	}
+	return parent
+}
+
+func inheritBool(parent, v *bool) *bool {
+	if v != nil {
+		return v
+	}
+	return parent
+}
+
+func inheritString(parent, v *string) *string {
+	if v != nil {
+		return v
+	}
+	return parent
+}
+
+// EffectiveSegmentList returns SegmentList of Representation r of AdaptationSet as within Period p with
+// attributes and elements inherited from AdaptationSet and Period levels, nil if there is no SegmentList
+// at any level. The result is a copy, it may be modified freely.
+func (p *Period) EffectiveSegmentList(as *AdaptationSet, r *Representation) *SegmentList {
+	var res *SegmentList
+	res = inheritSegmentList(res, p.SegmentList)
+	if as != nil {
+		res = inheritSegmentList(res, as.SegmentList)
+	}
+	if r != nil {
+		res = inheritSegmentList(res, r.SegmentList)
+	}
+	return cloneSegmentList(res)
+}
+
+// inheritSegmentList returns sl with unset attributes and elements taken from parent, values are not copied.
+func inheritSegmentList(parent, sl *SegmentList) *SegmentList {
+	if sl == nil {
+		return parent
+	}
+	if parent == nil {
+		return sl
+	}
+	res := &SegmentList{
+		Timescale:                inheritUInt64(parent.Timescale, sl.Timescale),
+		Duration:                 inheritUInt64(parent.Duration, sl.Duration),
+		StartNumber:              inheritUInt64(parent.StartNumber, sl.StartNumber),
+		PresentationTimeOffset:   inheritUInt64(parent.PresentationTimeOffset, sl.PresentationTimeOffset),
+		AvailabilityTimeOffset:   parent.AvailabilityTimeOffset,
+		AvailabilityTimeComplete: inheritBool(parent.AvailabilityTimeComplete, sl.AvailabilityTimeComplete),
+		Initialization:           parent.Initialization,
+		RepresentationIndex:      parent.RepresentationIndex,
+		SegmentTimelineS:         parent.SegmentTimelineS,
+		SegmentURLs:              parent.SegmentURLs,
+	}
+	if sl.AvailabilityTimeOffset != nil {
+		res.AvailabilityTimeOffset = sl.AvailabilityTimeOffset
+	}
+	if sl.Initialization != nil {
+		res.Initialization = sl.Initialization
+	}
+	if sl.RepresentationIndex != nil {
+		res.RepresentationIndex = sl.RepresentationIndex
+	}
+	if len(sl.SegmentTimelineS) > 0 {
+		res.SegmentTimelineS = sl.SegmentTimelineS
+	}
+	if len(sl.SegmentURLs) > 0 {
+		res.SegmentURLs = sl.SegmentURLs
+	}
+	return res
+}
+
+// Segments returns timing of SegmentList segments, one per SegmentURL, from SegmentTimeline or @duration.
+// Segments without timing information are not returned.
+func (sl *SegmentList) Segments() []Segment {
+	startNumber := uint64(1)
+	if sl.StartNumber != nil {
+		startNumber = *sl.StartNumber
+	}
+	if len(sl.SegmentURLs) == 0 {
+		return nil
+	}
+	endNumber := startNumber + uint64(len(sl.SegmentURLs)) - 1
+	st := &SegmentTemplate{
+		Timescale:              sl.Timescale,
+		Duration:               sl.Duration,
+		StartNumber:            &startNumber,
+		EndNumber:              &endNumber,
+		PresentationTimeOffset: sl.PresentationTimeOffset,
+		SegmentTimelineS:       sl.SegmentTimelineS,
+	}
+	res := st.Segments()
+	if len(res) > len(sl.SegmentURLs) {
+		res = res[:len(sl.SegmentURLs)]
+	}
+	return res
+}
+
+// EffectiveTimescale returns SegmentList timescale, 1 if it is not set.
+func (sl *SegmentList) EffectiveTimescale() uint64 {
+	return (&SegmentTemplate{Timescale: sl.Timescale}).EffectiveTimescale()
+}
+
+// EffectivePresentationTimeOffset returns SegmentList presentationTimeOffset, 0 if it is not set.
+func (sl *SegmentList) EffectivePresentationTimeOffset() uint64 {
+	if sl.PresentationTimeOffset == nil {
+		return 0
+	}
+	return *sl.PresentationTimeOffset
+}
+
+// EffectiveSegmentBase returns SegmentBase of Representation r of AdaptationSet as within Period p with
+// attributes and elements inherited from AdaptationSet and Period levels, nil if there is no SegmentBase
+// at any level. The result is a copy, it may be modified freely.
+func (p *Period) EffectiveSegmentBase(as *AdaptationSet, r *Representation) *SegmentBase {
+	var res *SegmentBase
+	res = inheritSegmentBase(res, p.SegmentBase)
+	if as != nil {
+		res = inheritSegmentBase(res, as.SegmentBase)
+	}
+	if r != nil {
+		res = inheritSegmentBase(res, r.SegmentBase)
+	}
+	return copySegmentBase(res)
+}
+
+// inheritSegmentBase returns sb with unset attributes and elements taken from parent, values are not copied.
+func inheritSegmentBase(parent, sb *SegmentBase) *SegmentBase {
+	if sb == nil {
+		return parent
+	}
+	if parent == nil {
+		return sb
+	}
+	res := &SegmentBase{
+		Timescale:                inheritUInt64(parent.Timescale, sb.Timescale),
+		PresentationTimeOffset:   inheritUInt64(parent.PresentationTimeOffset, sb.PresentationTimeOffset),
+		IndexRange:               inheritString(parent.IndexRange, sb.IndexRange),
+		IndexRangeExact:          inheritBool(parent.IndexRangeExact, sb.IndexRangeExact),
+		AvailabilityTimeOffset:   parent.AvailabilityTimeOffset,
+		AvailabilityTimeComplete: inheritBool(parent.AvailabilityTimeComplete, sb.AvailabilityTimeComplete),
+		Initialization:           parent.Initialization,
+		RepresentationIndex:      parent.RepresentationIndex,
+	}
+	if sb.AvailabilityTimeOffset != nil {
+		res.AvailabilityTimeOffset = sb.AvailabilityTimeOffset
+	}
+	if sb.Initialization != nil {
+		res.Initialization = sb.Initialization
+	}
+	if sb.RepresentationIndex != nil {
+		res.RepresentationIndex = sb.RepresentationIndex
+	}
+	return res
+}
+
+// EffectiveTimescale returns SegmentBase timescale, 1 if it is not set.
+func (sb *SegmentBase) EffectiveTimescale() uint64 {
+	return (&SegmentTemplate{Timescale: sb.Timescale}).EffectiveTimescale()
+}
+
+// EffectivePresentationTimeOffset returns SegmentBase presentationTimeOffset, 0 if it is not set.
+func (sb *SegmentBase) EffectivePresentationTimeOffset() uint64 {
+	if sb.PresentationTimeOffset == nil {
+		return 0
+	}
+	return *sb.PresentationTimeOffset
+}
@@ -0,0 +1,238 @@
+package mpd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func uint64Ptr(u uint64) *uint64 { return &u }
+
+func int64Ptr(i int64) *int64 { return &i }
+
+func stringPtr(s string) *string { return &s }
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestSegments(t *testing.T) {
+	st := &SegmentTemplate{
+		StartNumber: uint64Ptr(5),
+		SegmentTimelineS: []SegmentTimelineS{
+			{T: uint64Ptr(100), D: 10, R: int64Ptr(1)},
+			{D: 5},
+			{T: uint64Ptr(200), D: 20, R: int64Ptr(-1)},
+			{T: uint64Ptr(260), D: 30},
+		},
+	}
+	require.Equal(t, []Segment{
+		{Number: 5, Time: 100, Duration: 10},
+		{Number: 6, Time: 110, Duration: 10},
+		{Number: 7, Time: 120, Duration: 5},
+		{Number: 8, Time: 200, Duration: 20},
+		{Number: 9, Time: 220, Duration: 20},
+		{Number: 10, Time: 240, Duration: 20},
+		{Number: 11, Time: 260, Duration: 30},
+	}, st.Segments())
+
+	// S@n re-anchors numbering
+	st.SegmentTimelineS[2].N = uint64Ptr(100)
+	segments := st.Segments()
+	require.Equal(t, Segment{Number: 7, Time: 120, Duration: 5}, segments[2])
+	require.Equal(t, Segment{Number: 100, Time: 200, Duration: 20}, segments[3])
+	require.Equal(t, Segment{Number: 103, Time: 260, Duration: 30}, segments[6])
+}
+
+func TestSegmentsDuration(t *testing.T) {
+	st := &SegmentTemplate{Timescale: uint64Ptr(1000), Duration: uint64Ptr(2000), StartNumber: uint64Ptr(5)}
+	// unknown Period duration and no endNumber
+	require.Empty(t, st.Segments())
+
+	require.Equal(t, []Segment{
+		{Number: 5, Time: 0, Duration: 2000},
+		{Number: 6, Time: 2000, Duration: 2000},
+		{Number: 7, Time: 4000, Duration: 1000},
+	}, st.SegmentsWithin(5*time.Second))
+
+	st.PresentationTimeOffset = uint64Ptr(100)
+	st.EndNumber = uint64Ptr(6)
+	expected := []Segment{
+		{Number: 5, Time: 100, Duration: 2000},
+		{Number: 6, Time: 2100, Duration: 2000},
+	}
+	require.Equal(t, expected, st.Segments())
+	require.Equal(t, expected, st.SegmentsWithin(time.Minute))
+	require.Equal(t, expected[:1], st.SegmentsWithin(2*time.Second))
+
+	st.EndNumber = uint64Ptr(4)
+	require.Empty(t, st.SegmentsWithin(time.Minute))
+}
+
+func TestSegmentsWithinLastRepeat(t *testing.T) {
+	st := &SegmentTemplate{
+		Timescale:              uint64Ptr(10),
+		PresentationTimeOffset: uint64Ptr(100),
+		SegmentTimelineS:       []SegmentTimelineS{{T: uint64Ptr(100), D: 20, R: int64Ptr(-1)}},
+	}
+	require.Len(t, st.Segments(), 1)
+	segments := st.SegmentsWithin(7 * time.Second)
+	require.Len(t, segments, 4)
+	require.Equal(t, Segment{Number: 4, Time: 160, Duration: 20}, segments[3])
+}
+
+func TestSegmentsFixture(t *testing.T) {
+	m := decodeFixture(t, "fixture_elemental_delta_vod_multi_drm.mpd")
+	st := m.Period[0].AdaptationSets[0].Representations[0].SegmentTemplate
+	segments := st.Segments()
+	require.Len(t, segments, 18)
+	require.Equal(t, Segment{Number: 2, Time: 51, Duration: 200}, segments[0])
+	require.Equal(t, Segment{Number: 19, Time: 3451, Duration: 17}, segments[17])
+}
+
+func TestTicksToDuration(t *testing.T) {
+	require.Equal(t, 1500*time.Millisecond, ticksToDuration(3, 2))
+	require.Equal(t, 10*time.Second, ticksToDuration(10, 0))
+	require.Equal(t, 40*time.Millisecond, ticksToDuration(1, 25))
+}
+
+func TestPresentationTimeOffsetDuration(t *testing.T) {
+	st := &SegmentTemplate{}
+	require.Zero(t, st.PresentationTimeOffsetDuration())
+	require.NoError(t, st.SetPresentationTimeOffsetDuration(1400*time.Millisecond))
+	require.Equal(t, uint64(1), *st.PresentationTimeOffset)
+
+	st.Timescale = uint64Ptr(90000)
+	require.NoError(t, st.SetPresentationTimeOffsetDuration(10*time.Second+time.Millisecond))
+	require.Equal(t, uint64(900090), *st.PresentationTimeOffset)
+	require.Equal(t, 10*time.Second+time.Millisecond, st.PresentationTimeOffsetDuration())
+
+	st.Timescale = uint64Ptr(30000)
+	require.NoError(t, st.SetPresentationTimeOffsetDuration(time.Second/3))
+	require.Equal(t, uint64(10000), *st.PresentationTimeOffset)
+	require.Equal(t, 333333333*time.Nanosecond, st.PresentationTimeOffsetDuration())
+
+	require.EqualError(t, st.SetPresentationTimeOffsetDuration(-time.Second), "negative presentationTimeOffset -1s")
+	require.Equal(t, uint64(10000), *st.PresentationTimeOffset)
+}
+
+func TestDurationToTicks(t *testing.T) {
+	require.Equal(t, uint64(25), durationToTicks(time.Second, 25))
+	require.Equal(t, uint64(1), durationToTicks(20*time.Millisecond, 25))
+	require.Equal(t, uint64(0), durationToTicks(19*time.Millisecond, 25))
+	require.Equal(t, uint64(3), durationToTicks(3*time.Second, 0))
+}
+
+func TestEffectiveSegmentListAndBase(t *testing.T) {
+	m := decodeFixture(t, "fixture_segment_list.mpd")
+	as := m.Period[0].AdaptationSets[0]
+	sl := m.Period[0].EffectiveSegmentList(as, &as.Representations[0])
+	require.Equal(t, "video/init.mp4", *sl.Initialization.SourceURL)
+	require.Len(t, sl.SegmentURLs, 3)
+	require.Equal(t, []Segment{
+		{Number: 1, Time: 0, Duration: 4000},
+		{Number: 2, Time: 4000, Duration: 4000},
+		{Number: 3, Time: 8000, Duration: 4000},
+	}, sl.Segments())
+	sl.Initialization.SourceURL = stringPtr("changed.mp4")
+	require.Equal(t, "video/init.mp4", *as.SegmentList.Initialization.SourceURL)
+
+	m = decodeFixture(t, "fixture_period_template.mpd")
+	r := &m.Period[1].AdaptationSets[0].Representations[0]
+	sb := m.Period[1].EffectiveSegmentBase(m.Period[1].AdaptationSets[0], r)
+	require.Equal(t, "800-1000", *sb.IndexRange)
+	require.Equal(t, "0-799", *sb.Initialization.Range)
+	require.Nil(t, m.Period[1].EffectiveSegmentList(m.Period[1].AdaptationSets[0], r))
+	require.Len(t, m.Period[2].EffectiveSegmentList(m.Period[2].AdaptationSets[0], nil).Segments(), 1)
+}
+
+func TestEffectiveSegmentTemplate(t *testing.T) {
+	m := decodeFixture(t, "fixture_period_template.mpd")
+	p := &m.Period[0]
+
+	video := p.AdaptationSets[0]
+	st := p.EffectiveSegmentTemplate(video, &video.Representations[0])
+	require.Equal(t, &SegmentTemplate{
+		Timescale:      uint64Ptr(90000),
+		Duration:       uint64Ptr(180000),
+		Media:          stringPtr("$RepresentationID$/$Number$.m4s"),
+		Initialization: stringPtr("$RepresentationID$/init.mp4"),
+		StartNumber:    uint64Ptr(1),
+	}, st)
+
+	audio := p.AdaptationSets[1]
+	st = p.EffectiveSegmentTemplate(audio, &audio.Representations[0])
+	require.Equal(t, uint64(1000), *st.Timescale)
+	require.Equal(t, uint64(0), *st.StartNumber)
+
+	// result is a copy
+	*st.Media = "changed"
+	require.Equal(t, "$RepresentationID$/$Number$.m4s", *p.SegmentTemplate.Media)
+
+	require.Nil(t, m.Period[1].EffectiveSegmentTemplate(m.Period[1].AdaptationSets[0], &m.Period[1].AdaptationSets[0].Representations[0]))
+
+	// availabilityTimeComplete is inherited like availabilityTimeOffset
+	ato := AvailabilityTimeOffset(1.5)
+	p.SegmentTemplate.AvailabilityTimeOffset = &ato
+	p.SegmentTemplate.AvailabilityTimeComplete = boolPtr(false)
+	st = p.EffectiveSegmentTemplate(video, &video.Representations[0])
+	require.Equal(t, ato, *st.AvailabilityTimeOffset)
+	require.Equal(t, false, *st.AvailabilityTimeComplete)
+	video.SegmentTemplate = &SegmentTemplate{AvailabilityTimeComplete: boolPtr(true)}
+	st = p.EffectiveSegmentTemplate(video, &video.Representations[0])
+	require.Equal(t, true, *st.AvailabilityTimeComplete)
+	require.Equal(t, false, *p.SegmentTemplate.AvailabilityTimeComplete)
+}
+
+func TestInheritedSegmentTemplateHelpers(t *testing.T) {
+	for _, name := range []string{"fixture_adaptation_set_template.mpd", "fixture_period_template.mpd"} {
+		m := decodeFixture(t, name)
+		timelines, err := m.Timelines()
+		require.NoError(t, err, name)
+		require.NotEmpty(t, timelines, name)
+		for _, tl := range timelines {
+			require.NotEmpty(t, tl.Segments, "%s %s", name, tl.Representation)
+		}
+		urls, err := m.SegmentURLs("http://origin/vod/manifest.mpd")
+		require.NoError(t, err, name)
+		require.NotEmpty(t, urls, name)
+		tracks, err := m.ABRTracks(nil)
+		require.NoError(t, err, name)
+		require.Len(t, tracks, len(timelines), name)
+		report, err := m.HTMLReport()
+		require.NoError(t, err, name)
+		require.Contains(t, string(report), `"segments":[[0,`, name)
+		params, err := m.LatencyParams()
+		require.NoError(t, err, name)
+		require.NotZero(t, params.SegmentDuration, name)
+		require.Empty(t, m.Validate(), name)
+	}
+
+	m := decodeFixture(t, "fixture_adaptation_set_template.mpd")
+	urls, err := m.SegmentURLs("http://origin/vod/manifest.mpd")
+	require.NoError(t, err)
+	require.Len(t, urls, 2*4)
+	require.Equal(t, "http://origin/vod/720p/init.mp4", urls[0].URL)
+	require.Equal(t, "http://origin/vod/1080p/720000.m4s", urls[7].URL)
+
+	// checks see inherited timelines
+	m.Period[0].AdaptationSets[0].SegmentTemplate.SegmentTimelineS[0].R = int64Ptr(1)
+	require.Len(t, CheckDurations(m, DefaultTolerance), 2)
+	m.Period[0].AdaptationSets[0].Representations[1].SegmentTemplate = &SegmentTemplate{
+		SegmentTimelineS: []SegmentTimelineS{{T: uint64Ptr(90000), D: 360000}},
+	}
+	findings := CheckPeriodAlignment(m, DefaultTolerance)
+	require.Len(t, findings, 2)
+	require.Equal(t, "MPD/Period[0]/AdaptationSet[0]/Representation[1]", findings[0].Path)
+
+	m = decodeFixture(t, "fixture_period_template.mpd")
+	m.Period[0].SegmentTemplate.PresentationTimeOffset = uint64Ptr(1000)
+	findings = CheckPresentationTimeOffsets(m, DefaultTolerance)
+	require.Len(t, findings, 1)
+	require.Equal(t, "MPD/Period[0]/AdaptationSet[1]/Representation[0]", findings[0].Path)
+
+	// inherited template errors are reported once where they are defined
+	m.Period[0].SegmentTemplate.Media = stringPtr("$Foo$.m4s")
+	findings = validateTemplates(m)
+	require.Len(t, findings, 1)
+	require.Equal(t, "MPD/Period[0]/SegmentTemplate@media", findings[0].Path)
+}
@@ -0,0 +1,181 @@
+package mpd
+
+import (
+	"sort"
+)
+
+// DeviceCapabilities describes client preferences and capabilities used by SelectTracks.
+type DeviceCapabilities struct {
+	// Languages is a list of preferred languages in priority order.
+	Languages []string
+	// SupportsCodecs reports whether the client can play given codecs string, nil means any codecs.
+	SupportsCodecs func(codecs string) bool
+	// MaxBandwidth caps Representation bandwidth, 0 means no limit.
+	// If all Representations exceed the cap, the one with the lowest bandwidth is selected.
+	MaxBandwidth uint64
+	// EssentialProperties lists schemeIdUri values of EssentialProperty descriptors understood by the client.
+	// AdaptationSets and Representations with other EssentialProperty descriptors are ignored (e.g. trick mode
+	// AdaptationSets of TrickModeScheme).
+	EssentialProperties []string
+}
+
+// TrickModeScheme is a schemeIdUri of EssentialProperty marking trick mode AdaptationSets.
+const TrickModeScheme = "http://dashif.org/guidelines/trickmode"
+
+// understands reports whether all EssentialProperty descriptors are understood by the client.
+func (caps DeviceCapabilities) understands(properties []Descriptor) bool {
+	for _, d := range properties {
+		known := false
+		for _, scheme := range caps.EssentialProperties {
+			if stringValue(d.SchemeIDURI) == scheme {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return false
+		}
+	}
+	return true
+}
+
+// TrackSelection is a chosen AdaptationSet with its playable Representations sorted by bandwidth.
+type TrackSelection struct {
+	ContentType     string
+	AdaptationSet   *AdaptationSet
+	Representations []*Representation
+}
+
+// SelectTracks implements DASH-IF recommended initial track selection for Period:
+// for each content type (in order of appearance) it keeps AdaptationSets with playable Representations and
+// without EssentialProperty descriptors unknown to the client, prefers ones with the language which best matches
+// capabilities languages (as AdaptationSetsForLanguages does), then ones with "main" Role, then ones with
+// the highest @selectionPriority, then takes the first one in document order.
+func (p *Period) SelectTracks(caps DeviceCapabilities) []TrackSelection {
+	var contentTypes []string
+	candidates := make(map[string][]TrackSelection)
+	for _, as := range p.AdaptationSets {
+		if as == nil || !caps.understands(as.EssentialProperty) {
+			continue
+		}
+		reps := caps.playableRepresentations(as)
+		if len(reps) == 0 {
+			continue
+		}
+		ct := adaptationSetContentType(as)
+		if _, ok := candidates[ct]; !ok {
+			contentTypes = append(contentTypes, ct)
+		}
+		candidates[ct] = append(candidates[ct], TrackSelection{ContentType: ct, AdaptationSet: as, Representations: reps})
+	}
+
+	res := make([]TrackSelection, 0, len(contentTypes))
+	for _, ct := range contentTypes {
+		res = append(res, caps.selectAdaptationSet(candidates[ct]))
+	}
+	return res
+}
+
+// RoleScheme is a schemeIdUri of Role descriptors defined by ISO/IEC 23009-1.
+const RoleScheme = "urn:mpeg:dash:role:2011"
+
+// HasRole reports whether AdaptationSet has Role descriptor of RoleScheme with given value.
+func (as *AdaptationSet) HasRole(value string) bool {
+	for _, r := range as.Role {
+		if stringValue(r.SchemeIDURI) == RoleScheme && stringValue(r.Value) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// selectAdaptationSet chooses one of candidates with the same content type.
+func (caps DeviceCapabilities) selectAdaptationSet(candidates []TrackSelection) TrackSelection {
+	var langs []string
+	var withLang []TrackSelection
+	for _, c := range candidates {
+		if c.AdaptationSet.Lang != nil {
+			langs = append(langs, *c.AdaptationSet.Lang)
+			withLang = append(withLang, c)
+		}
+	}
+	var matched []TrackSelection
+	for _, i := range matchLanguages(langs, caps.Languages) {
+		matched = append(matched, withLang[i])
+	}
+	if len(matched) > 0 {
+		return selectMainRole(matched)
+	}
+	return selectMainRole(candidates)
+}
+
+// selectMainRole returns the candidate with "main" Role, or any candidate if there is no such; among them
+// the first one with the highest @selectionPriority is chosen.
+func selectMainRole(candidates []TrackSelection) TrackSelection {
+	var main []TrackSelection
+	for _, c := range candidates {
+		if c.AdaptationSet.HasRole("main") {
+			main = append(main, c)
+		}
+	}
+	if len(main) > 0 {
+		candidates = main
+	}
+
+	priority := func(as *AdaptationSet) uint64 {
+		if as.SelectionPriority == nil {
+			return 1
+		}
+		return *as.SelectionPriority
+	}
+	res := candidates[0]
+	for _, c := range candidates[1:] {
+		if priority(c.AdaptationSet) > priority(res.AdaptationSet) {
+			res = c
+		}
+	}
+	return res
+}
+
+// playableRepresentations returns Representations of AdaptationSet supported by capabilities, sorted by bandwidth.
+func (caps DeviceCapabilities) playableRepresentations(as *AdaptationSet) []*Representation {
+	var supported []*Representation
+	for i := range as.Representations {
+		r := &as.Representations[i]
+		codecs := stringValue(r.Codecs)
+		if codecs == "" {
+			codecs = stringValue(as.Codecs)
+		}
+		if !caps.understands(r.EssentialProperty) {
+			continue
+		}
+		if caps.SupportsCodecs == nil || caps.SupportsCodecs(codecs) {
+			supported = append(supported, r)
+		}
+	}
+	if len(supported) == 0 {
+		return nil
+	}
+
+	bandwidth := func(r *Representation) uint64 {
+		if r.Bandwidth == nil {
+			return 0
+		}
+		return *r.Bandwidth
+	}
+	sort.SliceStable(supported, func(i, j int) bool { return bandwidth(supported[i]) < bandwidth(supported[j]) })
+	if caps.MaxBandwidth == 0 {
+		return supported
+	}
+
+	var res []*Representation
+	for _, r := range supported {
+		if bandwidth(r) <= caps.MaxBandwidth {
+			res = append(res, r)
+		}
+	}
+	if len(res) == 0 {
+		res = supported[:1]
+	}
+	return res
+}
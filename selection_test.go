@@ -0,0 +1,101 @@
+package mpd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func representationIDs(rs []*Representation) []string {
+	var res []string
+	for _, r := range rs {
+		res = append(res, *r.ID)
+	}
+	return res
+}
+
+func TestSelectTracks(t *testing.T) {
+	p := &Period{AdaptationSets: []*AdaptationSet{
+		{MimeType: "video/mp4", Representations: []Representation{
+			{ID: stringPtr("v3"), Bandwidth: uint64Ptr(3000000), Codecs: stringPtr("hvc1.1.6.L93.B0")},
+			{ID: stringPtr("v2"), Bandwidth: uint64Ptr(2000000), Codecs: stringPtr("avc1.64001f")},
+			{ID: stringPtr("v1"), Bandwidth: uint64Ptr(1000000), Codecs: stringPtr("avc1.4d401e")},
+		}},
+		{MimeType: "audio/mp4", Lang: stringPtr("en"), Codecs: stringPtr("mp4a.40.2"), Representations: []Representation{
+			{ID: stringPtr("a-en"), Bandwidth: uint64Ptr(128000)},
+		}},
+		{MimeType: "audio/mp4", Lang: stringPtr("ru-RU"), Codecs: stringPtr("ec-3"), Representations: []Representation{
+			{ID: stringPtr("a-ru-ec3"), Bandwidth: uint64Ptr(384000)},
+		}},
+		{MimeType: "audio/mp4", Lang: stringPtr("ru"), Codecs: stringPtr("mp4a.40.2"), Representations: []Representation{
+			{ID: stringPtr("a-ru"), Bandwidth: uint64Ptr(128000)},
+		}},
+	}}
+
+	selection := p.SelectTracks(DeviceCapabilities{})
+	require.Len(t, selection, 2)
+	require.Equal(t, "video", selection[0].ContentType)
+	require.Equal(t, []string{"v1", "v2", "v3"}, representationIDs(selection[0].Representations))
+	require.Equal(t, "audio", selection[1].ContentType)
+	require.Equal(t, []string{"a-en"}, representationIDs(selection[1].Representations))
+
+	caps := DeviceCapabilities{
+		Languages: []string{"de", "RU"},
+		SupportsCodecs: func(codecs string) bool {
+			return strings.HasPrefix(codecs, "avc1") || strings.HasPrefix(codecs, "mp4a")
+		},
+		MaxBandwidth: 2500000,
+	}
+	selection = p.SelectTracks(caps)
+	require.Equal(t, []string{"v1", "v2"}, representationIDs(selection[0].Representations))
+	require.Equal(t, []string{"a-ru"}, representationIDs(selection[1].Representations))
+
+	caps.MaxBandwidth = 10
+	selection = p.SelectTracks(caps)
+	require.Equal(t, []string{"v1"}, representationIDs(selection[0].Representations))
+
+	// "main" Role is preferred among candidates of the same language
+	p.AdaptationSets = append(p.AdaptationSets, &AdaptationSet{
+		MimeType: "audio/mp4", Lang: stringPtr("en"), Codecs: stringPtr("mp4a.40.2"),
+		Role:            []Descriptor{{SchemeIDURI: stringPtr(RoleScheme), Value: stringPtr("main")}},
+		Representations: []Representation{{ID: stringPtr("a-en-main"), Bandwidth: uint64Ptr(128000)}},
+	})
+	selection = p.SelectTracks(DeviceCapabilities{})
+	require.Equal(t, []string{"a-en-main"}, representationIDs(selection[1].Representations))
+	selection = p.SelectTracks(DeviceCapabilities{Languages: []string{"ru"}})
+	require.Equal(t, []string{"a-ru-ec3"}, representationIDs(selection[1].Representations))
+
+	// languages are matched by BCP 47 rules, not by prefix
+	selection = p.SelectTracks(DeviceCapabilities{Languages: []string{"rus"}})
+	require.Equal(t, []string{"a-ru-ec3"}, representationIDs(selection[1].Representations))
+	p.AdaptationSets[4].Lang = stringPtr("en-GB")
+	selection = p.SelectTracks(DeviceCapabilities{Languages: []string{"de", "eng"}})
+	require.Equal(t, []string{"a-en"}, representationIDs(selection[1].Representations))
+
+	// AdaptationSets and Representations with unknown EssentialProperty are ignored
+	trickMode := &AdaptationSet{
+		MimeType: "video/mp4", Codecs: stringPtr("avc1.4d401e"),
+		EssentialProperty: []Descriptor{{SchemeIDURI: stringPtr(TrickModeScheme), Value: stringPtr("1")}},
+		Representations:   []Representation{{ID: stringPtr("v-trick"), Bandwidth: uint64Ptr(100000)}},
+	}
+	p.AdaptationSets = append([]*AdaptationSet{trickMode}, p.AdaptationSets...)
+	p.AdaptationSets[1].Representations[2].EssentialProperty = []Descriptor{{SchemeIDURI: stringPtr("urn:example:unknown")}}
+	selection = p.SelectTracks(DeviceCapabilities{})
+	require.Equal(t, []string{"v2", "v3"}, representationIDs(selection[0].Representations))
+	selection = p.SelectTracks(DeviceCapabilities{EssentialProperties: []string{TrickModeScheme}})
+	require.Equal(t, []string{"v-trick"}, representationIDs(selection[0].Representations))
+
+	// @selectionPriority breaks ties before document order, but after "main" Role
+	p = &Period{AdaptationSets: []*AdaptationSet{
+		{MimeType: "audio/mp4", Lang: stringPtr("en"), Representations: []Representation{{ID: stringPtr("a1")}}},
+		{MimeType: "audio/mp4", Lang: stringPtr("en"), SelectionPriority: uint64Ptr(2), Representations: []Representation{{ID: stringPtr("a2")}}},
+		{MimeType: "audio/mp4", Lang: stringPtr("en"), SelectionPriority: uint64Ptr(2), Representations: []Representation{{ID: stringPtr("a3")}}},
+		{MimeType: "audio/mp4", Lang: stringPtr("en"), SelectionPriority: uint64Ptr(0), Representations: []Representation{{ID: stringPtr("a4")}}},
+	}}
+	selection = p.SelectTracks(DeviceCapabilities{})
+	require.Equal(t, []string{"a2"}, representationIDs(selection[0].Representations))
+	p.AdaptationSets[3].Role = []Descriptor{{SchemeIDURI: stringPtr(RoleScheme), Value: stringPtr("main")}}
+	selection = p.SelectTracks(DeviceCapabilities{})
+	require.Equal(t, []string{"a4"}, representationIDs(selection[0].Representations))
+}
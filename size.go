@@ -0,0 +1,336 @@
+package mpd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// SizeReport describes encoded MPD size contributions.
+type SizeReport struct {
+	// Total is the size of encoded MPD in bytes.
+	Total int
+	// Elements maps element name to the number of bytes taken by its tags, attributes and text,
+	// children elements are accounted separately. Bytes outside of elements (XML declaration, whitespace
+	// between top-level tokens) are accounted with an empty name.
+	Elements map[string]int
+}
+
+// SizeReport encodes MPD and reports its size contributions per element name.
+func (m *MPD) SizeReport() (*SizeReport, error) {
+	b, err := m.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SizeReport{Total: len(b), Elements: map[string]int{}}
+	d := xml.NewDecoder(bytes.NewReader(b))
+	var stack []string
+	var offset int64
+	for {
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var name string
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name = t.Name.Local
+			stack = append(stack, name)
+		case xml.EndElement:
+			name = t.Name.Local
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) > 0 {
+				name = stack[len(stack)-1]
+			}
+		}
+		report.Elements[name] += int(d.InputOffset() - offset)
+		offset = d.InputOffset()
+	}
+	return report, nil
+}
+
+// CompactTimelines rewrites every SegmentTimeline with the minimal number of S elements:
+// consecutive segments of equal duration are merged using S@r, S@t is kept only where timeline is not continuous.
+// Timelines of Period, AdaptationSet and Representation levels are compacted where they are defined.
+// Timelines with negative S@r are left untouched.
+func (m *MPD) CompactTimelines() {
+	compact := func(st *SegmentTemplate) {
+		if st == nil || len(st.SegmentTimelineS) == 0 || hasNegativeRepeat(st.SegmentTimelineS) {
+			return
+		}
+		st.SegmentTimelineS = compactTimeline(st.Segments(), st.EffectiveStartNumber())
+	}
+	for _, p := range m.Period {
+		compact(p.SegmentTemplate)
+		for _, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			compact(as.SegmentTemplate)
+			for _, r := range as.Representations {
+				compact(r.SegmentTemplate)
+			}
+		}
+	}
+}
+
+func hasNegativeRepeat(ss []SegmentTimelineS) bool {
+	for _, s := range ss {
+		if s.R != nil && *s.R < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// compactTimeline builds SegmentTimeline from the list of segments, S@n is set where segment numbers
+// don't continue from startNumber or the previous segment.
+func compactTimeline(segments []Segment, startNumber uint64) []SegmentTimelineS {
+	var res []SegmentTimelineS
+	var next uint64
+	number := startNumber
+	for i, s := range segments {
+		if i > 0 && s.Time == next && s.Number == number && s.Duration == res[len(res)-1].D {
+			last := &res[len(res)-1]
+			if last.R == nil {
+				last.R = new(int64)
+			}
+			*last.R++
+		} else {
+			el := SegmentTimelineS{D: s.Duration}
+			if i == 0 || s.Time != next {
+				t := s.Time
+				el.T = &t
+			}
+			if s.Number != number {
+				n := s.Number
+				el.N = &n
+			}
+			res = append(res, el)
+		}
+		next = s.Time + s.Duration
+		number = s.Number + 1
+	}
+	return res
+}
+
+// HoistAttributes moves codecs attribute and ContentProtection elements shared by all Representations
+// of AdaptationSet to the AdaptationSet itself.
+func (m *MPD) HoistAttributes() {
+	for _, p := range m.Period {
+		for _, as := range p.AdaptationSets {
+			if as == nil || len(as.Representations) == 0 {
+				continue
+			}
+			reps := as.Representations
+
+			if as.Codecs == nil && reps[0].Codecs != nil {
+				same := true
+				for _, r := range reps[1:] {
+					same = same && r.Codecs != nil && *r.Codecs == *reps[0].Codecs
+				}
+				if same {
+					as.Codecs = reps[0].Codecs
+					for i := range reps {
+						reps[i].Codecs = nil
+					}
+				}
+			}
+
+			if len(as.ContentProtections) == 0 && len(reps[0].ContentProtections) > 0 {
+				first, err := xml.Marshal(modifyContentProtections(reps[0].ContentProtections, nil))
+				same := err == nil
+				for _, r := range reps[1:] {
+					if !same {
+						break
+					}
+					b, err := xml.Marshal(modifyContentProtections(r.ContentProtections, nil))
+					same = err == nil && bytes.Equal(first, b)
+				}
+				if same {
+					as.ContentProtections = reps[0].ContentProtections
+					for i := range reps {
+						reps[i].ContentProtections = nil
+					}
+				}
+			}
+		}
+	}
+}
+
+// ConsolidatePeriods merges adjacent Periods which differ in timing only: all Representations must be
+// addressed by SegmentTimeline (defined at any level) and the next Period segments must continue the previous
+// Period ones both in presentation time and in segment numbers. It returns the number of removed Periods.
+func (m *MPD) ConsolidatePeriods() (int, error) {
+	var removed int
+	for i := 0; i+1 < len(m.Period); {
+		ok, err := m.mergePeriods(i)
+		if err != nil {
+			return removed, err
+		}
+		if !ok {
+			i++
+			continue
+		}
+		m.Period = append(m.Period[:i+1], m.Period[i+2:]...)
+		removed++
+	}
+	return removed, nil
+}
+
+// periodShape encodes Period without its timing, Periods with equal shape differ in timing only.
+func periodShape(p Period) ([]byte, error) {
+	pm := modifyPeriod([]Period{p}, nil)[0]
+	pm.Start, pm.ID, pm.Duration = nil, nil, nil
+	stripTiming := func(st *segmentTemplateMarshal) {
+		if st != nil {
+			st.SegmentTimeline = nil
+			st.PresentationTimeOffset = nil
+			st.StartNumber = nil
+		}
+	}
+	stripTiming(pm.SegmentTemplate)
+	for _, as := range pm.AdaptationSets {
+		if as == nil {
+			continue
+		}
+		stripTiming(as.SegmentTemplate)
+		for _, r := range as.Representations {
+			stripTiming(r.SegmentTemplate)
+		}
+	}
+	return xml.Marshal(pm)
+}
+
+// mergePeriods merges Period i+1 into Period i if possible.
+func (m *MPD) mergePeriods(i int) (bool, error) {
+	a, b := &m.Period[i], &m.Period[i+1]
+	shapeA, err := periodShape(*a)
+	if err != nil {
+		return false, err
+	}
+	shapeB, err := periodShape(*b)
+	if err != nil {
+		return false, err
+	}
+	if !bytes.Equal(shapeA, shapeB) {
+		return false, nil
+	}
+
+	startA, err := m.PeriodStart(i)
+	if err != nil {
+		return false, err
+	}
+	startB, err := m.PeriodStart(i + 1)
+	if err != nil {
+		return false, err
+	}
+
+	// check all Representations first, so Period is either merged completely or left untouched;
+	// timelines inherited from AdaptationSet or Period are merged where they are defined
+	merged := map[*SegmentTemplate][]SegmentTimelineS{}
+	for ai, as := range a.AdaptationSets {
+		if as == nil {
+			continue
+		}
+		for ri := range as.Representations {
+			r := &as.Representations[ri]
+			st := a.EffectiveSegmentTemplate(as, r)
+			next := b.EffectiveSegmentTemplate(b.AdaptationSets[ai], &b.AdaptationSets[ai].Representations[ri])
+			owner := timelineOwner(a, as, r)
+			if st == nil || next == nil || owner == nil ||
+				hasNegativeRepeat(st.SegmentTimelineS) || hasNegativeRepeat(next.SegmentTimelineS) {
+				return false, nil
+			}
+			segments, nextSegments := st.Segments(), next.Segments()
+			if len(segments) == 0 || len(nextSegments) == 0 {
+				return false, nil
+			}
+
+			timescale := st.EffectiveTimescale()
+			// media time of the Period b start in Period a timeline
+			origin := st.EffectivePresentationTimeOffset() + durationToTicks(startB-startA, timescale)
+			nextPTO := next.EffectivePresentationTimeOffset()
+			last := segments[len(segments)-1]
+			for _, s := range nextSegments {
+				if s.Time+origin < nextPTO {
+					return false, nil
+				}
+				s.Time = s.Time + origin - nextPTO
+				segments = append(segments, s)
+			}
+			if nextSegments[0].Number != last.Number+1 || segments[len(segments)-len(nextSegments)].Time != last.Time+last.Duration {
+				return false, nil
+			}
+			timeline := compactTimeline(segments, st.EffectiveStartNumber())
+			if prev, ok := merged[owner]; ok && !reflect.DeepEqual(prev, timeline) {
+				// shared timeline doesn't continue the same way for all Representations
+				return false, nil
+			}
+			merged[owner] = timeline
+		}
+	}
+
+	for owner, timeline := range merged {
+		owner.SegmentTimelineS = timeline
+	}
+	if a.Duration != nil {
+		if b.Duration == nil {
+			a.Duration = nil
+		} else {
+			d, err := parseDuration(*b.Duration)
+			if err != nil {
+				return false, err
+			}
+			a.Duration = Duration(startB - startA + d).Attr()
+		}
+	}
+	return true, nil
+}
+
+// FitSizeBudget applies size mitigations until encoded MPD fits into budget bytes: timelines compaction,
+// attributes hoisting and Periods consolidation, in this order. MPD is modified in place.
+// It returns the final encoded size and an error if the budget can't be met.
+func (m *MPD) FitSizeBudget(budget int) (int, error) {
+	mitigations := []func() error{
+		func() error { m.CompactTimelines(); return nil },
+		func() error { m.HoistAttributes(); return nil },
+		func() error { _, err := m.ConsolidatePeriods(); return err },
+	}
+
+	size, err := m.encodedSize()
+	if err != nil {
+		return 0, err
+	}
+	for _, mitigate := range mitigations {
+		if size <= budget {
+			return size, nil
+		}
+		if err := mitigate(); err != nil {
+			return 0, err
+		}
+		if size, err = m.encodedSize(); err != nil {
+			return 0, err
+		}
+	}
+	if size > budget {
+		return size, fmt.Errorf("encoded MPD size %d exceeds budget %d", size, budget)
+	}
+	return size, nil
+}
+
+func (m *MPD) encodedSize() (int, error) {
+	b, err := m.Encode()
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
@@ -0,0 +1,181 @@
+package mpd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeReport(t *testing.T) {
+	m := decodeFixture(t, "fixture_elemental_delta_live.mpd")
+	b, err := m.Encode()
+	require.NoError(t, err)
+
+	report, err := m.SizeReport()
+	require.NoError(t, err)
+	require.Equal(t, len(b), report.Total)
+
+	var sum int
+	for _, size := range report.Elements {
+		sum += size
+	}
+	require.Equal(t, report.Total, sum)
+	require.Greater(t, report.Elements["Representation"], report.Elements["AdaptationSet"])
+	require.Greater(t, report.Elements["pssh"], 0)
+	require.Greater(t, report.Elements[""], 0)
+}
+
+func TestCompactTimelines(t *testing.T) {
+	st := &SegmentTemplate{SegmentTimelineS: []SegmentTimelineS{
+		{T: uint64Ptr(100), D: 10},
+		{T: uint64Ptr(110), D: 10},
+		{D: 10, R: int64Ptr(1)},
+		{D: 5},
+		{T: uint64Ptr(200), D: 5},
+	}}
+	negative := &SegmentTemplate{SegmentTimelineS: []SegmentTimelineS{{T: uint64Ptr(0), D: 10, R: int64Ptr(-1)}}}
+	m := &MPD{Period: []Period{{AdaptationSets: []*AdaptationSet{{Representations: []Representation{
+		{SegmentTemplate: st},
+		{SegmentTemplate: negative},
+	}}}}}}
+
+	m.CompactTimelines()
+	require.Equal(t, []SegmentTimelineS{
+		{T: uint64Ptr(100), D: 10, R: int64Ptr(3)},
+		{D: 5},
+		{T: uint64Ptr(200), D: 5},
+	}, st.SegmentTimelineS)
+	require.Equal(t, []SegmentTimelineS{{T: uint64Ptr(0), D: 10, R: int64Ptr(-1)}}, negative.SegmentTimelineS)
+
+	// numbering discontinuities are kept
+	st.StartNumber = uint64Ptr(10)
+	st.SegmentTimelineS = []SegmentTimelineS{
+		{T: uint64Ptr(0), N: uint64Ptr(1), D: 10},
+		{D: 10},
+		{N: uint64Ptr(5), D: 10},
+		{D: 10},
+	}
+	m.CompactTimelines()
+	require.Equal(t, []SegmentTimelineS{
+		{T: uint64Ptr(0), N: uint64Ptr(1), D: 10, R: int64Ptr(1)},
+		{N: uint64Ptr(5), D: 10, R: int64Ptr(1)},
+	}, st.SegmentTimelineS)
+}
+
+func TestHoistAttributes(t *testing.T) {
+	m := decodeFixture(t, "fixture_flussonic_live.mpd")
+	video, audio := m.Period[0].AdaptationSets[0], m.Period[0].AdaptationSets[1]
+	audioCodecs := *audio.Representations[0].Codecs
+	m.HoistAttributes()
+
+	// video codecs differ between Representations
+	require.Nil(t, video.Codecs)
+	require.NotNil(t, video.Representations[0].Codecs)
+	require.Equal(t, audioCodecs, *audio.Codecs)
+	require.Nil(t, audio.Representations[0].Codecs)
+
+	m = decodeFixture(t, "fixture_elemental_delta_live.mpd")
+	before := m.Period[0].AdaptationSets[0]
+	protections := before.Representations[0].ContentProtections
+	m.Period[0].AdaptationSets[0].Representations = before.Representations[:3]
+	m.HoistAttributes()
+	require.Equal(t, protections, before.ContentProtections)
+	for _, r := range before.Representations {
+		require.Nil(t, r.ContentProtections)
+	}
+}
+
+func consolidationMPD() *MPD {
+	period := func(start, id, duration string, pto, startNumber uint64, t uint64, r int64) Period {
+		return Period{
+			Start:    stringPtr(start),
+			ID:       stringPtr(id),
+			Duration: stringPtr(duration),
+			AdaptationSets: []*AdaptationSet{{
+				MimeType: "video/mp4",
+				Representations: []Representation{{
+					ID: stringPtr("v"),
+					SegmentTemplate: &SegmentTemplate{
+						Timescale:              uint64Ptr(10),
+						Media:                  stringPtr("$Number$.m4s"),
+						StartNumber:            uint64Ptr(startNumber),
+						PresentationTimeOffset: uint64Ptr(pto),
+						SegmentTimelineS:       []SegmentTimelineS{{T: uint64Ptr(t), D: 20, R: int64Ptr(r)}},
+					},
+				}},
+			}},
+		}
+	}
+	return &MPD{Period: []Period{
+		period("PT0S", "p0", "PT10S", 0, 1, 0, 4),
+		period("PT10S", "p1", "PT6S", 1000, 6, 1000, 2),
+		// numbering restarts
+		period("PT16S", "p2", "PT4S", 0, 1, 0, 1),
+	}}
+}
+
+func TestConsolidatePeriods(t *testing.T) {
+	m := consolidationMPD()
+	removed, err := m.ConsolidatePeriods()
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+	require.Len(t, m.Period, 2)
+	require.Equal(t, "p0", *m.Period[0].ID)
+	require.Equal(t, "PT16S", *m.Period[0].Duration)
+	require.Equal(t, []SegmentTimelineS{{T: uint64Ptr(0), D: 20, R: int64Ptr(7)}},
+		m.Period[0].AdaptationSets[0].Representations[0].SegmentTemplate.SegmentTimelineS)
+	require.Equal(t, "p2", *m.Period[1].ID)
+
+	m = consolidationMPD()
+	m.Period[1].AdaptationSets[0].MimeType = "audio/mp4"
+	removed, err = m.ConsolidatePeriods()
+	require.NoError(t, err)
+	require.Equal(t, 0, removed)
+}
+
+func TestConsolidatePeriodsInheritedTimeline(t *testing.T) {
+	m := decodeFixture(t, "fixture_adaptation_set_template.mpd")
+	m.MediaPresentationDuration = stringPtr("PT20S")
+	next := m.Period[0].Clone()
+	next.ID, next.Start = stringPtr("1"), stringPtr("PT12S")
+	next.AdaptationSets[0].SegmentTemplate.StartNumber = uint64Ptr(4)
+	next.AdaptationSets[0].SegmentTemplate.PresentationTimeOffset = uint64Ptr(1080000)
+	next.AdaptationSets[0].SegmentTemplate.SegmentTimelineS = []SegmentTimelineS{{T: uint64Ptr(1080000), D: 360000, R: int64Ptr(1)}}
+	m.Period = append(m.Period, *next)
+
+	removed, err := m.ConsolidatePeriods()
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+	require.Equal(t, []SegmentTimelineS{{T: uint64Ptr(0), D: 360000, R: int64Ptr(4)}},
+		m.Period[0].AdaptationSets[0].SegmentTemplate.SegmentTimelineS)
+	require.Nil(t, m.Period[0].AdaptationSets[0].Representations[0].SegmentTemplate)
+
+	// AdaptationSet timeline is compacted where it's defined
+	m.Period[0].AdaptationSets[0].SegmentTemplate.SegmentTimelineS = []SegmentTimelineS{
+		{T: uint64Ptr(0), D: 360000},
+		{D: 360000, R: int64Ptr(3)},
+	}
+	m.CompactTimelines()
+	require.Equal(t, []SegmentTimelineS{{T: uint64Ptr(0), D: 360000, R: int64Ptr(4)}},
+		m.Period[0].AdaptationSets[0].SegmentTemplate.SegmentTimelineS)
+}
+
+func TestFitSizeBudget(t *testing.T) {
+	m := consolidationMPD()
+	initial, err := m.encodedSize()
+	require.NoError(t, err)
+
+	size, err := m.FitSizeBudget(initial)
+	require.NoError(t, err)
+	require.Equal(t, initial, size)
+	require.Len(t, m.Period, 3)
+
+	size, err = m.FitSizeBudget(initial - 1)
+	require.NoError(t, err)
+	require.Less(t, size, initial)
+	require.Len(t, m.Period, 2)
+
+	_, err = m.FitSizeBudget(100)
+	require.EqualError(t, err, fmt.Sprintf("encoded MPD size %d exceeds budget 100", size))
+}
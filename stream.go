@@ -0,0 +1,171 @@
+package mpd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+)
+
+// xmlHeader is written before MPD element by default.
+const xmlHeader = `<?xml version="1.0" encoding="utf-8"?>`
+
+// EncodeOption configures MPD XML output of Encoder and MPD.Encode.
+type EncodeOption func(*encodeOptions)
+
+type encodeOptions struct {
+	indent    string
+	header    string
+	canonical bool
+}
+
+// WithIndent sets indentation string of nested elements, two spaces by default.
+// Empty indent produces compact output with no line breaks.
+func WithIndent(indent string) EncodeOption {
+	return func(o *encodeOptions) { o.indent = indent }
+}
+
+// WithCompact produces compact output with no indentation and line breaks.
+func WithCompact() EncodeOption {
+	return WithIndent("")
+}
+
+// WithHeader replaces the XML declaration written before MPD element with header,
+// e.g. to add a comment. It's written as is and followed by a line break unless output is compact.
+func WithHeader(header string) EncodeOption {
+	return func(o *encodeOptions) { o.header = header }
+}
+
+// WithoutXMLHeader omits the XML declaration.
+func WithoutXMLHeader() EncodeOption {
+	return WithHeader("")
+}
+
+// Encoder writes MPD XML to an output stream.
+type Encoder struct {
+	w    io.Writer
+	opts encodeOptions
+}
+
+// NewEncoder returns Encoder writing to w configured with opts.
+func NewEncoder(w io.Writer, opts ...EncodeOption) *Encoder {
+	e := &Encoder{w: w, opts: encodeOptions{indent: "  ", header: xmlHeader}}
+	for _, opt := range opts {
+		opt(&e.opts)
+	}
+	return e
+}
+
+// Encode writes MPD XML to the stream, by default the output is the same as the one of MPD.Encode.
+// The stream is written line by line, so w may receive partial output if error is returned.
+func (e *Encoder) Encode(m *MPD) error {
+	var newline string
+	if e.opts.indent != "" {
+		newline = "\n"
+	}
+	if e.opts.header != "" {
+		if _, err := io.WriteString(e.w, e.opts.header+newline); err != nil {
+			return err
+		}
+	}
+	mm := modifyMPD(m)
+	if e.opts.canonical {
+		canonicalize(mm)
+	}
+	lw := &selfClosingWriter{w: e.w}
+	xe := xml.NewEncoder(lw)
+	xe.Indent("", e.opts.indent)
+	if err := xe.Encode(mm); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(lw, newline); err != nil {
+		return err
+	}
+	return lw.flush()
+}
+
+// selfClosingWriter rewrites empty elements of every complete line to self-closing tags before passing it to w.
+type selfClosingWriter struct {
+	w   io.Writer
+	buf []byte
+}
+
+func (s *selfClosingWriter) Write(p []byte) (int, error) {
+	s.buf = append(s.buf, p...)
+	for {
+		i := bytes.IndexByte(s.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		if _, err := s.w.Write(emptyElementRE.ReplaceAll(s.buf[:i+1], []byte(`$1/>`))); err != nil {
+			return 0, err
+		}
+		s.buf = s.buf[i+1:]
+	}
+}
+
+// flush writes the last incomplete line.
+func (s *selfClosingWriter) flush() error {
+	if len(s.buf) == 0 {
+		return nil
+	}
+	_, err := s.w.Write(emptyElementRE.ReplaceAll(s.buf, []byte(`$1/>`)))
+	s.buf = nil
+	return err
+}
+
+// Decoder reads MPD XML from an input stream.
+type Decoder struct {
+	d *xml.Decoder
+}
+
+// NewDecoder returns Decoder reading from r, it may read beyond the end of MPD element.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{d: xml.NewDecoder(r)}
+}
+
+// Decode parses MPD XML from the stream into m.
+func (d *Decoder) Decode(m *MPD) error {
+	return d.d.Decode(m)
+}
+
+// WriteTo implements io.WriterTo, it writes MPD XML to w and returns the number of bytes written.
+func (m *MPD) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	err := NewEncoder(cw).Encode(m)
+	return cw.n, err
+}
+
+// ReadFrom implements io.ReaderFrom, it parses MPD XML from r and returns the number of bytes read.
+func (m *MPD) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countingReader{r: r}
+	err := NewDecoder(cr).Decode(m)
+	return cr.n, err
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// check interfaces
+var (
+	_ io.WriterTo   = new(MPD)
+	_ io.ReaderFrom = new(MPD)
+)
@@ -0,0 +1,92 @@
+package mpd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+type failingWriter struct {
+	limit int
+}
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > f.limit {
+		return 0, errors.New("write failed")
+	}
+	f.limit -= len(p)
+	return len(p), nil
+}
+
+func TestReadFromWriteTo(t *testing.T) {
+	f, err := os.Open("fixture_elemental_delta_vod_multi_drm.mpd")
+	require.NoError(t, err)
+	defer f.Close()
+	info, err := f.Stat()
+	require.NoError(t, err)
+
+	m := new(MPD)
+	n, err := m.ReadFrom(iotest.OneByteReader(f))
+	require.NoError(t, err)
+	// decoding stops at the end of MPD element, trailing newline isn't read
+	require.Equal(t, info.Size()-1, n)
+	require.Equal(t, decodeFixture(t, "fixture_elemental_delta_vod_multi_drm.mpd"), m)
+
+	expected, err := m.Encode()
+	require.NoError(t, err)
+	buf := new(bytes.Buffer)
+	n, err = m.WriteTo(buf)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(expected)), n)
+	require.Equal(t, string(expected), buf.String())
+
+	n, err = m.WriteTo(&failingWriter{limit: 100})
+	require.EqualError(t, err, "write failed")
+	require.True(t, n <= 100)
+
+	_, err = new(MPD).ReadFrom(bytes.NewReader([]byte("<MPD>")))
+	require.Error(t, err)
+}
+
+func TestEncoderDecoder(t *testing.T) {
+	buf := new(bytes.Buffer)
+	e := NewEncoder(buf)
+	m := &MPD{Profiles: ProfileISOFFLive, Period: []Period{{ID: stringPtr("1")}}}
+	require.NoError(t, e.Encode(m))
+	require.NoError(t, e.Encode(m))
+
+	d := NewDecoder(buf)
+	for i := 0; i < 2; i++ {
+		res := new(MPD)
+		require.NoError(t, d.Decode(res))
+		require.Equal(t, "1", *res.Period[0].ID)
+	}
+}
+
+func TestEncodeOptions(t *testing.T) {
+	m := &MPD{XMLNS: stringPtr(DASHNamespace), Profiles: ProfileISOFFLive, Period: []Period{{ID: stringPtr("1"), BaseURL: []BaseURL{{Value: "http://a/"}}}}}
+
+	b, err := m.Encode(WithCompact())
+	require.NoError(t, err)
+	require.Equal(t, `<?xml version="1.0" encoding="utf-8"?><MPD xmlns="urn:mpeg:dash:schema:mpd:2011" profiles="urn:mpeg:dash:profile:isoff-live:2011">`+
+		`<Period id="1"><BaseURL>http://a/</BaseURL></Period></MPD>`, string(b))
+
+	b, err = m.Encode(WithIndent("\t"), WithoutXMLHeader())
+	require.NoError(t, err)
+	require.Equal(t, "<MPD xmlns=\"urn:mpeg:dash:schema:mpd:2011\" profiles=\"urn:mpeg:dash:profile:isoff-live:2011\">\n"+
+		"\t<Period id=\"1\">\n\t\t<BaseURL>http://a/</BaseURL>\n\t</Period>\n</MPD>\n", string(b))
+
+	m.Period[0].BaseURL = nil
+	b, err = m.Encode(WithCompact(), WithHeader("<!-- generated -->"))
+	require.NoError(t, err)
+	require.Equal(t, `<!-- generated --><MPD xmlns="urn:mpeg:dash:schema:mpd:2011" profiles="urn:mpeg:dash:profile:isoff-live:2011">`+
+		`<Period id="1"/></MPD>`, string(b))
+
+	res := new(MPD)
+	require.NoError(t, res.Decode(b))
+	require.Equal(t, "1", *res.Period[0].ID)
+}
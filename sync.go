@@ -0,0 +1,77 @@
+package mpd
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultTolerance is the timing mismatch tolerance used by Validate.
+const DefaultTolerance = 100 * time.Millisecond
+
+// adaptationSetContentType returns AdaptationSet@contentType or content type guessed from its mimeType.
+func adaptationSetContentType(as *AdaptationSet) string {
+	if as.ContentType != nil {
+		return *as.ContentType
+	}
+	return mimeTypeContentType(as.MimeType)
+}
+
+// CheckPresentationTimeOffsets compares effective presentationTimeOffset of audio and video Representations
+// within each Period and reports offsets differing from the first video Representation more than tolerance,
+// which causes A/V desync at Period boundaries.
+func CheckPresentationTimeOffsets(m *MPD, tolerance time.Duration) []Finding {
+	var res []Finding
+	for pi, p := range m.Period {
+		var refPath string
+		var ref time.Duration
+		found := false
+
+		type offset struct {
+			path string
+			pto  time.Duration
+		}
+		var offsets []offset
+		for ai, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			ct := adaptationSetContentType(as)
+			if ct != ContentTypeVideo && ct != ContentTypeAudio {
+				continue
+			}
+			for ri := range as.Representations {
+				st := p.EffectiveSegmentTemplate(as, &as.Representations[ri])
+				if st == nil {
+					continue
+				}
+				o := offset{
+					path: representationPath(pi, ai, ri),
+					pto:  st.PresentationTimeOffsetDuration(),
+				}
+				if !found && ct == ContentTypeVideo {
+					refPath, ref, found = o.path, o.pto, true
+				}
+				offsets = append(offsets, o)
+			}
+		}
+		if !found {
+			continue
+		}
+
+		for _, o := range offsets {
+			if diff := absDuration(o.pto - ref); diff > tolerance {
+				res = append(res, Finding{
+					Severity: SeverityWarning,
+					Path:     o.path,
+					Message: fmt.Sprintf("presentationTimeOffset %s differs from %s of %s by %s",
+						o.pto, ref, refPath, diff),
+				})
+			}
+		}
+	}
+	return res
+}
+
+func validatePresentationTimeOffsets(m *MPD) []Finding {
+	return CheckPresentationTimeOffsets(m, DefaultTolerance)
+}
@@ -0,0 +1,32 @@
+package mpd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckPresentationTimeOffsets(t *testing.T) {
+	m := decodeFixture(t, "fixture_elemental_delta_vod_multi_drm.mpd")
+	require.Empty(t, CheckPresentationTimeOffsets(m, DefaultTolerance))
+
+	var audio *AdaptationSet
+	for _, as := range m.Period[0].AdaptationSets {
+		if as.MimeType == "audio/mp4" {
+			audio = as
+			break
+		}
+	}
+	require.NotNil(t, audio)
+	st := audio.Representations[0].SegmentTemplate
+	pto := st.EffectivePresentationTimeOffset() + st.EffectiveTimescale()
+	st.PresentationTimeOffset = &pto
+
+	findings := CheckPresentationTimeOffsets(m, DefaultTolerance)
+	require.Len(t, findings, 1)
+	require.Equal(t, SeverityWarning, findings[0].Severity)
+	require.Contains(t, findings[0].Message, "differs from 2.04s of MPD/Period[0]/AdaptationSet[0]/Representation[0] by 966.666666ms")
+	require.Empty(t, CheckPresentationTimeOffsets(m, 2*time.Second))
+	require.Contains(t, m.Validate(), findings[0])
+}
@@ -0,0 +1,264 @@
+package mpd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Template identifiers.
+const (
+	templateRepresentationID = "RepresentationID"
+	templateNumber           = "Number"
+	templateBandwidth        = "Bandwidth"
+	templateTime             = "Time"
+	templateSubNumber        = "SubNumber"
+)
+
+// TemplateValues holds values for SegmentTemplate identifiers substitution.
+type TemplateValues struct {
+	RepresentationID string
+	Number           uint64
+	Bandwidth        uint64
+	Time             uint64
+	SubNumber        uint64
+}
+
+// TemplateError describes a problem at a position of SegmentTemplate media or initialization string.
+type TemplateError struct {
+	// Pos is a byte offset of the problem in template.
+	Pos     int
+	Message string
+}
+
+// Error implements error interface.
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Pos)
+}
+
+func templateErrorf(pos int, format string, args ...interface{}) *TemplateError {
+	return &TemplateError{Pos: pos, Message: fmt.Sprintf(format, args...)}
+}
+
+// templatePart is a literal text or an identifier of parsed template.
+type templatePart struct {
+	// Pos is a byte offset of the part in template.
+	Pos int
+	// Literal is set for text parts.
+	Literal string
+	// Identifier is set for identifier parts, with optional printf-style Format (e.g. "%05d").
+	Identifier string
+	Format     string
+}
+
+// parseTemplate splits template into parts, "$$" is unescaped to "$".
+// An unterminated identifier is reported, and the rest of template is kept as a literal.
+func parseTemplate(template string) ([]templatePart, []*TemplateError) {
+	var res []templatePart
+	var errs []*TemplateError
+	var literal strings.Builder
+	literalPos := 0
+	flush := func() {
+		if literal.Len() > 0 {
+			res = append(res, templatePart{Pos: literalPos, Literal: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(template); {
+		if template[i] != '$' {
+			if literal.Len() == 0 {
+				literalPos = i
+			}
+			literal.WriteByte(template[i])
+			i++
+			continue
+		}
+
+		end := strings.IndexByte(template[i+1:], '$')
+		if end < 0 {
+			errs = append(errs, templateErrorf(i, "unterminated identifier"))
+			if literal.Len() == 0 {
+				literalPos = i
+			}
+			literal.WriteString(template[i:])
+			break
+		}
+		end += i + 1
+		if end == i+1 {
+			if literal.Len() == 0 {
+				literalPos = i
+			}
+			literal.WriteByte('$')
+			i = end + 1
+			continue
+		}
+
+		flush()
+		part := templatePart{Pos: i, Identifier: template[i+1 : end]}
+		if j := strings.IndexByte(part.Identifier, '%'); j >= 0 {
+			part.Identifier, part.Format = part.Identifier[:j], part.Identifier[j:]
+		}
+		res = append(res, part)
+		i = end + 1
+	}
+	flush()
+	return res, errs
+}
+
+// check validates identifier and its format tag, it returns width of the format tag or 0 if there is none.
+func (p templatePart) check() (int, *TemplateError) {
+	switch p.Identifier {
+	case templateRepresentationID:
+		if p.Format != "" {
+			return 0, templateErrorf(p.Pos, "format tag is not allowed for $%s$", p.Identifier)
+		}
+	case templateNumber, templateBandwidth, templateTime, templateSubNumber:
+		if p.Format != "" {
+			width, err := parseTemplateFormat(p.Format)
+			if err != nil {
+				return 0, templateErrorf(p.Pos, "%s", err)
+			}
+			return width, nil
+		}
+	default:
+		return 0, templateErrorf(p.Pos, "unknown identifier $%s$", p.Identifier)
+	}
+	return 0, nil
+}
+
+// ExpandTemplate substitutes identifiers in SegmentTemplate media or initialization template.
+func ExpandTemplate(template string, values TemplateValues) (string, error) {
+	parts, errs := parseTemplate(template)
+	if len(errs) > 0 {
+		return "", errs[0]
+	}
+
+	var res strings.Builder
+	for _, p := range parts {
+		if p.Identifier == "" {
+			res.WriteString(p.Literal)
+			continue
+		}
+		width, err := p.check()
+		if err != nil {
+			return "", err
+		}
+
+		var v uint64
+		switch p.Identifier {
+		case templateRepresentationID:
+			res.WriteString(values.RepresentationID)
+			continue
+		case templateNumber:
+			v = values.Number
+		case templateBandwidth:
+			v = values.Bandwidth
+		case templateTime:
+			v = values.Time
+		case templateSubNumber:
+			v = values.SubNumber
+		}
+
+		s := strconv.FormatUint(v, 10)
+		if pad := width - len(s); pad > 0 {
+			s = strings.Repeat("0", pad) + s
+		}
+		res.WriteString(s)
+	}
+	return res.String(), nil
+}
+
+// parseTemplateFormat parses width format tag "%0[width]d" and returns width.
+func parseTemplateFormat(format string) (int, error) {
+	if len(format) < 4 || !strings.HasPrefix(format, "%0") || format[len(format)-1] != 'd' {
+		return 0, fmt.Errorf("invalid format tag %q", format)
+	}
+	width, err := strconv.Atoi(format[2 : len(format)-1])
+	if err != nil || width <= 0 {
+		return 0, fmt.Errorf("invalid format tag %q", format)
+	}
+	return width, nil
+}
+
+// CheckTemplate validates media (or initialization if initialization is true) template and returns all found problems:
+// unterminated identifiers, unknown identifiers, invalid width format tags, $Time$ without SegmentTimeline
+// and $Number$/$Time$ in initialization template.
+func CheckTemplate(template string, initialization, hasTimeline bool) []*TemplateError {
+	parts, parseErrs := parseTemplate(template)
+	var res []*TemplateError
+	for _, p := range parts {
+		if p.Identifier == "" {
+			continue
+		}
+		if _, err := p.check(); err != nil {
+			res = append(res, err)
+		}
+		switch {
+		case initialization && (p.Identifier == templateNumber || p.Identifier == templateTime || p.Identifier == templateSubNumber):
+			res = append(res, templateErrorf(p.Pos, "$%s$ is not allowed in initialization template", p.Identifier))
+		case !initialization && p.Identifier == templateTime && !hasTimeline:
+			res = append(res, templateErrorf(p.Pos, "$Time$ is used without SegmentTimeline"))
+		}
+	}
+	// an unterminated identifier can only be the last problem
+	return append(res, parseErrs...)
+}
+
+// CheckTemplates validates media and initialization templates of SegmentTemplate.
+func (st *SegmentTemplate) CheckTemplates() (media, initialization []*TemplateError) {
+	hasTimeline := len(st.SegmentTimelineS) > 0
+	if st.Media != nil {
+		media = CheckTemplate(*st.Media, false, hasTimeline)
+	}
+	if st.Initialization != nil {
+		initialization = CheckTemplate(*st.Initialization, true, hasTimeline)
+	}
+	return media, initialization
+}
+
+// validateTemplates checks media and initialization strings of effective SegmentTemplate of every Representation.
+// Inherited attributes are reported once, at the level where they are defined.
+func validateTemplates(m *MPD) []Finding {
+	var res []Finding
+	seen := map[Finding]bool{}
+	add := func(path string, errs []*TemplateError) {
+		for _, e := range errs {
+			f := Finding{Severity: SeverityError, Path: path, Message: e.Error()}
+			if !seen[f] {
+				seen[f] = true
+				res = append(res, f)
+			}
+		}
+	}
+
+	for pi := range m.Period {
+		p := &m.Period[pi]
+		for ai, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			for ri := range as.Representations {
+				r := &as.Representations[ri]
+				st := p.EffectiveSegmentTemplate(as, r)
+				if st == nil {
+					continue
+				}
+				definedAt := func(attr func(st *SegmentTemplate) *string) string {
+					switch {
+					case r.SegmentTemplate != nil && attr(r.SegmentTemplate) != nil:
+						return representationPath(pi, ai, ri) + "/SegmentTemplate"
+					case as.SegmentTemplate != nil && attr(as.SegmentTemplate) != nil:
+						return adaptationSetPath(pi, ai) + "/SegmentTemplate"
+					default:
+						return periodPath(pi) + "/SegmentTemplate"
+					}
+				}
+				media, initialization := st.CheckTemplates()
+				add(definedAt(func(st *SegmentTemplate) *string { return st.Media })+"@media", media)
+				add(definedAt(func(st *SegmentTemplate) *string { return st.Initialization })+"@initialization", initialization)
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,63 @@
+package mpd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandTemplate(t *testing.T) {
+	values := TemplateValues{RepresentationID: "v1", Number: 42, Bandwidth: 196000, Time: 380620753}
+	for template, expected := range map[string]string{
+		"$RepresentationID$/seg-$Number$.m4v?t=$Time$": "v1/seg-42.m4v?t=380620753",
+		"$Bandwidth$/$Number%05d$.mp4":                 "196000/00042.mp4",
+		"$Number%01d$":                                 "42",
+		"a$$b$$$Number$":                               "a$b$42",
+		"init.mp4":                                     "init.mp4",
+	} {
+		s, err := ExpandTemplate(template, values)
+		require.NoError(t, err, template)
+		require.Equal(t, expected, s, template)
+	}
+
+	for template, expected := range map[string]string{
+		"seg-$Number":             "unterminated identifier at position 4",
+		"$Foo$":                   "unknown identifier $Foo$ at position 0",
+		"x$RepresentationID%02d$": "format tag is not allowed for $RepresentationID$ at position 1",
+		"$Number%5d$":             `invalid format tag "%5d" at position 0`,
+		"$Time%0xd$":              `invalid format tag "%0xd" at position 0`,
+	} {
+		_, err := ExpandTemplate(template, values)
+		require.EqualError(t, err, expected, template)
+	}
+}
+
+func TestCheckTemplate(t *testing.T) {
+	require.Empty(t, CheckTemplate("$RepresentationID$/seg-$Number%05d$.m4v?t=$Time$&$$", false, true))
+	require.Empty(t, CheckTemplate("$RepresentationID$/$Bandwidth$/init.mp4", true, false))
+
+	require.Equal(t, []*TemplateError{
+		{Pos: 0, Message: "unknown identifier $Foo$"},
+		{Pos: 5, Message: `invalid format tag "%d"`},
+		{Pos: 15, Message: "format tag is not allowed for $RepresentationID$"},
+		{Pos: 40, Message: "$Time$ is used without SegmentTimeline"},
+		{Pos: 49, Message: "unterminated identifier"},
+	}, CheckTemplate("$Foo$$Number%d$$RepresentationID%02d$/a/$Time$/b/$c", false, false))
+
+	require.Equal(t, []*TemplateError{
+		{Pos: 5, Message: "$Number$ is not allowed in initialization template"},
+		{Pos: 14, Message: "$Time$ is not allowed in initialization template"},
+	}, CheckTemplate("init-$Number$-$Time$.mp4", true, true))
+}
+
+func TestValidateTemplates(t *testing.T) {
+	m := decodeFixture(t, "fixture_flussonic_live.mpd")
+	st := m.Period[0].AdaptationSets[1].Representations[0].SegmentTemplate
+	st.Media = stringPtr("$RepresentationID$/$Number$.m4s$")
+	st.Initialization = stringPtr("$RepresentationID$/init-$Time$.mp4")
+
+	require.Equal(t, []Finding{
+		{SeverityError, "MPD/Period[0]/AdaptationSet[1]/Representation[0]/SegmentTemplate@media", "unterminated identifier at position 31"},
+		{SeverityError, "MPD/Period[0]/AdaptationSet[1]/Representation[0]/SegmentTemplate@initialization", "$Time$ is not allowed in initialization template at position 24"},
+	}, m.Validate())
+}
@@ -0,0 +1,193 @@
+package mpd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TimelineSegment describes single segment of RepresentationTimeline.
+type TimelineSegment struct {
+	Number uint64 `json:"number"`
+	// Start is a segment start in seconds from the start of the presentation.
+	Start float64 `json:"start"`
+	// Duration is a segment duration in seconds.
+	Duration float64 `json:"duration"`
+	// AvailabilityStart and AvailabilityEnd are set for dynamic MPD only,
+	// AvailabilityStart is not set if availabilityTimeOffset is "INF" (segment is always available),
+	// AvailabilityEnd is not set if timeShiftBufferDepth is unknown.
+	AvailabilityStart *time.Time `json:"availabilityStart,omitempty"`
+	AvailabilityEnd   *time.Time `json:"availabilityEnd,omitempty"`
+}
+
+// RepresentationTimeline describes segments of a single Representation within a single Period.
+type RepresentationTimeline struct {
+	Period         string            `json:"period"`
+	AdaptationSet  int               `json:"adaptationSet"`
+	Representation string            `json:"representation"`
+	Bandwidth      uint64            `json:"bandwidth,omitempty"`
+	Segments       []TimelineSegment `json:"segments"`
+
+	// segmentBase is true for SegmentBase Representation with a single segment covering the whole Period.
+	segmentBase bool
+}
+
+// PeriodStart returns start of Period with index i from the start of the presentation.
+// If Period@start is absent it is calculated from the previous Period's start and duration.
+func (m *MPD) PeriodStart(i int) (time.Duration, error) {
+	if i < 0 || i >= len(m.Period) {
+		return 0, fmt.Errorf("no Period with index %d", i)
+	}
+	p := m.Period[i]
+	if p.Start != nil {
+		return parseDuration(*p.Start)
+	}
+	if i == 0 {
+		return 0, nil
+	}
+	prev := m.Period[i-1]
+	if prev.Duration == nil {
+		return 0, fmt.Errorf("can't calculate Period %d start: previous Period has no duration", i)
+	}
+	start, err := m.PeriodStart(i - 1)
+	if err != nil {
+		return 0, err
+	}
+	d, err := parseDuration(*prev.Duration)
+	if err != nil {
+		return 0, err
+	}
+	return start + d, nil
+}
+
+// periodName returns Period@id or its index if id is not set.
+func periodName(i int, id *string) string {
+	if id == nil {
+		return fmt.Sprint(i)
+	}
+	return *id
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// IsDynamic reports whether MPD@type is "dynamic".
+func (m *MPD) IsDynamic() bool {
+	return m.Type != nil && *m.Type == PresentationTypeDynamic
+}
+
+// IsStatic reports whether MPD@type is "static", which is the default.
+func (m *MPD) IsStatic() bool {
+	return m.Type == nil || *m.Type == PresentationTypeStatic
+}
+
+// Timelines returns segment timelines of all Representations. SegmentBase Representation has a single
+// segment covering the whole Period.
+func (m *MPD) Timelines() ([]RepresentationTimeline, error) {
+	var ast time.Time
+	var tsbd time.Duration
+	var err error
+	dynamic := m.IsDynamic()
+	if dynamic {
+		if m.AvailabilityStartTime == nil {
+			return nil, fmt.Errorf("dynamic MPD without availabilityStartTime")
+		}
+		ast = m.AvailabilityStartTime.Time
+		if tsbd, err = parseOptionalDuration(m.TimeShiftBufferDepth); err != nil {
+			return nil, err
+		}
+	}
+
+	var res []RepresentationTimeline
+	for pi, p := range m.Period {
+		periodStart, err := m.PeriodStart(pi)
+		if err != nil {
+			return nil, err
+		}
+		periodDuration, _, err := m.PeriodDuration(pi)
+		if err != nil {
+			return nil, err
+		}
+
+		for ai, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			for ri := range as.Representations {
+				r := &as.Representations[ri]
+				var segments []Segment
+				var timescale, pto uint64
+				var ato *AvailabilityTimeOffset
+				var segmentBase bool
+				if st := p.EffectiveSegmentTemplate(as, r); st != nil {
+					segments = st.SegmentsWithin(periodDuration)
+					timescale, pto, ato = st.EffectiveTimescale(), st.EffectivePresentationTimeOffset(), st.AvailabilityTimeOffset
+				} else if sl := p.EffectiveSegmentList(as, r); sl != nil {
+					segments = sl.Segments()
+					timescale, pto, ato = sl.EffectiveTimescale(), sl.EffectivePresentationTimeOffset(), sl.AvailabilityTimeOffset
+				} else if sb := p.EffectiveSegmentBase(as, r); sb != nil {
+					// the whole Period is a single segment, subsegments are known only from the segment index
+					timescale, pto, ato = sb.EffectiveTimescale(), sb.EffectivePresentationTimeOffset(), sb.AvailabilityTimeOffset
+					segments = []Segment{{Time: pto, Duration: durationToTicks(periodDuration, timescale)}}
+					segmentBase = true
+				} else {
+					continue
+				}
+				timeline := RepresentationTimeline{
+					Period:         periodName(pi, p.ID),
+					AdaptationSet:  ai,
+					Representation: stringValue(r.ID),
+					Segments:       []TimelineSegment{},
+					segmentBase:    segmentBase,
+				}
+				if r.Bandwidth != nil {
+					timeline.Bandwidth = *r.Bandwidth
+				}
+
+				for _, s := range segments {
+					start := periodStart + ticksToDuration(s.Time, timescale) - ticksToDuration(pto, timescale)
+					duration := ticksToDuration(s.Duration, timescale)
+					segment := TimelineSegment{
+						Number:   s.Number,
+						Start:    start.Seconds(),
+						Duration: duration.Seconds(),
+					}
+					if dynamic {
+						// availabilityTimeOffset makes segment available earlier, but doesn't affect availability end
+						nominal := ast.Add(start + duration)
+						switch {
+						case ato == nil:
+							segment.AvailabilityStart = &nominal
+						case !ato.IsInf():
+							availabilityStart := nominal.Add(-ato.Duration())
+							segment.AvailabilityStart = &availabilityStart
+						}
+						if m.TimeShiftBufferDepth != nil {
+							availabilityEnd := nominal.Add(tsbd + duration)
+							segment.AvailabilityEnd = &availabilityEnd
+						}
+					}
+					timeline.Segments = append(timeline.Segments, segment)
+				}
+				res = append(res, timeline)
+			}
+		}
+	}
+	return res, nil
+}
+
+// TimelineJSON encodes Timelines as JSON suitable for plotting segment gaps and drift.
+func (m *MPD) TimelineJSON() ([]byte, error) {
+	timelines, err := m.Timelines()
+	if err != nil {
+		return nil, err
+	}
+	if timelines == nil {
+		timelines = []RepresentationTimeline{}
+	}
+	return json.Marshal(timelines)
+}
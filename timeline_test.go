@@ -0,0 +1,88 @@
+package mpd
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriodStart(t *testing.T) {
+	m := &MPD{Period: []Period{
+		{Duration: stringPtr("PT10S")},
+		{Duration: stringPtr("PT5S")},
+		{Start: stringPtr("PT30S")},
+		{},
+	}}
+	for i, expected := range []time.Duration{0, 10 * time.Second, 30 * time.Second} {
+		start, err := m.PeriodStart(i)
+		require.NoError(t, err)
+		require.Equal(t, expected, start)
+	}
+	_, err := m.PeriodStart(3)
+	require.Error(t, err)
+	_, err = m.PeriodStart(4)
+	require.Error(t, err)
+}
+
+func TestTimelinesStatic(t *testing.T) {
+	m := decodeFixture(t, "fixture_elemental_delta_vod_multi_drm.mpd")
+	timelines, err := m.Timelines()
+	require.NoError(t, err)
+	require.NotEmpty(t, timelines)
+
+	tl := timelines[0]
+	require.Equal(t, "1", tl.Period)
+	require.Equal(t, "1", tl.Representation)
+	require.Equal(t, uint64(400000), tl.Bandwidth)
+	require.Len(t, tl.Segments, 18)
+	require.Equal(t, TimelineSegment{Number: 2, Start: 0, Duration: 8}, tl.Segments[0])
+	require.Equal(t, TimelineSegment{Number: 19, Start: 136, Duration: 0.68}, tl.Segments[17])
+}
+
+func TestTimelinesDynamic(t *testing.T) {
+	m := decodeFixture(t, "fixture_flussonic_live.mpd")
+	timelines, err := m.Timelines()
+	require.NoError(t, err)
+	require.Len(t, timelines, 5)
+
+	s := timelines[0].Segments[0]
+	require.Equal(t, uint64(219269), s.Number)
+	require.Equal(t, 380620.753, s.Start)
+	require.Equal(t, 8.0, s.Duration)
+	ast := time.Date(2021, 9, 17, 4, 42, 54, 0, time.UTC)
+	require.Equal(t, ast.Add(380628753*time.Millisecond), *s.AvailabilityStart)
+	require.Equal(t, ast.Add((380628753+136000+8000)*time.Millisecond), *s.AvailabilityEnd)
+
+	b, err := m.TimelineJSON()
+	require.NoError(t, err)
+	var decoded []RepresentationTimeline
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	require.Equal(t, "tracks-a1", decoded[4].Representation)
+	require.Len(t, decoded[4].Segments, 17)
+}
+
+func TestTimelinesAvailabilityTimeOffset(t *testing.T) {
+	m := decodeFixture(t, "fixture_flussonic_live.mpd")
+	ast := time.Date(2021, 9, 17, 4, 42, 54, 0, time.UTC)
+	ato := AvailabilityTimeOffset(7.5)
+	m.Period[0].AdaptationSets[0].Representations[0].SegmentTemplate.AvailabilityTimeOffset = &ato
+	inf := InfiniteAvailabilityTimeOffset
+	m.Period[0].AdaptationSets[0].Representations[1].SegmentTemplate.AvailabilityTimeOffset = &inf
+
+	timelines, err := m.Timelines()
+	require.NoError(t, err)
+
+	s := timelines[0].Segments[0]
+	require.Equal(t, ast.Add(380621253*time.Millisecond), *s.AvailabilityStart)
+	require.Equal(t, ast.Add((380628753+136000+8000)*time.Millisecond), *s.AvailabilityEnd)
+
+	s = timelines[1].Segments[0]
+	require.Nil(t, s.AvailabilityStart)
+	require.Equal(t, ast.Add((380628753+136000+8000)*time.Millisecond), *s.AvailabilityEnd)
+
+	p, err := m.LatencyParams()
+	require.NoError(t, err)
+	require.Equal(t, 7500*time.Millisecond, p.AvailabilityTimeOffset)
+}
@@ -0,0 +1,205 @@
+package mpd
+
+import "fmt"
+
+// RepresentationUpdate describes Representation which gained new segments in ApplyUpdate.
+type RepresentationUpdate struct {
+	Period         string
+	AdaptationSet  int
+	Representation string
+	NewSegments    []Segment
+}
+
+// ApplyUpdate merges refreshed dynamic MPD into m and returns Representations which gained new segments.
+//
+// MPD attributes and Periods are taken from update, update must not be used after the call.
+// Periods are matched by id, AdaptationSets and Representations are matched by id or by index if id is absent.
+// Matched AdaptationSets keep their pointers, so references held by the caller stay valid. Matched
+// Representations keep their pointers too, as long as they keep their positions within AdaptationSet and
+// the number of its Representations doesn't grow.
+// Segments of matched Representations which are no longer listed in update are preserved (SegmentTimeline
+// grows beyond the update window, trimming it is up to the caller) if numbering of segments is continuous.
+// Periods absent in update are removed.
+func (m *MPD) ApplyUpdate(update *MPD) ([]RepresentationUpdate, error) {
+	if update == nil {
+		return nil, fmt.Errorf("nil MPD update")
+	}
+	if m.ID != nil && update.ID != nil && *m.ID != *update.ID {
+		return nil, fmt.Errorf("MPD id changed from %q to %q", *m.ID, *update.ID)
+	}
+
+	prevPeriods := m.Period
+	*m = *update
+
+	var res []RepresentationUpdate
+	// SegmentTimeline inherited from AdaptationSet or Period is merged once
+	merged := map[*SegmentTemplate]bool{}
+	for pi := range m.Period {
+		p := &m.Period[pi]
+		prev := findPeriod(prevPeriods, pi, p.ID)
+
+		for ai, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			var prevAS *AdaptationSet
+			if prev != nil {
+				if found := findAdaptationSet(prev.AdaptationSets, ai, as.ID); found != nil {
+					// previous Representations are copied, their array may be reused for updated ones
+					prevContent := *found
+					prevContent.Representations = append([]Representation(nil), found.Representations...)
+					prevAS = &prevContent
+					prevReps := found.Representations
+					*found = *as
+					if reuseRepresentations(prevReps, as.Representations) {
+						found.Representations = prevReps[:len(as.Representations)]
+					}
+					p.AdaptationSets[ai] = found
+					as = found
+				}
+			}
+
+			for ri := range as.Representations {
+				r := &as.Representations[ri]
+				st := p.EffectiveSegmentTemplate(as, r)
+				if st == nil {
+					continue
+				}
+				var prevTemplate *SegmentTemplate
+				if prevAS != nil {
+					if prevR := findRepresentation(prevAS.Representations, ri, r.ID); prevR != nil {
+						prevTemplate = prev.EffectiveSegmentTemplate(prevAS, prevR)
+					}
+				}
+				owner := timelineOwner(p, as, r)
+				if merged[owner] {
+					owner = nil
+				} else if owner != nil {
+					merged[owner] = true
+				}
+				if added := mergeTimeline(prevTemplate, st, owner); len(added) > 0 {
+					res = append(res, RepresentationUpdate{
+						Period:         periodName(pi, p.ID),
+						AdaptationSet:  ai,
+						Representation: stringValue(r.ID),
+						NewSegments:    added,
+					})
+				}
+			}
+		}
+	}
+	return res, nil
+}
+
+func findPeriod(ps []Period, i int, id *string) *Period {
+	if id == nil {
+		if i < len(ps) && ps[i].ID == nil {
+			return &ps[i]
+		}
+		return nil
+	}
+	for j := range ps {
+		if ps[j].ID != nil && *ps[j].ID == *id {
+			return &ps[j]
+		}
+	}
+	return nil
+}
+
+func findAdaptationSet(sets []*AdaptationSet, i int, id *string) *AdaptationSet {
+	if id == nil {
+		if i < len(sets) && sets[i] != nil && sets[i].ID == nil {
+			return sets[i]
+		}
+		return nil
+	}
+	for _, as := range sets {
+		if as != nil && as.ID != nil && *as.ID == *id {
+			return as
+		}
+	}
+	return nil
+}
+
+func findRepresentation(rs []Representation, i int, id *string) *Representation {
+	if id == nil {
+		if i < len(rs) && rs[i].ID == nil {
+			return &rs[i]
+		}
+		return nil
+	}
+	for j := range rs {
+		if rs[j].ID != nil && *rs[j].ID == *id {
+			return &rs[j]
+		}
+	}
+	return nil
+}
+
+// reuseRepresentations copies next into the array of prev and reports true if every Representation of next
+// matched in prev has the same index and next fits into the array; otherwise prev is not changed.
+func reuseRepresentations(prev, next []Representation) bool {
+	if len(next) > cap(prev) {
+		return false
+	}
+	for i := range next {
+		if found := findRepresentation(prev, i, next[i].ID); found != nil && found != &prev[i] {
+			return false
+		}
+	}
+	copy(prev[:len(next)], next)
+	return true
+}
+
+// timelineOwner returns the SegmentTemplate defining SegmentTimeline of Representation r, nil if there is none.
+func timelineOwner(p *Period, as *AdaptationSet, r *Representation) *SegmentTemplate {
+	for _, st := range []*SegmentTemplate{r.SegmentTemplate, as.SegmentTemplate, p.SegmentTemplate} {
+		if st != nil && len(st.SegmentTimelineS) > 0 {
+			return st
+		}
+	}
+	return nil
+}
+
+// mergeTimeline returns segments of effective template st which are missing in prev one. Segments of prev
+// which are missing in st are prepended to the timeline of owner (the template defining st timeline),
+// nil owner is left as is.
+func mergeTimeline(prev, st, owner *SegmentTemplate) []Segment {
+	segments := st.Segments()
+	if prev == nil {
+		return segments
+	}
+	if prev.EffectiveTimescale() != st.EffectiveTimescale() ||
+		prev.EffectivePresentationTimeOffset() != st.EffectivePresentationTimeOffset() {
+		// media timeline changed, previous segments are not comparable
+		return segments
+	}
+
+	prevSegments := prev.Segments()
+	known := make(map[uint64]bool, len(prevSegments))
+	for _, s := range prevSegments {
+		known[s.Time] = true
+	}
+	var added []Segment
+	for _, s := range segments {
+		if !known[s.Time] {
+			added = append(added, s)
+		}
+	}
+
+	if len(segments) == 0 || hasNegativeRepeat(prev.SegmentTimelineS) || hasNegativeRepeat(st.SegmentTimelineS) {
+		return added
+	}
+	var kept []Segment
+	for _, s := range prevSegments {
+		if s.Time+s.Duration <= segments[0].Time {
+			kept = append(kept, s)
+		}
+	}
+	if owner != nil && len(kept) > 0 && kept[len(kept)-1].Number+1 == segments[0].Number {
+		startNumber := kept[0].Number
+		owner.StartNumber = &startNumber
+		owner.SegmentTimelineS = compactTimeline(append(kept, segments...), startNumber)
+	}
+	return added
+}
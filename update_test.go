@@ -0,0 +1,136 @@
+package mpd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func updateMPD(publishTime string, startNumber, t uint64, reps ...string) *MPD {
+	as := &AdaptationSet{MimeType: "video/mp4"}
+	for _, id := range reps {
+		as.Representations = append(as.Representations, Representation{
+			ID: stringPtr(id),
+			SegmentTemplate: &SegmentTemplate{
+				Timescale:        uint64Ptr(10),
+				StartNumber:      uint64Ptr(startNumber),
+				SegmentTimelineS: []SegmentTimelineS{{T: uint64Ptr(t), D: 10, R: int64Ptr(4)}},
+			},
+		})
+	}
+	return &MPD{
+		ID:          stringPtr("live"),
+		Type:        PresentationTypeOf(PresentationTypeDynamic),
+		PublishTime: dateTimePtr(publishTime),
+		Period:      []Period{{ID: stringPtr("1"), AdaptationSets: []*AdaptationSet{as}}},
+	}
+}
+
+func TestApplyUpdate(t *testing.T) {
+	m := updateMPD("2021-01-01T00:00:00Z", 1, 0, "v")
+	as := m.Period[0].AdaptationSets[0]
+
+	updates, err := m.ApplyUpdate(updateMPD("2021-01-01T00:00:02Z", 3, 20, "v", "a"))
+	require.NoError(t, err)
+	require.Equal(t, "2021-01-01T00:00:02Z", m.PublishTime.String())
+	require.True(t, as == m.Period[0].AdaptationSets[0])
+
+	require.Equal(t, []RepresentationUpdate{
+		{Period: "1", AdaptationSet: 0, Representation: "v", NewSegments: []Segment{
+			{Number: 6, Time: 50, Duration: 10},
+			{Number: 7, Time: 60, Duration: 10},
+		}},
+		{Period: "1", AdaptationSet: 0, Representation: "a", NewSegments: []Segment{
+			{Number: 3, Time: 20, Duration: 10},
+			{Number: 4, Time: 30, Duration: 10},
+			{Number: 5, Time: 40, Duration: 10},
+			{Number: 6, Time: 50, Duration: 10},
+			{Number: 7, Time: 60, Duration: 10},
+		}},
+	}, updates)
+
+	st := as.Representations[0].SegmentTemplate
+	require.Equal(t, uint64(1), *st.StartNumber)
+	require.Equal(t, []SegmentTimelineS{{T: uint64Ptr(0), D: 10, R: int64Ptr(6)}}, st.SegmentTimelineS)
+	require.Equal(t, uint64(3), *as.Representations[1].SegmentTemplate.StartNumber)
+
+	// nothing new
+	updates, err = m.ApplyUpdate(updateMPD("2021-01-01T00:00:03Z", 3, 20, "v", "a"))
+	require.NoError(t, err)
+	require.Empty(t, updates)
+	require.Equal(t, []SegmentTimelineS{{T: uint64Ptr(0), D: 10, R: int64Ptr(6)}},
+		m.Period[0].AdaptationSets[0].Representations[0].SegmentTemplate.SegmentTimelineS)
+
+	// numbering discontinuity, previous segments are dropped
+	updates, err = m.ApplyUpdate(updateMPD("2021-01-01T00:00:04Z", 100, 100, "v"))
+	require.NoError(t, err)
+	require.Len(t, updates, 1)
+	require.Len(t, updates[0].NewSegments, 5)
+	require.Equal(t, uint64(100), *m.Period[0].AdaptationSets[0].Representations[0].SegmentTemplate.StartNumber)
+
+	other := updateMPD("2021-01-01T00:00:05Z", 1, 0, "v")
+	other.ID = stringPtr("other")
+	_, err = m.ApplyUpdate(other)
+	require.EqualError(t, err, `MPD id changed from "live" to "other"`)
+	_, err = m.ApplyUpdate(nil)
+	require.EqualError(t, err, "nil MPD update")
+}
+
+func TestApplyUpdateRepresentationPointers(t *testing.T) {
+	m := updateMPD("2021-01-01T00:00:00Z", 1, 0, "v", "a")
+	v := &m.Period[0].AdaptationSets[0].Representations[0]
+	a := &m.Period[0].AdaptationSets[0].Representations[1]
+
+	updates, err := m.ApplyUpdate(updateMPD("2021-01-01T00:00:02Z", 3, 20, "v", "a"))
+	require.NoError(t, err)
+	require.Len(t, updates, 2)
+	require.Same(t, v, &m.Period[0].AdaptationSets[0].Representations[0])
+	require.Same(t, a, &m.Period[0].AdaptationSets[0].Representations[1])
+	require.Equal(t, []SegmentTimelineS{{T: uint64Ptr(0), D: 10, R: int64Ptr(6)}}, v.SegmentTemplate.SegmentTimelineS)
+
+	// Representation removed from the end
+	updates, err = m.ApplyUpdate(updateMPD("2021-01-01T00:00:03Z", 4, 30, "v"))
+	require.NoError(t, err)
+	require.Len(t, updates, 1)
+	require.Len(t, m.Period[0].AdaptationSets[0].Representations, 1)
+	require.Same(t, v, &m.Period[0].AdaptationSets[0].Representations[0])
+	require.Equal(t, []SegmentTimelineS{{T: uint64Ptr(0), D: 10, R: int64Ptr(7)}}, v.SegmentTemplate.SegmentTimelineS)
+
+	// Representation is added back, the array fits it
+	_, err = m.ApplyUpdate(updateMPD("2021-01-01T00:00:04Z", 4, 30, "v", "a"))
+	require.NoError(t, err)
+	require.Same(t, v, &m.Period[0].AdaptationSets[0].Representations[0])
+	require.Equal(t, "a", *a.ID)
+
+	// moved Representations get new pointers, previous ones are not changed
+	_, err = m.ApplyUpdate(updateMPD("2021-01-01T00:00:05Z", 5, 40, "a", "v"))
+	require.NoError(t, err)
+	require.Equal(t, "a", *m.Period[0].AdaptationSets[0].Representations[0].ID)
+	require.Equal(t, "v", *v.ID)
+	require.Equal(t, []SegmentTimelineS{{T: uint64Ptr(0), D: 10, R: int64Ptr(8)}},
+		m.Period[0].AdaptationSets[0].Representations[1].SegmentTemplate.SegmentTimelineS)
+}
+
+func TestApplyUpdateInheritedTimeline(t *testing.T) {
+	inherited := func(publishTime string, startNumber, t uint64) *MPD {
+		m := updateMPD(publishTime, startNumber, t, "v", "a")
+		as := m.Period[0].AdaptationSets[0]
+		as.SegmentTemplate = as.Representations[0].SegmentTemplate
+		for i := range as.Representations {
+			as.Representations[i].SegmentTemplate = nil
+		}
+		return m
+	}
+	m := inherited("2021-01-01T00:00:00Z", 1, 0)
+	updates, err := m.ApplyUpdate(inherited("2021-01-01T00:00:02Z", 3, 20))
+	require.NoError(t, err)
+	require.Len(t, updates, 2)
+	for _, u := range updates {
+		require.Equal(t, []Segment{{Number: 6, Time: 50, Duration: 10}, {Number: 7, Time: 60, Duration: 10}}, u.NewSegments)
+	}
+
+	st := m.Period[0].AdaptationSets[0].SegmentTemplate
+	require.Equal(t, uint64(1), *st.StartNumber)
+	require.Equal(t, []SegmentTimelineS{{T: uint64Ptr(0), D: 10, R: int64Ptr(6)}}, st.SegmentTimelineS)
+	require.Nil(t, m.Period[0].AdaptationSets[0].Representations[0].SegmentTemplate)
+}
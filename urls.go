@@ -0,0 +1,352 @@
+package mpd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Segment URL types.
+const (
+	SegmentURLInitialization = "initialization"
+	SegmentURLMedia          = "media"
+	SegmentURLIndex          = "index"
+)
+
+// SegmentURL describes single resolved segment URL.
+type SegmentURL struct {
+	Period         string `json:"period"`
+	Representation string `json:"representation"`
+	// Type is SegmentURLInitialization, SegmentURLIndex or SegmentURLMedia.
+	Type string `json:"type"`
+	// Index is a zero-based index of media segment within Representation.
+	Index  int    `json:"index"`
+	Number uint64 `json:"number,omitempty"`
+	// Time is a segment media time in timescale units ($Time$ value).
+	Time uint64 `json:"time,omitempty"`
+	// Start is a segment start in seconds from the start of the presentation.
+	Start float64 `json:"start,omitempty"`
+	// Duration is a segment duration in seconds.
+	Duration float64 `json:"duration,omitempty"`
+	URL      string  `json:"url"`
+	// ByteRange is a byte range of segment within URL (e.g. "0-861"), empty for the whole resource.
+	ByteRange string `json:"byteRange,omitempty"`
+}
+
+// resolveURL resolves reference against base; nil base returns reference as is.
+func resolveURL(base *url.URL, ref string) (*url.URL, error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return nil, err
+	}
+	if base == nil {
+		return u, nil
+	}
+	return base.ResolveReference(u), nil
+}
+
+// URLOptions configures segment URLs resolution.
+type URLOptions struct {
+	// PropagateQuery copies query parameters of manifest URL (e.g. CDN tokens) to segment URLs,
+	// parameters already present in segment URL are not overwritten.
+	PropagateQuery bool
+	// QueryParams limits propagated parameters to the given names, all parameters are propagated if empty.
+	QueryParams []string
+}
+
+// propagateQuery adds query parameters of manifest URL to u according to options. Segment URL query is kept
+// as is, missing parameters are appended in the manifest order with the manifest escaping.
+func (o URLOptions) propagateQuery(manifest, u *url.URL) {
+	if !o.PropagateQuery || manifest == nil || manifest.RawQuery == "" {
+		return
+	}
+	var allowed map[string]bool
+	if len(o.QueryParams) > 0 {
+		allowed = make(map[string]bool, len(o.QueryParams))
+		for _, name := range o.QueryParams {
+			allowed[name] = true
+		}
+	}
+
+	present := u.Query()
+	query := u.RawQuery
+	for _, pair := range strings.Split(manifest.RawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		name := pair
+		if i := strings.IndexByte(name, '='); i >= 0 {
+			name = name[:i]
+		}
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if _, ok := present[name]; ok || (allowed != nil && !allowed[name]) {
+			continue
+		}
+		// repeated parameter of manifest URL is propagated with all its values
+		if query != "" {
+			query += "&"
+		}
+		query += pair
+	}
+	u.RawQuery = query
+}
+
+// SegmentURLs resolves URLs of all initialization and media segments against manifestURL, which can be empty.
+func (m *MPD) SegmentURLs(manifestURL string) ([]SegmentURL, error) {
+	return m.SegmentURLsWithOptions(manifestURL, URLOptions{})
+}
+
+// SegmentURLsWithOptions is like SegmentURLs, but with resolution options.
+func (m *MPD) SegmentURLsWithOptions(manifestURL string, opts URLOptions) ([]SegmentURL, error) {
+	var manifest *url.URL
+	if manifestURL != "" {
+		var err error
+		if manifest, err = url.Parse(manifestURL); err != nil {
+			return nil, err
+		}
+	}
+
+	var res []SegmentURL
+	for pi, p := range m.Period {
+		periodStart, err := m.PeriodStart(pi)
+		if err != nil {
+			return nil, err
+		}
+		periodDuration, _, err := m.PeriodDuration(pi)
+		if err != nil {
+			return nil, err
+		}
+
+		for ai, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			for ri := range as.Representations {
+				r := &as.Representations[ri]
+				base, err := m.representationBaseURL(manifestURL, &p, as, r)
+				if err != nil {
+					return nil, err
+				}
+
+				values := TemplateValues{RepresentationID: stringValue(r.ID)}
+				if r.Bandwidth != nil {
+					values.Bandwidth = *r.Bandwidth
+				}
+				link := func(ref string) (string, error) {
+					u, err := resolveURL(base, ref)
+					if err != nil {
+						return "", err
+					}
+					opts.propagateQuery(manifest, u)
+					return u.String(), nil
+				}
+				resolve := func(template string) (string, error) {
+					s, err := ExpandTemplate(template, values)
+					if err != nil {
+						return "", err
+					}
+					return link(s)
+				}
+				// addURLType adds segment URL described by Initialization or RepresentationIndex element,
+				// the absent @sourceURL refers to BaseURL itself.
+				addURLType := func(typ string, ut *URLType) error {
+					u, err := link(stringValue(ut.SourceURL))
+					if err != nil {
+						return fmt.Errorf("%s: %s: %s", representationPath(pi, ai, ri), typ, err)
+					}
+					res = append(res, SegmentURL{
+						Period:         periodName(pi, p.ID),
+						Representation: values.RepresentationID,
+						Type:           typ,
+						URL:            u,
+						ByteRange:      stringValue(ut.Range),
+					})
+					return nil
+				}
+				start := func(t, pto, timescale uint64) float64 {
+					return (periodStart + ticksToDuration(t, timescale) - ticksToDuration(pto, timescale)).Seconds()
+				}
+
+				if st := p.EffectiveSegmentTemplate(as, r); st != nil {
+					if st.Initialization != nil {
+						u, err := resolve(*st.Initialization)
+						if err != nil {
+							return nil, fmt.Errorf("%s: initialization: %s", representationPath(pi, ai, ri), err)
+						}
+						res = append(res, SegmentURL{
+							Period:         periodName(pi, p.ID),
+							Representation: values.RepresentationID,
+							Type:           SegmentURLInitialization,
+							URL:            u,
+						})
+					} else if st.InitializationURL != nil {
+						if err = addURLType(SegmentURLInitialization, st.InitializationURL); err != nil {
+							return nil, err
+						}
+					}
+					if st.RepresentationIndex != nil {
+						if err = addURLType(SegmentURLIndex, st.RepresentationIndex); err != nil {
+							return nil, err
+						}
+					}
+
+					if st.Media == nil {
+						continue
+					}
+					timescale := st.EffectiveTimescale()
+					pto := st.EffectivePresentationTimeOffset()
+					for i, s := range st.SegmentsWithin(periodDuration) {
+						values.Number, values.Time = s.Number, s.Time
+						u, err := resolve(*st.Media)
+						if err != nil {
+							return nil, fmt.Errorf("%s: media: %s", representationPath(pi, ai, ri), err)
+						}
+						res = append(res, SegmentURL{
+							Period:         periodName(pi, p.ID),
+							Representation: values.RepresentationID,
+							Type:           SegmentURLMedia,
+							Index:          i,
+							Number:         s.Number,
+							Time:           s.Time,
+							Start:          start(s.Time, pto, timescale),
+							Duration:       ticksToDuration(s.Duration, timescale).Seconds(),
+							URL:            u,
+						})
+					}
+					continue
+				}
+
+				if sl := p.EffectiveSegmentList(as, r); sl != nil {
+					if sl.Initialization != nil {
+						if err = addURLType(SegmentURLInitialization, sl.Initialization); err != nil {
+							return nil, err
+						}
+					}
+					if sl.RepresentationIndex != nil {
+						if err = addURLType(SegmentURLIndex, sl.RepresentationIndex); err != nil {
+							return nil, err
+						}
+					}
+
+					timescale := sl.EffectiveTimescale()
+					pto := sl.EffectivePresentationTimeOffset()
+					segments := sl.Segments()
+					for i, su := range sl.SegmentURLs {
+						u, err := link(stringValue(su.Media))
+						if err != nil {
+							return nil, fmt.Errorf("%s: media: %s", representationPath(pi, ai, ri), err)
+						}
+						v := SegmentURL{
+							Period:         periodName(pi, p.ID),
+							Representation: values.RepresentationID,
+							Type:           SegmentURLMedia,
+							Index:          i,
+							URL:            u,
+							ByteRange:      stringValue(su.MediaRange),
+						}
+						if i < len(segments) {
+							s := segments[i]
+							v.Number, v.Time = s.Number, s.Time
+							v.Start = start(s.Time, pto, timescale)
+							v.Duration = ticksToDuration(s.Duration, timescale).Seconds()
+						}
+						res = append(res, v)
+					}
+					continue
+				}
+
+				if sb := p.EffectiveSegmentBase(as, r); sb != nil {
+					if sb.Initialization != nil {
+						if err = addURLType(SegmentURLInitialization, sb.Initialization); err != nil {
+							return nil, err
+						}
+					}
+					switch {
+					case sb.RepresentationIndex != nil:
+						if err = addURLType(SegmentURLIndex, sb.RepresentationIndex); err != nil {
+							return nil, err
+						}
+					case sb.IndexRange != nil:
+						if err = addURLType(SegmentURLIndex, &URLType{Range: sb.IndexRange}); err != nil {
+							return nil, err
+						}
+					}
+
+					// the whole Period is a single media segment referring to BaseURL itself
+					u, err := link("")
+					if err != nil {
+						return nil, fmt.Errorf("%s: media: %s", representationPath(pi, ai, ri), err)
+					}
+					res = append(res, SegmentURL{
+						Period:         periodName(pi, p.ID),
+						Representation: values.RepresentationID,
+						Type:           SegmentURLMedia,
+						Start:          periodStart.Seconds(),
+						Duration:       periodDuration.Seconds(),
+						URL:            u,
+					})
+				}
+			}
+		}
+	}
+	return res, nil
+}
+
+// WriteSegmentURLsCSV writes segment URLs as CSV with a header line.
+func WriteSegmentURLsCSV(w io.Writer, urls []SegmentURL) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"period", "representation", "type", "index", "number", "time", "start", "duration", "url", "byteRange"})
+	if err != nil {
+		return err
+	}
+	for _, u := range urls {
+		err = cw.Write([]string{
+			u.Period,
+			u.Representation,
+			u.Type,
+			strconv.Itoa(u.Index),
+			strconv.FormatUint(u.Number, 10),
+			strconv.FormatUint(u.Time, 10),
+			strconv.FormatFloat(u.Start, 'f', -1, 64),
+			strconv.FormatFloat(u.Duration, 'f', -1, 64),
+			u.URL,
+			u.ByteRange,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteSegmentURLsJSON writes segment URLs as JSON array.
+func WriteSegmentURLsJSON(w io.Writer, urls []SegmentURL) error {
+	if urls == nil {
+		urls = []SegmentURL{}
+	}
+	return json.NewEncoder(w).Encode(urls)
+}
+
+// RefreshURL returns URL to use for subsequent MPD refreshes: the first Location resolved against
+// manifestURL, or manifestURL itself if MPD has no Location.
+func (m *MPD) RefreshURL(manifestURL string) (string, error) {
+	if len(m.Location) == 0 {
+		return manifestURL, nil
+	}
+	base, err := url.Parse(manifestURL)
+	if err != nil {
+		return "", err
+	}
+	u, err := resolveURL(base, m.Location[0])
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
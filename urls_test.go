@@ -0,0 +1,262 @@
+package mpd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSegmentURLs(t *testing.T) {
+	m := decodeFixture(t, "fixture_vod_with_base_url.mpd")
+	urls, err := m.SegmentURLs("http://origin/live/manifest.mpd")
+	require.NoError(t, err)
+	require.Len(t, urls, 5*18)
+
+	require.Equal(t, SegmentURL{
+		Period:         "1631853774",
+		Representation: "tracks-v1",
+		Type:           SegmentURLInitialization,
+		URL:            "https://video-1-2/tracks-v1/init.m4v",
+	}, urls[0])
+	require.Equal(t, SegmentURL{
+		Period:         "1631853774",
+		Representation: "tracks-v1",
+		Type:           SegmentURLMedia,
+		Index:          1,
+		Number:         219270,
+		Time:           380628753,
+		Start:          380628.753,
+		Duration:       8,
+		URL:            "https://video-1-2/tracks-v1/seg-1631853774-219270.m4v?t=380628753",
+	}, urls[2])
+}
+
+func TestSegmentURLsDuration(t *testing.T) {
+	m := &MPD{
+		MediaPresentationDuration: stringPtr("PT5S"),
+		Period: []Period{{AdaptationSets: []*AdaptationSet{{Representations: []Representation{{
+			ID: stringPtr("v1"),
+			SegmentTemplate: &SegmentTemplate{
+				Timescale: uint64Ptr(1000),
+				Duration:  uint64Ptr(2000),
+				Media:     stringPtr("$RepresentationID$/$Number$.m4s"),
+			},
+		}}}}}},
+	}
+	urls, err := m.SegmentURLs("http://origin/vod/manifest.mpd")
+	require.NoError(t, err)
+	require.Len(t, urls, 3)
+	require.Equal(t, "http://origin/vod/v1/1.m4s", urls[0].URL)
+	require.Equal(t, SegmentURL{
+		Period:         "0",
+		Representation: "v1",
+		Type:           SegmentURLMedia,
+		Index:          2,
+		Number:         3,
+		Time:           4000,
+		Start:          4,
+		Duration:       1,
+		URL:            "http://origin/vod/v1/3.m4s",
+	}, urls[2])
+
+	timelines, err := m.Timelines()
+	require.NoError(t, err)
+	require.Len(t, timelines[0].Segments, 3)
+}
+
+func TestSegmentURLsSegmentList(t *testing.T) {
+	m := decodeFixture(t, "fixture_segment_list.mpd")
+	urls, err := m.SegmentURLs("http://origin/vod/manifest.mpd")
+	require.NoError(t, err)
+	require.Len(t, urls, 8)
+
+	// Initialization is inherited from AdaptationSet level
+	require.Equal(t, SegmentURL{
+		Period:         "0",
+		Representation: "v1",
+		Type:           SegmentURLInitialization,
+		URL:            "http://origin/vod/video/360p/video/init.mp4",
+	}, urls[0])
+	require.Equal(t, SegmentURL{
+		Period:         "0",
+		Representation: "v1",
+		Type:           SegmentURLMedia,
+		Index:          1,
+		Number:         2,
+		Time:           4000,
+		Start:          4,
+		Duration:       4,
+		URL:            "http://origin/vod/video/360p/seg-2.m4s",
+	}, urls[2])
+
+	require.Equal(t, SegmentURL{
+		Period:         "0",
+		Representation: "a1",
+		Type:           SegmentURLInitialization,
+		URL:            "http://origin/vod/audio.mp4",
+		ByteRange:      "0-861",
+	}, urls[4])
+	require.Equal(t, SegmentURL{
+		Period:         "0",
+		Representation: "a1",
+		Type:           SegmentURLMedia,
+		Index:          2,
+		Number:         3,
+		Time:           385024,
+		Start:          8,
+		Duration:       4,
+		URL:            "http://origin/vod/audio.mp4",
+		ByteRange:      "130001-195000",
+	}, urls[7])
+}
+
+func TestSegmentURLsSegmentBase(t *testing.T) {
+	m := decodeFixture(t, "fixture_segment_base.mpd")
+	urls, err := m.SegmentURLs("http://origin/vod/manifest.mpd")
+	require.NoError(t, err)
+	require.Len(t, urls, 6+2+158)
+
+	require.Equal(t, []SegmentURL{{
+		Period:         "0",
+		Representation: "v1",
+		Type:           SegmentURLInitialization,
+		URL:            "http://origin/vod/video_1080p.mp4",
+		ByteRange:      "0-899",
+	}, {
+		Period:         "0",
+		Representation: "v1",
+		Type:           SegmentURLIndex,
+		URL:            "http://origin/vod/video_1080p.mp4",
+		ByteRange:      "900-2695",
+	}, {
+		Period:         "0",
+		Representation: "v1",
+		Type:           SegmentURLMedia,
+		Duration:       632,
+		URL:            "http://origin/vod/video_1080p.mp4",
+	}, {
+		Period:         "0",
+		Representation: "v2",
+		Type:           SegmentURLInitialization,
+		URL:            "http://origin/vod/video_720p_init.mp4",
+	}, {
+		Period:         "0",
+		Representation: "v2",
+		Type:           SegmentURLIndex,
+		URL:            "http://origin/vod/video_720p.sidx",
+		ByteRange:      "0-1795",
+	}, {
+		Period:         "0",
+		Representation: "v2",
+		Type:           SegmentURLMedia,
+		Duration:       632,
+		URL:            "http://origin/vod/video_720p.mp4",
+	}}, urls[:6])
+
+	// SegmentTemplate with Initialization and RepresentationIndex elements
+	require.Equal(t, SegmentURL{
+		Period:         "0",
+		Representation: "a1",
+		Type:           SegmentURLInitialization,
+		URL:            "http://origin/vod/audio/init.mp4",
+		ByteRange:      "0-700",
+	}, urls[6])
+	require.Equal(t, SegmentURL{
+		Period:         "0",
+		Representation: "a1",
+		Type:           SegmentURLIndex,
+		URL:            "http://origin/vod/audio/index.sidx",
+	}, urls[7])
+	require.Equal(t, "http://origin/vod/audio/1.m4s", urls[8].URL)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteSegmentURLsCSV(&buf, urls[:2]))
+	require.Equal(t, "period,representation,type,index,number,time,start,duration,url,byteRange\n"+
+		"0,v1,initialization,0,0,0,0,0,http://origin/vod/video_1080p.mp4,0-899\n"+
+		"0,v1,index,0,0,0,0,0,http://origin/vod/video_1080p.mp4,900-2695\n", buf.String())
+}
+
+func TestSegmentURLsRelative(t *testing.T) {
+	m := decodeFixture(t, "fixture_elemental_delta_vod_multi_drm.mpd")
+	urls, err := m.SegmentURLs("http://origin/vod/1135124/manifest.mpd?token=1")
+	require.NoError(t, err)
+	require.Equal(t, "http://origin/vod/1135124/v1-video-249852_video_1_0_init.mp4?m=1598986491", urls[0].URL)
+	require.Equal(t, "http://origin/vod/1135124/v1-video-249852_video_1_0_2.mp4?m=1598986491", urls[1].URL)
+
+	urls, err = m.SegmentURLs("")
+	require.NoError(t, err)
+	require.Equal(t, "v1-video-249852_video_1_0_init.mp4?m=1598986491", urls[0].URL)
+}
+
+func TestSegmentURLsInvalidTemplate(t *testing.T) {
+	m := decodeFixture(t, "fixture_flussonic_live.mpd")
+	m.Period[0].AdaptationSets[1].Representations[0].SegmentTemplate.Media = stringPtr("$Foo$")
+	_, err := m.SegmentURLs("")
+	require.EqualError(t, err, "MPD/Period[0]/AdaptationSet[1]/Representation[0]: media: unknown identifier $Foo$ at position 0")
+}
+
+func TestWriteSegmentURLs(t *testing.T) {
+	urls := []SegmentURL{
+		{Period: "1", Representation: "v1", Type: SegmentURLInitialization, URL: "http://a/init.mp4"},
+		{Period: "1", Representation: "v1", Type: SegmentURLMedia, Number: 1, Start: 0, Duration: 2.5, URL: "http://a/1,2.mp4"},
+	}
+
+	b := new(bytes.Buffer)
+	require.NoError(t, WriteSegmentURLsCSV(b, urls))
+	require.Equal(t, strings.Join([]string{
+		"period,representation,type,index,number,time,start,duration,url,byteRange",
+		"1,v1,initialization,0,0,0,0,0,http://a/init.mp4,",
+		`1,v1,media,0,1,0,0,2.5,"http://a/1,2.mp4",`,
+		"",
+	}, "\n"), b.String())
+
+	b.Reset()
+	require.NoError(t, WriteSegmentURLsJSON(b, urls))
+	var decoded []SegmentURL
+	require.NoError(t, json.Unmarshal(b.Bytes(), &decoded))
+	require.Equal(t, urls, decoded)
+}
+
+func TestSegmentURLsPropagateQuery(t *testing.T) {
+	m := decodeFixture(t, "fixture_elemental_delta_vod_multi_drm.mpd")
+	manifestURL := "http://origin/vod/manifest.mpd?token=abc&m=1&exp=10"
+
+	urls, err := m.SegmentURLsWithOptions(manifestURL, URLOptions{PropagateQuery: true})
+	require.NoError(t, err)
+	require.Equal(t, "http://origin/vod/v1-video-249852_video_1_0_init.mp4?m=1598986491&token=abc&exp=10", urls[0].URL)
+
+	urls, err = m.SegmentURLsWithOptions(manifestURL, URLOptions{PropagateQuery: true, QueryParams: []string{"token", "sig"}})
+	require.NoError(t, err)
+	require.Equal(t, "http://origin/vod/v1-video-249852_video_1_0_2.mp4?m=1598986491&token=abc", urls[1].URL)
+
+	urls, err = m.SegmentURLsWithOptions(manifestURL, URLOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "http://origin/vod/v1-video-249852_video_1_0_init.mp4?m=1598986491", urls[0].URL)
+
+	// original escaping and order of both queries are kept, repeated parameters are propagated with all values
+	m.Period[0].AdaptationSets[0].Representations[0].SegmentTemplate.Initialization = stringPtr("init.mp4?z=1&a=%2F")
+	urls, err = m.SegmentURLsWithOptions("http://origin/vod/manifest.mpd?sig=a%2Bb%3D&a=2&k=1&k=2",
+		URLOptions{PropagateQuery: true})
+	require.NoError(t, err)
+	require.Equal(t, "http://origin/vod/init.mp4?z=1&a=%2F&sig=a%2Bb%3D&k=1&k=2", urls[0].URL)
+}
+
+func TestRefreshURL(t *testing.T) {
+	m := decodeFixture(t, "fixture_program_information.mpd")
+	u, err := m.RefreshURL("http://origin.example.com/live/manifest.mpd")
+	require.NoError(t, err)
+	require.Equal(t, "http://example.com/manifest.mpd", u)
+
+	m.Location = []string{"../other/manifest.mpd"}
+	u, err = m.RefreshURL("http://origin.example.com/live/manifest.mpd")
+	require.NoError(t, err)
+	require.Equal(t, "http://origin.example.com/other/manifest.mpd", u)
+
+	m.Location = nil
+	u, err = m.RefreshURL("http://origin.example.com/live/manifest.mpd")
+	require.NoError(t, err)
+	require.Equal(t, "http://origin.example.com/live/manifest.mpd", u)
+}
@@ -24,6 +24,14 @@ func UInt64(i *uint64) *uint64 {
 
 	return &cop
 }
+func Float64(f *float64) *float64 {
+	if f == nil {
+		return nil
+	}
+	cop := *f
+
+	return &cop
+}
 func Bool(b *bool) *bool {
 	if b == nil {
 		return nil
@@ -32,3 +40,22 @@ func Bool(b *bool) *bool {
 
 	return &cop
 }
+func UInt64s(s []uint64) []uint64 {
+	if s == nil {
+		return nil
+	}
+	cop := make([]uint64, len(s))
+	copy(cop, s)
+
+	return cop
+}
+
+func Strings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	cop := make([]string, len(s))
+	copy(cop, s)
+
+	return cop
+}
@@ -0,0 +1,163 @@
+package mpd
+
+import (
+	"fmt"
+)
+
+// Severity describes how serious a validation Finding is.
+type Severity string
+
+// Finding severities.
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Finding represents single problem found by Validate.
+type Finding struct {
+	Severity Severity
+	// Path points to the element with a problem, e.g. "MPD/Period[0]/AdaptationSet[1]/Representation[0]".
+	Path    string
+	Message string
+}
+
+// String implements fmt.Stringer.
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s", f.Severity, f.Path, f.Message)
+}
+
+// validators are run by Validate in order.
+var validators = []func(m *MPD) []Finding{
+	validateStructure,
+	validatePresentationTimeOffsets,
+	validateLatency,
+	validateTemplates,
+	validatePeriodAlignment,
+	validateDurations,
+	validateKIDs,
+	validateContentProtectionRefs,
+	validateContentTypes,
+	validateLangs,
+}
+
+// Validate checks MPD for problems and returns all findings; nil means no problems were found.
+func (m *MPD) Validate() []Finding {
+	var res []Finding
+	for _, v := range validators {
+		res = append(res, v(m)...)
+	}
+	return res
+}
+
+func periodPath(pi int) string {
+	return fmt.Sprintf("MPD/Period[%d]", pi)
+}
+
+func adaptationSetPath(pi, ai int) string {
+	return fmt.Sprintf("%s/AdaptationSet[%d]", periodPath(pi), ai)
+}
+
+func representationPath(pi, ai, ri int) string {
+	return fmt.Sprintf("%s/Representation[%d]", adaptationSetPath(pi, ai), ri)
+}
+
+// validateStructure checks mandatory attributes and elements.
+func validateStructure(m *MPD) []Finding {
+	var res []Finding
+	add := func(severity Severity, path, format string, args ...interface{}) {
+		res = append(res, Finding{Severity: severity, Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if m.Profiles == "" {
+		add(SeverityError, "MPD", "missing profiles attribute")
+	}
+	if m.MinBufferTime == nil {
+		add(SeverityError, "MPD", "missing minBufferTime attribute")
+	}
+	if m.Type != nil && *m.Type != PresentationTypeStatic && *m.Type != PresentationTypeDynamic {
+		add(SeverityError, "MPD", "unknown type %q", *m.Type)
+	}
+	if m.IsDynamic() && m.AvailabilityStartTime == nil {
+		add(SeverityError, "MPD", "dynamic MPD without availabilityStartTime attribute")
+	}
+	if m.AvailabilityStartTime != nil && m.AvailabilityEndTime != nil {
+		if !m.AvailabilityEndTime.After(m.AvailabilityStartTime.Time) {
+			add(SeverityError, "MPD", "availabilityEndTime %s is not after availabilityStartTime %s",
+				m.AvailabilityEndTime.value(), m.AvailabilityStartTime.value())
+		}
+	}
+	if len(m.Period) == 0 {
+		add(SeverityError, "MPD", "no Period elements")
+	}
+
+	initializationSets := map[uint64]bool{}
+	for _, is := range m.InitializationSet {
+		if is.ID != nil {
+			initializationSets[*is.ID] = true
+		}
+	}
+
+	checkTimeline := func(path string, st *SegmentTemplate) {
+		if st == nil {
+			return
+		}
+		for si, s := range st.SegmentTimelineS {
+			if s.D == 0 {
+				add(SeverityError, fmt.Sprintf("%s/SegmentTemplate/SegmentTimeline/S[%d]", path, si), "zero duration")
+			}
+		}
+	}
+
+	for pi, p := range m.Period {
+		checkTimeline(periodPath(pi), p.SegmentTemplate)
+		for ai, as := range p.AdaptationSets {
+			if as == nil {
+				continue
+			}
+			checkTimeline(adaptationSetPath(pi, ai), as.SegmentTemplate)
+			for _, id := range as.InitializationSetRef {
+				if !initializationSets[id] {
+					add(SeverityError, adaptationSetPath(pi, ai), "initializationSetRef %d refers to unknown InitializationSet", id)
+				}
+			}
+			// remote AdaptationSet content is resolved by client
+			if len(as.Representations) == 0 && as.XLinkHref == nil {
+				add(SeverityError, adaptationSetPath(pi, ai), "no Representation elements")
+			}
+			for ri, r := range as.Representations {
+				path := representationPath(pi, ai, ri)
+				if r.ID == nil || *r.ID == "" {
+					add(SeverityError, path, "missing id attribute")
+				}
+				if r.Bandwidth == nil {
+					add(SeverityError, path, "missing bandwidth attribute")
+				}
+				if r.SAR != nil {
+					if _, _, err := r.SAR.Values(); err != nil {
+						add(SeverityError, path, "%s", err)
+					}
+				}
+				if r.AudioSamplingRate != nil {
+					if _, _, err := r.AudioSamplingRate.Values(); err != nil {
+						add(SeverityError, path, "%s", err)
+					}
+				}
+				for _, c := range []struct {
+					name        string
+					v, min, max *uint64
+				}{
+					{"bandwidth", r.Bandwidth, as.MinBandwidth, as.MaxBandwidth},
+					{"width", r.Width, as.MinWidth, as.MaxWidth},
+					{"height", r.Height, as.MinHeight, as.MaxHeight},
+				} {
+					if c.v != nil && (c.min != nil && *c.v < *c.min || c.max != nil && *c.v > *c.max) {
+						add(SeverityWarning, path, "%s %d is out of AdaptationSet range", c.name, *c.v)
+					}
+				}
+				checkTimeline(path, r.SegmentTemplate)
+			}
+		}
+	}
+
+	return res
+}
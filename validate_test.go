@@ -0,0 +1,94 @@
+package mpd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFixtures(t *testing.T) {
+	for _, name := range []string{
+		"fixture_elemental_delta_live.mpd",
+		"fixture_elemental_delta_vod.mpd",
+		"fixture_elemental_delta_vod_multi_drm.mpd",
+		"fixture_flussonic_live.mpd",
+		"fixture_vod_with_base_url.mpd",
+		"fixture_program_information.mpd",
+		"fixture_segment_list.mpd",
+		"fixture_segment_base.mpd",
+		"fixture_segment_template_duration.mpd",
+		"fixture_adaptation_set_template.mpd",
+		"fixture_period_template.mpd",
+		"fixture_event_stream.mpd",
+		"fixture_inband_event_stream.mpd",
+		"fixture_metrics_reporting.mpd",
+		"fixture_content_component.mpd",
+		"fixture_frame_packing.mpd",
+		"fixture_descriptors.mpd",
+		"fixture_xlink.mpd",
+		"fixture_dependent_representations.mpd",
+		"fixture_base_url_attributes.mpd",
+		"fixture_low_latency.mpd",
+		"fixture_initialization_set.mpd",
+		"fixture_preselection.mpd",
+		"fixture_extensions.mpd",
+		"fixture_playready.mpd",
+		"fixture_clearkey.mpd",
+		"fixture_marlin.mpd",
+		"fixture_content_protection_ref.mpd",
+		"fixture_subset.mpd",
+	} {
+		m := decodeFixture(t, name)
+		require.Empty(t, m.Validate(), name)
+	}
+}
+
+func TestValidateStructure(t *testing.T) {
+	m := &MPD{
+		Type: PresentationTypeOf(PresentationTypeDynamic),
+		Period: []Period{{
+			AdaptationSets: []*AdaptationSet{
+				{},
+				{Representations: []Representation{{
+					SegmentTemplate: &SegmentTemplate{SegmentTimelineS: []SegmentTimelineS{{D: 0}}},
+				}}},
+			},
+		}},
+	}
+	require.Equal(t, []Finding{
+		{SeverityError, "MPD", "missing profiles attribute"},
+		{SeverityError, "MPD", "missing minBufferTime attribute"},
+		{SeverityError, "MPD", "dynamic MPD without availabilityStartTime attribute"},
+		{SeverityError, "MPD/Period[0]/AdaptationSet[0]", "no Representation elements"},
+		{SeverityError, "MPD/Period[0]/AdaptationSet[1]/Representation[0]", "missing id attribute"},
+		{SeverityError, "MPD/Period[0]/AdaptationSet[1]/Representation[0]", "missing bandwidth attribute"},
+		{SeverityError, "MPD/Period[0]/AdaptationSet[1]/Representation[0]/SegmentTemplate/SegmentTimeline/S[0]", "zero duration"},
+	}, m.Validate())
+}
+
+func TestValidateAvailabilityEndTime(t *testing.T) {
+	m := decodeFixture(t, "fixture_inband_event_stream.mpd")
+	m.AvailabilityEndTime = dateTimePtr("2020-12-31T23:00:00Z")
+	require.Equal(t, []Finding{
+		{SeverityError, "MPD", "availabilityEndTime 2020-12-31T23:00:00Z is not after availabilityStartTime 2021-01-01T00:00:00Z"},
+	}, m.Validate())
+}
+
+func TestValidateAdaptationSetRanges(t *testing.T) {
+	m := decodeFixture(t, "fixture_descriptors.mpd")
+	as := m.Period[0].AdaptationSets[0]
+	as.MinBandwidth, as.MaxBandwidth = uint64Ptr(500000), uint64Ptr(2000000)
+	as.MaxWidth, as.MaxHeight = uint64Ptr(1920), uint64Ptr(540)
+	require.Equal(t, []Finding{
+		{SeverityWarning, "MPD/Period[0]/AdaptationSet[0]/Representation[0]", "bandwidth 3000000 is out of AdaptationSet range"},
+		{SeverityWarning, "MPD/Period[0]/AdaptationSet[0]/Representation[0]", "height 720 is out of AdaptationSet range"},
+	}, m.Validate())
+}
+
+func TestValidateKIDs(t *testing.T) {
+	m := decodeFixture(t, "fixture_elemental_delta_vod_multi_drm.mpd")
+	m.Period[0].AdaptationSets[0].Representations[1].ContentProtections[0].CencDefaultKID = stringPtr("3BC6927192DE4ADC8110F4A4BBDD759C")
+	require.Equal(t, []Finding{
+		{SeverityError, "MPD/Period[0]/AdaptationSet[0]/Representation[1]/ContentProtection[0]@cenc:default_KID", `invalid KID "3BC6927192DE4ADC8110F4A4BBDD759C"`},
+	}, m.Validate())
+}
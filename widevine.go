@@ -0,0 +1,84 @@
+package mpd
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// WidevinePSSHData holds fields of Widevine PSSH data (WidevineCencHeader protobuf message).
+type WidevinePSSHData struct {
+	// KeyIDs are formatted like cenc:default_KID.
+	KeyIDs    []string
+	Provider  string
+	ContentID []byte
+	Policy    string
+	// ProtectionScheme is a four character code, e.g. "cenc" or "cbcs", empty if not set.
+	ProtectionScheme string
+}
+
+// Widevine parses data of Widevine PSSH box.
+func (b *PSSHBox) Widevine() (*WidevinePSSHData, error) {
+	if b.SystemID != WidevineSystemID {
+		return nil, fmt.Errorf("pssh box of system %s is not a Widevine one", b.SystemID)
+	}
+	return ParseWidevinePSSHData(b.Data)
+}
+
+// ParseWidevinePSSHData parses WidevineCencHeader protobuf message, unknown fields are skipped.
+func ParseWidevinePSSHData(b []byte) (*WidevinePSSHData, error) {
+	res := &WidevinePSSHData{}
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, fmt.Errorf("widevine pssh data: invalid field key")
+		}
+		b = b[n:]
+
+		field, wireType := key>>3, key&7
+		var value []byte
+		var number uint64
+		switch wireType {
+		case 0:
+			if number, n = binary.Uvarint(b); n <= 0 {
+				return nil, fmt.Errorf("widevine pssh data: invalid varint of field %d", field)
+			}
+			b = b[n:]
+		case 1, 5:
+			size := 8
+			if wireType == 5 {
+				size = 4
+			}
+			if len(b) < size {
+				return nil, fmt.Errorf("widevine pssh data: truncated field %d", field)
+			}
+			b = b[size:]
+		case 2:
+			size, n := binary.Uvarint(b)
+			if n <= 0 || size > uint64(len(b)-n) {
+				return nil, fmt.Errorf("widevine pssh data: truncated field %d", field)
+			}
+			value, b = b[n:n+int(size)], b[n+int(size):]
+		default:
+			return nil, fmt.Errorf("widevine pssh data: unsupported wire type %d of field %d", wireType, field)
+		}
+
+		switch {
+		case field == 2 && wireType == 2:
+			if len(value) != 16 {
+				return nil, fmt.Errorf("widevine pssh data: invalid key id size %d", len(value))
+			}
+			res.KeyIDs = append(res.KeyIDs, formatUUID(value))
+		case field == 3 && wireType == 2:
+			res.Provider = string(value)
+		case field == 4 && wireType == 2:
+			res.ContentID = value
+		case field == 6 && wireType == 2:
+			res.Policy = string(value)
+		case field == 9 && wireType == 0:
+			var fourCC [4]byte
+			binary.BigEndian.PutUint32(fourCC[:], uint32(number))
+			res.ProtectionScheme = string(fourCC[:])
+		}
+	}
+	return res, nil
+}
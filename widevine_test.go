@@ -0,0 +1,40 @@
+package mpd
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWidevinePSSHData(t *testing.T) {
+	m := decodeFixture(t, "fixture_elemental_delta_live.mpd")
+	box, err := m.Period[0].AdaptationSets[0].Representations[0].ContentProtections[1].Pssh.Box()
+	require.NoError(t, err)
+	data, err := box.Widevine()
+	require.NoError(t, err)
+	require.Equal(t, &WidevinePSSHData{ContentID: []byte("wv-1593507197702836665"), ProtectionScheme: "cenc"}, data)
+
+	_, err = (&PSSHBox{SystemID: PlayReadySystemID}).Widevine()
+	require.EqualError(t, err, "pssh box of system 9a04f079-9840-4286-ab92-e65be0885f95 is not a Widevine one")
+}
+
+func TestParseWidevinePSSHData(t *testing.T) {
+	b, err := hex.DecodeString("0801" + "1210" + "10000000100010001000100000000001" + "1210" + "20000000200020002000200000000002" +
+		"1a0477696465" + "2203616263" + "3200")
+	require.NoError(t, err)
+	data, err := ParseWidevinePSSHData(b)
+	require.NoError(t, err)
+	require.Equal(t, &WidevinePSSHData{
+		KeyIDs:    []string{"10000000-1000-1000-1000-100000000001", "20000000-2000-2000-2000-200000000002"},
+		Provider:  "wide",
+		ContentID: []byte("abc"),
+	}, data)
+
+	_, err = ParseWidevinePSSHData(b[:10])
+	require.EqualError(t, err, "widevine pssh data: truncated field 2")
+	_, err = ParseWidevinePSSHData([]byte{0x12, 0x01, 0x00})
+	require.EqualError(t, err, "widevine pssh data: invalid key id size 1")
+	_, err = ParseWidevinePSSHData([]byte{0x0b})
+	require.EqualError(t, err, "widevine pssh data: unsupported wire type 3 of field 1")
+}